@@ -0,0 +1,120 @@
+package accounts
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"db"
+	"exports"
+	"helpers"
+	"media"
+	mr "models/request"
+)
+
+/* DeleteAccount deletes an user with all its data and its files */
+func DeleteAccount(user mr.User) error {
+	var references []string
+
+	// The images of the tweets, including the scheduled ones and the drafts, are collected before the tweets are deleted
+	tweets, err := db.DbConn.GetUserTweets(user.Id)
+
+	if err != nil {
+		return err
+	}
+
+	for _, tweet := range tweets {
+		references = append(references, tweet.Media...)
+	}
+
+	scheduledTweets, _, err := db.DbConn.GetScheduledTweets(user.Id, 1, 0)
+
+	if err != nil {
+		return err
+	}
+
+	for _, scheduled := range scheduledTweets {
+		references = append(references, scheduled.Media...)
+	}
+
+	drafts, _, err := db.DbConn.GetDrafts(user.Id, 1, 0)
+
+	if err != nil {
+		return err
+	}
+
+	for _, draft := range drafts {
+		references = append(references, draft.Media...)
+	}
+
+	err = db.DbConn.DeleteUser(user.Id)
+
+	if err != nil {
+		return err
+	}
+
+	exports.DeleteUserExports(user.Id)
+	media.Delete(references)
+
+	// The files are removed after the registries, so a failure here doesn't leave a partially deleted user
+	isRemote, _ := strconv.ParseBool(os.Getenv("FILES_REMOTE"))
+
+	for tag, filename := range map[string]string{"avatar": user.Avatar, "banner": user.Banner} {
+		if len(filename) < 1 {
+			continue
+		}
+
+		if isRemote {
+			err = helpers.DestroyRemote(fmt.Sprintf("%s-%s", user.Id, tag))
+		} else {
+			err = os.Remove(fmt.Sprintf("uploads/%ss/%s", tag, filename))
+		}
+
+		if err != nil && !os.IsNotExist(err) {
+			log.Printf("Error removing the %s of the user %s: %s", tag, user.Id, err.Error())
+		}
+	}
+
+	return nil
+}
+
+/* DeleteScheduledAccounts deletes the accounts whose grace period has ended */
+func DeleteScheduledAccounts() error {
+	users, err := db.DbConn.GetUsersToDelete(time.Now())
+
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		err = DeleteAccount(*user)
+
+		// A failed account is retried in the next run, without stopping the rest
+		if err != nil {
+			log.Printf("Error deleting the account %s: %s", user.Id, err.Error())
+		}
+	}
+
+	return nil
+}
+
+/* StartDeletionJob deletes periodically the accounts whose grace period has ended */
+func StartDeletionJob() {
+	interval := helpers.GetDurationEnv("ACCOUNT_DELETION_INTERVAL", time.Hour)
+
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	go func() {
+		for range time.Tick(interval) {
+			err := DeleteScheduledAccounts()
+
+			if err != nil {
+				log.Println("Error deleting the scheduled accounts: " + err.Error())
+			}
+		}
+	}()
+}
@@ -0,0 +1,113 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"db"
+	"helpers"
+	req "models/request"
+	"throttle"
+)
+
+// region "Actions"
+
+/* UnlockLogin removes the failed login attempts of an account and/or an IP address */
+func UnlockLogin(w http.ResponseWriter, r *http.Request) {
+	var keys []string
+
+	email := r.URL.Query().Get("email")
+	ip := r.URL.Query().Get("ip")
+
+	if len(email) > 0 {
+		keys = append(keys, throttle.AccountKey(email))
+	}
+
+	if len(ip) > 0 {
+		keys = append(keys, throttle.IpKey(ip))
+	}
+
+	if len(keys) < 1 {
+		http.Error(w, "The email or the ip param is required", http.StatusBadRequest)
+
+		return
+	}
+
+	err := throttle.Reset(keys...)
+
+	if err != nil {
+		http.Error(w, "An error has occurred when trying to unlock the login: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/* SetRole sets the role of an user */
+func SetRole(w http.ResponseWriter, r *http.Request) {
+	var roleChange req.RoleChange
+
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	principal := helpers.GetPrincipal(r)
+	err := json.NewDecoder(r.Body).Decode(&roleChange)
+
+	if err != nil {
+		http.Error(w, "Invalid data: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	if !isRole(roleChange.Role) {
+		http.Error(w, "Invalid role: "+roleChange.Role, http.StatusBadRequest)
+
+		return
+	}
+
+	// It avoids that the last administrator loses the access by mistake
+	if id == principal.Id {
+		http.Error(w, "The own role cannot be changed", http.StatusBadRequest)
+
+		return
+	}
+
+	_, isFound, err := db.DbConn.GetProfile(id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find a registry in the DB: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound {
+		http.Error(w, "No registry found in the DB", http.StatusNotFound)
+
+		return
+	}
+
+	err = db.DbConn.SetRole(id, roleChange.Role)
+
+	if err != nil {
+		http.Error(w, "An error has occurred when trying to set the role: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// endregion
+
+// region "Helpers"
+
+func isRole(role string) bool {
+	for _, r := range req.Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// endregion
@@ -0,0 +1,3 @@
+module controllers/admin
+
+go 1.19
@@ -0,0 +1,3 @@
+module controllers/keys
+
+go 1.19
@@ -0,0 +1,18 @@
+package keys
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"jwt"
+)
+
+/* GetJwks returns the public keys used to verify the tokens */
+func GetJwks(w http.ResponseWriter, r *http.Request) {
+	response := jwt.GetJWKS()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(response)
+}
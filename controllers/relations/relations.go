@@ -8,8 +8,8 @@ import (
 	"strings"
 
 	"db"
+	"embeds"
 	"helpers"
-	"jwt"
 	req "models/request"
 	res "models/response"
 )
@@ -19,14 +19,15 @@ import (
 /* Creates creates a new relation between two users */
 func Create(w http.ResponseWriter, r *http.Request) {
 	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	principal := helpers.GetPrincipal(r)
 
-	if jwt.UserId == id {
+	if principal.Id == id {
 		http.Error(w, fmt.Sprintf("Error: %s", "relation with oneself not allowed (userId and userRelationId are the same)"), http.StatusBadRequest)
 
 		return
 	}
 
-	relation, err := getRelationRequestModel(id)
+	relation, err := getRelationRequestModel(principal.Id, id)
 
 	if err != nil {
 		http.Error(w, "Error: "+err.Error(), http.StatusInternalServerError)
@@ -55,7 +56,8 @@ func Create(w http.ResponseWriter, r *http.Request) {
 /* Delete deletes a relation */
 func Delete(w http.ResponseWriter, r *http.Request) {
 	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
-	relation, err := getRelationRequestModel(id)
+	principal := helpers.GetPrincipal(r)
+	relation, err := getRelationRequestModel(principal.Id, id)
 
 	if err != nil {
 		http.Error(w, "Error: "+err.Error(), http.StatusInternalServerError)
@@ -79,7 +81,8 @@ func IsRelation(w http.ResponseWriter, r *http.Request) {
 	var isRelation bool
 
 	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
-	relation, err := getRelationRequestModel(id)
+	principal := helpers.GetPrincipal(r)
+	relation, err := getRelationRequestModel(principal.Id, id)
 
 	if err != nil {
 		http.Error(w, "Error: "+err.Error(), http.StatusInternalServerError)
@@ -117,20 +120,21 @@ func GetUsers(w http.ResponseWriter, r *http.Request) {
 	limit := r.Context().Value(helpers.RequestLimitKey{}).(int64)
 	search := r.URL.Query().Get("search")
 	searchType := r.URL.Query().Get("type")
+	principal := helpers.GetPrincipal(r)
 
 	switch searchType {
 
 	case "new":
-		results, total, err = db.DbConn.GetNotFollowing(jwt.UserId, page, limit, search)
+		results, total, err = db.DbConn.GetNotFollowing(principal.Id, page, limit, search)
 	case "follow":
-		results, total, err = db.DbConn.GetFollowing(jwt.UserId, page, limit, search)
+		results, total, err = db.DbConn.GetFollowing(principal.Id, page, limit, search)
 	default:
 		http.Error(w, "Invalid type param value. It has to be \"follow\" or \"new\"", http.StatusBadRequest)
 
 		return
 	}
 
-	// results, total, err = db.DbConn.GetUsers(jwt.UserId, page, limit, search, searchType)
+	// results, total, err = db.DbConn.GetUsers(principal.Id, page, limit, search, searchType)
 
 	if err != nil {
 		http.Error(w, "Error getting the users: "+err.Error(), http.StatusInternalServerError)
@@ -156,6 +160,7 @@ func GetFollowingTweets(w http.ResponseWriter, r *http.Request) {
 	page := r.Context().Value(helpers.RequestPageKey{}).(int64)
 	limit := r.Context().Value(helpers.RequestLimitKey{}).(int64)
 	onlyTweets := r.URL.Query().Get("onlytweets")
+	principal := helpers.GetPrincipal(r)
 
 	if len(onlyTweets) < 1 {
 		onlyTweets = "false"
@@ -169,7 +174,7 @@ func GetFollowingTweets(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	results, total, err := db.DbConn.GetFollowingTweets(jwt.UserId, page, limit, isOnlyTweets)
+	results, total, err := db.DbConn.GetFollowingTweets(principal.Id, page, limit, isOnlyTweets)
 
 	if err != nil {
 		http.Error(w, "Error getting the tweets: "+err.Error(), http.StatusInternalServerError)
@@ -183,17 +188,37 @@ func GetFollowingTweets(w http.ResponseWriter, r *http.Request) {
 	//w.WriteHeader(http.StatusOK)
 
 	if isOnlyTweets {
+		tweets := results.([]*req.Tweet)
+		err = embeds.SetOriginals(tweets)
+
+		if err == nil {
+			err = embeds.SetLikedByMe(principal.Id, tweets)
+		}
+
 		response = res.TweetsResponse{
-			Tweets: results.([]*req.Tweet),
+			Tweets: tweets,
 			Total:  total,
 		}
 	} else {
+		userTweets := results.([]*req.UserTweet)
+		err = embeds.SetUserTweetOriginals(userTweets)
+
+		if err == nil {
+			err = embeds.SetUserTweetLikedByMe(principal.Id, userTweets)
+		}
+
 		response = res.UserTweetsResponse{
-			Tweets: results.([]*req.UserTweet),
+			Tweets: userTweets,
 			Total:  total,
 		}
 	}
 
+	if err != nil {
+		http.Error(w, "Error getting the tweets: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
 	json.NewEncoder(w).Encode(response)
 }
 
@@ -201,10 +226,10 @@ func GetFollowingTweets(w http.ResponseWriter, r *http.Request) {
 
 // region "Helpers"
 
-func getRelationRequestModel(userRelationId string) (req.Relation, error) {
+func getRelationRequestModel(userId string, userRelationId string) (req.Relation, error) {
 	var relation req.Relation
 
-	relation.UserId = jwt.UserId
+	relation.UserId = userId
 	relation.UserRelationId = userRelationId
 	relation.Active = true
 
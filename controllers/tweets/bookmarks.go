@@ -0,0 +1,127 @@
+package tweets
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"db"
+	"embeds"
+	"helpers"
+	req "models/request"
+	res "models/response"
+)
+
+/* Bookmark privately saves a tweet in the user's bookmarks */
+func Bookmark(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	principal := helpers.GetPrincipal(r)
+	tweet, isFound := getReferencedOriginal(w, id)
+
+	if !isFound {
+		return
+	}
+
+	registry := req.Bookmark{
+		UserId:  principal.Id,
+		TweetId: tweet.Id,
+		Date:    time.Now(),
+	}
+
+	isCreated, err := db.DbConn.InsertBookmark(registry)
+
+	if err != nil {
+		http.Error(w, "An error occurred trying to insert a new registry into the DB: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isCreated {
+		http.Error(w, "The tweet has already been bookmarked", http.StatusConflict)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+/* DeleteBookmark removes a tweet from the user's bookmarks, even if the tweet has been deleted */
+func DeleteBookmark(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	principal := helpers.GetPrincipal(r)
+	originalId, err := getStoredOriginalId(id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find the tweet: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	isFound, err := db.DbConn.DeleteBookmark(principal.Id, originalId)
+
+	if err != nil {
+		http.Error(w, "An error occurred trying to delete the bookmark: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound {
+		http.Error(w, "The tweet has not been bookmarked", http.StatusNotFound)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/* GetBookmarkedTweets gets the tweets bookmarked by the user, the last bookmarked first */
+func GetBookmarkedTweets(w http.ResponseWriter, r *http.Request) {
+	var available []*req.Tweet
+
+	page := r.Context().Value(helpers.RequestPageKey{}).(int64)
+	limit := r.Context().Value(helpers.RequestLimitKey{}).(int64)
+	principal := helpers.GetPrincipal(r)
+
+	results, total, err := db.DbConn.GetBookmarkedTweets(principal.Id, page, limit)
+
+	if err != nil {
+		http.Error(w, "An error has happened trying to get the bookmarked tweets from the DB "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	// The deleted tweets keep their place in the bookmarks without their content
+	for i, tweet := range results {
+		if tweet.Active {
+			available = append(available, tweet)
+		} else {
+			results[i] = &req.Tweet{Id: tweet.Id, Unavailable: true}
+		}
+	}
+
+	err = embeds.SetOriginals(available)
+
+	if err == nil {
+		err = embeds.SetLikedByMe(principal.Id, available)
+	}
+
+	if err != nil {
+		http.Error(w, "An error has happened trying to get the bookmarked tweets from the DB "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if results == nil {
+		results = []*req.Tweet{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := res.TweetsResponse{
+		Tweets: results,
+		Total:  total,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,302 @@
+package tweets
+
+import (
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"db"
+	"helpers"
+	"media"
+	req "models/request"
+	res "models/response"
+)
+
+/* InsertDraft saves a tweet without publishing it */
+func InsertDraft(w http.ResponseWriter, r *http.Request) {
+	principal := helpers.GetPrincipal(r)
+	tweet, headers, err := decodeTweet(r)
+
+	if err != nil {
+		http.Error(w, "Invalid data: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	draft, isValid := prepareDraft(w, principal.Id, tweet, nil, headers)
+
+	if !isValid {
+		return
+	}
+
+	draft.Id, err = db.DbConn.InsertDraft(draft)
+
+	if err != nil {
+		media.Delete(draft.Media)
+
+		http.Error(w, "An error occurred trying to insert the draft into the DB: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	json.NewEncoder(w).Encode(draft)
+}
+
+/* GetDrafts gets the drafts of the user, the last saved first */
+func GetDrafts(w http.ResponseWriter, r *http.Request) {
+	page := r.Context().Value(helpers.RequestPageKey{}).(int64)
+	limit := r.Context().Value(helpers.RequestLimitKey{}).(int64)
+	principal := helpers.GetPrincipal(r)
+	results, total, err := db.DbConn.GetDrafts(principal.Id, page, limit)
+
+	if err != nil {
+		http.Error(w, "An error has happened trying to get the drafts from the DB "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if results == nil {
+		results = []*req.Draft{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := res.DraftsResponse{
+		Drafts: results,
+		Total:  total,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+/* ModifyDraft replaces the content of a draft of the user, the images not sent back are removed */
+func ModifyDraft(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	principal := helpers.GetPrincipal(r)
+	tweet, headers, err := decodeTweet(r)
+
+	if err != nil {
+		http.Error(w, "Invalid data: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	current, isFound, err := db.DbConn.GetDraft(id, principal.Id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find the draft: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound {
+		http.Error(w, "Draft not found", http.StatusNotFound)
+
+		return
+	}
+
+	draft, isValid := prepareDraft(w, principal.Id, tweet, current.Media, headers)
+
+	if !isValid {
+		return
+	}
+
+	draft.Id = current.Id
+	isFound, err = db.DbConn.ModifyDraft(draft)
+
+	if err != nil || !isFound {
+		media.Delete(getRemovedMedia(draft.Media, current.Media))
+
+		if err != nil {
+			http.Error(w, "An error occurred trying to modify the draft: "+err.Error(), http.StatusInternalServerError)
+		} else {
+			http.Error(w, "Draft not found", http.StatusNotFound)
+		}
+
+		return
+	}
+
+	media.Delete(getRemovedMedia(current.Media, draft.Media))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/* DeleteDraft deletes a draft of the user with its images */
+func DeleteDraft(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	principal := helpers.GetPrincipal(r)
+	draft, isFound, err := db.DbConn.DeleteDraft(id, principal.Id)
+
+	if err != nil {
+		http.Error(w, "An error occurred trying to delete the draft: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound {
+		http.Error(w, "Draft not found", http.StatusNotFound)
+
+		return
+	}
+
+	media.Delete(draft.Media)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/* PublishDraft turns a draft of the user into a tweet, validated as a new one */
+func PublishDraft(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	principal := helpers.GetPrincipal(r)
+	draft, isFound, err := db.DbConn.GetDraft(id, principal.Id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find the draft: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound {
+		http.Error(w, "Draft not found", http.StatusNotFound)
+
+		return
+	}
+
+	tweet := req.Tweet{
+		Message:       draft.Message,
+		InReplyTo:     draft.InReplyTo,
+		QuotedTweetId: draft.QuotedTweetId,
+		Media:         draft.Media,
+	}
+	registry, isValid := prepareTweet(w, principal.Id, tweet, nil)
+
+	if !isValid {
+		return
+	}
+
+	// The draft is deleted with the insertion, so it can only be published once
+	_, isPublished, err := db.DbConn.PublishDraft(id, principal.Id, registry)
+
+	if err != nil {
+		http.Error(w, "An error occurred trying to publish the draft: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isPublished {
+		http.Error(w, "Draft not found", http.StatusNotFound)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+/* GetDraftMedia gets an image of a draft of the user */
+func GetDraftMedia(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	index := getMediaIndex(r)
+	principal := helpers.GetPrincipal(r)
+	draft, isFound, err := db.DbConn.GetDraft(id, principal.Id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find the draft: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound || index >= len(draft.Media) {
+		http.Error(w, "Image not found", http.StatusNotFound)
+
+		return
+	}
+
+	setMediaToResponse(w, draft.Media[index])
+}
+
+// region "Helpers"
+
+/* prepareDraft validates the content of a draft and uploads its new images, writing the error response if it is not valid */
+func prepareDraft(w http.ResponseWriter, userId string, tweet req.Tweet, current []string, headers []*multipart.FileHeader) (req.Draft, bool) {
+	draft := req.Draft{
+		UserId:        userId,
+		Message:       tweet.Message,
+		InReplyTo:     tweet.InReplyTo,
+		QuotedTweetId: tweet.QuotedTweetId,
+		Date:          time.Now(),
+	}
+
+	// Only the images already in the draft can be kept
+	for _, reference := range tweet.Media {
+		if len(getRemovedMedia([]string{reference}, current)) > 0 {
+			http.Error(w, "The image "+reference+" is not in the draft", http.StatusBadRequest)
+
+			return draft, false
+		}
+	}
+
+	if len(tweet.Message) < 1 && len(tweet.Media)+len(headers) < 1 {
+		http.Error(w, "The draft cannot be empty", http.StatusBadRequest)
+
+		return draft, false
+	}
+
+	err := media.Validate(headers, len(tweet.Media))
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return draft, false
+	}
+
+	// The references are checked again when the draft is published
+	for _, id := range []string{tweet.InReplyTo, tweet.QuotedTweetId} {
+		if len(id) > 0 {
+			if _, isFound := getReferencedTweet(w, id); !isFound {
+				return draft, false
+			}
+		}
+	}
+
+	draft.Media = tweet.Media
+
+	if len(headers) > 0 {
+		references, err := media.Upload(userId, headers)
+
+		if err != nil {
+			http.Error(w, "An error occurred trying to upload the images: "+err.Error(), http.StatusInternalServerError)
+
+			return draft, false
+		}
+
+		draft.Media = append(draft.Media, references...)
+	}
+
+	return draft, true
+}
+
+/* getRemovedMedia returns the images of a list which are not in another one */
+func getRemovedMedia(references []string, kept []string) []string {
+	var results []string
+
+	isKept := make(map[string]bool)
+
+	for _, reference := range kept {
+		isKept[reference] = true
+	}
+
+	for _, reference := range references {
+		if !isKept[reference] {
+			results = append(results, reference)
+		}
+	}
+
+	return results
+}
+
+// endregion
@@ -2,20 +2,74 @@ package tweets
 
 import (
 	"encoding/json"
+	"mime/multipart"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	fc "controllers/files"
 	"db"
+	"embeds"
 	"helpers"
-	"jwt"
+	"media"
 	req "models/request"
 	res "models/response"
 )
 
-/* InsertTweet creates a tweet in the DB */
+/* InsertTweet creates a tweet in the DB, or schedules it when it has a publication date */
 func Insert(w http.ResponseWriter, r *http.Request) {
+	principal := helpers.GetPrincipal(r)
+	tweet, headers, err := decodeTweet(r)
+
+	if err != nil {
+		http.Error(w, "Invalid data: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	// The images of a new tweet can only be uploaded with it
+	tweet.Media = nil
+	registry, isValid := prepareTweet(w, principal.Id, tweet, headers)
+
+	if !isValid {
+		return
+	}
+
+	// The scheduled tweets are kept apart until the scheduler publishes them
+	if tweet.PublishAt != nil {
+		_, err = db.DbConn.InsertScheduledTweet(req.ScheduledTweet{
+			UserId:         registry.UserId,
+			Message:        registry.Message,
+			InReplyTo:      registry.InReplyTo,
+			ConversationId: registry.ConversationId,
+			QuotedTweetId:  registry.QuotedTweetId,
+			Media:          registry.Media,
+			PublishAt:      tweet.PublishAt.UTC(),
+			Date:           registry.Date,
+		})
+	} else {
+		_, err = db.DbConn.InsertTweet(registry)
+	}
+
+	if err != nil {
+		media.Delete(registry.Media)
+
+		http.Error(w, "An error occurred trying to insert a new registry into the DB: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+/* Modify changes the message of an user's tweet during the edit window */
+func Modify(w http.ResponseWriter, r *http.Request) {
 	var tweet req.Tweet
 
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	principal := helpers.GetPrincipal(r)
 	err := json.NewDecoder(r.Body).Decode(&tweet)
 
 	if err != nil {
@@ -30,14 +84,171 @@ func Insert(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	current, isFound, err := db.DbConn.GetTweet(id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find the tweet: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound || !current.Active || current.UserId != principal.Id {
+		http.Error(w, "Tweet not found", http.StatusNotFound)
+
+		return
+	}
+
+	if len(current.RetweetOf) > 0 {
+		http.Error(w, "A retweet cannot be edited", http.StatusBadRequest)
+
+		return
+	}
+
+	window := helpers.GetDurationEnv("TWEET_EDIT_WINDOW", 30*time.Minute)
+
+	if time.Since(current.Date) > window {
+		http.Error(w, "The tweet can no longer be edited", http.StatusForbidden)
+
+		return
+	}
+
+	editedAt := time.Now()
 	registry := req.Tweet{
-		UserId:  jwt.UserId,
-		Message: tweet.Message,
+		Id:       current.Id,
+		UserId:   principal.Id,
+		Message:  tweet.Message,
+		EditedAt: &editedAt,
+	}
+
+	isModified, err := db.DbConn.ModifyTweet(registry)
+
+	if err != nil {
+		http.Error(w, "An error has occurred when trying to modify the tweet: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isModified {
+		http.Error(w, "Tweet not found", http.StatusNotFound)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/* GetHistory gets the previous messages of a tweet */
+func GetHistory(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	tweet, isFound, err := db.DbConn.GetTweet(id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find the tweet: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound || !tweet.Active {
+		http.Error(w, "Tweet not found", http.StatusNotFound)
+
+		return
+	}
+
+	revisions, err := db.DbConn.GetTweetRevisions(id)
+
+	if err != nil {
+		http.Error(w, "An error has happened trying to get the revisions from the DB "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if revisions == nil {
+		revisions = []*req.TweetRevision{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := res.TweetHistoryResponse{
+		Tweet:     &tweet,
+		Revisions: revisions,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+/* Retweet shares another tweet in the user's tweets */
+func Retweet(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	principal := helpers.GetPrincipal(r)
+	original, isFound := getReferencedTweet(w, id)
+
+	if !isFound {
+		return
+	}
+
+	registry := req.Tweet{
+		UserId:    principal.Id,
+		Date:      time.Now(),
+		Active:    true,
+		RetweetOf: getOriginalId(original),
+	}
+
+	_, isCreated, err := db.DbConn.InsertRetweet(registry)
+
+	if err != nil {
+		http.Error(w, "An error occurred trying to insert a new registry into the DB: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isCreated {
+		http.Error(w, "The tweet has already been retweeted", http.StatusConflict)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+/* DeleteRetweet undoes the user's retweet of a tweet */
+func DeleteRetweet(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	principal := helpers.GetPrincipal(r)
+	isFound, err := db.DbConn.DeleteRetweet(principal.Id, id)
+
+	if err != nil {
+		http.Error(w, "An error occurred trying to delete the retweet: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound {
+		http.Error(w, "The tweet has not been retweeted", http.StatusNotFound)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/* Like adds the user's like to a tweet */
+func Like(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	principal := helpers.GetPrincipal(r)
+	tweet, isFound := getReferencedOriginal(w, id)
+
+	if !isFound {
+		return
+	}
+
+	registry := req.Like{
+		UserId:  principal.Id,
+		TweetId: tweet.Id,
 		Date:    time.Now(),
-		Active:  true,
 	}
 
-	_, err = db.DbConn.InsertTweet(registry)
+	isCreated, err := db.DbConn.InsertLike(registry)
 
 	if err != nil {
 		http.Error(w, "An error occurred trying to insert a new registry into the DB: "+err.Error(), http.StatusInternalServerError)
@@ -45,23 +256,304 @@ func Insert(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !isCreated {
+		http.Error(w, "The tweet has already been liked", http.StatusConflict)
+
+		return
+	}
+
 	w.WriteHeader(http.StatusCreated)
 }
 
+/* Unlike removes the user's like from a tweet */
+func Unlike(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	principal := helpers.GetPrincipal(r)
+	originalId, err := getStoredOriginalId(id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find the tweet: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	isFound, err := db.DbConn.DeleteLike(principal.Id, originalId)
+
+	if err != nil {
+		http.Error(w, "An error occurred trying to delete the like: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound {
+		http.Error(w, "The tweet has not been liked", http.StatusNotFound)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/* GetLikedTweets gets the tweets liked by an user, the last liked first */
+func GetLikedTweets(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	page := r.Context().Value(helpers.RequestPageKey{}).(int64)
+	limit := r.Context().Value(helpers.RequestLimitKey{}).(int64)
+	principal := helpers.GetPrincipal(r)
+
+	results, total, err := db.DbConn.GetLikedTweets(id, page, limit)
+
+	if err == nil {
+		err = embeds.SetOriginals(results)
+	}
+
+	if err == nil {
+		err = embeds.SetLikedByMe(principal.Id, results)
+	}
+
+	if err != nil {
+		http.Error(w, "An error has happened trying to get the liked tweets from the DB "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if results == nil {
+		results = []*req.Tweet{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := res.TweetsResponse{
+		Tweets: results,
+		Total:  total,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
 /* GetTweets gets an user's tweets */
 func GetTweets(w http.ResponseWriter, r *http.Request) {
 	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
 	page := r.Context().Value(helpers.RequestPageKey{}).(int64)
 	limit := r.Context().Value(helpers.RequestLimitKey{}).(int64)
 
+	principal := helpers.GetPrincipal(r)
+
 	results, total, err := db.DbConn.GetTweets(id, page, limit)
 
+	if err == nil {
+		err = embeds.SetOriginals(results)
+	}
+
+	if err == nil {
+		err = embeds.SetLikedByMe(principal.Id, results)
+	}
+
+	if err != nil {
+		http.Error(w, "An error has happened trying to get the tweets from the DB "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := res.TweetsResponse{
+		Tweets: results,
+		Total:  total,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+/* GetHashtagTweets gets the tweets with a hashtag */
+func GetHashtagTweets(w http.ResponseWriter, r *http.Request) {
+	tag := strings.TrimPrefix(r.URL.Query().Get("tag"), "#")
+	page := r.Context().Value(helpers.RequestPageKey{}).(int64)
+	limit := r.Context().Value(helpers.RequestLimitKey{}).(int64)
+	principal := helpers.GetPrincipal(r)
+	hashtags := helpers.GetHashtags("#" + tag)
+
+	// The tag must be a whole hashtag, it is searched as it is stored
+	if len(hashtags) != 1 || hashtags[0] != strings.ToLower(tag) {
+		http.Error(w, "The tag param is invalid", http.StatusBadRequest)
+
+		return
+	}
+
+	results, total, err := db.DbConn.GetHashtagTweets(hashtags[0], page, limit)
+
+	if err == nil {
+		err = embeds.SetOriginals(results)
+	}
+
+	if err == nil {
+		err = embeds.SetLikedByMe(principal.Id, results)
+	}
+
+	if err != nil {
+		http.Error(w, "An error has happened trying to get the tweets from the DB "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if results == nil {
+		results = []*req.Tweet{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := res.TweetsResponse{
+		Tweets: results,
+		Total:  total,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+/* SearchTweets gets the tweets whose message contains the words of a query, by relevance or the newest first */
+func SearchTweets(w http.ResponseWriter, r *http.Request) {
+	terms := helpers.GetSearchTerms(r.URL.Query().Get("q"))
+	sort := r.URL.Query().Get("sort")
+	page := r.Context().Value(helpers.RequestPageKey{}).(int64)
+	limit := r.Context().Value(helpers.RequestLimitKey{}).(int64)
+	principal := helpers.GetPrincipal(r)
+
+	if len(terms) < 1 {
+		http.Error(w, "The q param must have at least one word", http.StatusBadRequest)
+
+		return
+	}
+
+	if len(sort) > 0 && sort != "relevance" && sort != "recent" {
+		http.Error(w, "The sort param must be relevance or recent", http.StatusBadRequest)
+
+		return
+	}
+
+	results, total, err := db.DbConn.SearchTweets(terms, sort == "recent", page, limit)
+
+	if err == nil {
+		err = embeds.SetOriginals(results)
+	}
+
+	if err == nil {
+		err = embeds.SetLikedByMe(principal.Id, results)
+	}
+
+	if err != nil {
+		http.Error(w, "An error has happened trying to get the tweets from the DB "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if results == nil {
+		results = []*req.Tweet{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := res.TweetsResponse{
+		Tweets: results,
+		Total:  total,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+/* GetMentionTweets gets the tweets that mention the user */
+func GetMentionTweets(w http.ResponseWriter, r *http.Request) {
+	page := r.Context().Value(helpers.RequestPageKey{}).(int64)
+	limit := r.Context().Value(helpers.RequestLimitKey{}).(int64)
+	principal := helpers.GetPrincipal(r)
+
+	results, total, err := db.DbConn.GetMentionTweets(principal.Id, page, limit)
+
+	if err == nil {
+		err = embeds.SetOriginals(results)
+	}
+
+	if err == nil {
+		err = embeds.SetLikedByMe(principal.Id, results)
+	}
+
 	if err != nil {
 		http.Error(w, "An error has happened trying to get the tweets from the DB "+err.Error(), http.StatusInternalServerError)
 
 		return
 	}
 
+	if results == nil {
+		results = []*req.Tweet{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := res.TweetsResponse{
+		Tweets: results,
+		Total:  total,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+/* GetTrends gets the most used hashtags in the trends window */
+func GetTrends(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.ParseInt(os.Getenv("TRENDS_LIMIT"), 10, 64)
+
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	since := time.Now().Add(-helpers.GetTrendsWindow())
+	results, err := db.DbConn.GetTrends(since, limit)
+
+	if err != nil {
+		http.Error(w, "An error has happened trying to get the trends from the DB "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if results == nil {
+		results = []*req.Trend{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := res.TrendsResponse{
+		Trends: results,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+/* GetReplies gets the direct replies to a tweet */
+func GetReplies(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	page := r.Context().Value(helpers.RequestPageKey{}).(int64)
+	limit := r.Context().Value(helpers.RequestLimitKey{}).(int64)
+
+	results, total, err := db.DbConn.GetReplies(id, page, limit)
+
+	if err == nil {
+		err = embeds.SetOriginals(results)
+	}
+
+	if err != nil {
+		http.Error(w, "An error has happened trying to get the replies from the DB "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if results == nil {
+		results = []*req.Tweet{}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
@@ -73,10 +565,125 @@ func GetTweets(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+/* GetConversation gets the whole thread of a tweet as a tree ordered by date */
+func GetConversation(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	tweet, isFound, err := db.DbConn.GetTweet(id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find the tweet: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound {
+		http.Error(w, "Tweet not found", http.StatusNotFound)
+
+		return
+	}
+
+	rootId := tweet.ConversationId
+
+	if len(rootId) < 1 {
+		rootId = tweet.Id
+	}
+
+	tweets, err := db.DbConn.GetConversation(rootId)
+
+	if err == nil {
+		err = embeds.SetOriginals(tweets)
+	}
+
+	if err != nil {
+		http.Error(w, "An error has happened trying to get the conversation from the DB "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(getThread(rootId, tweets))
+}
+
+/* GetScheduledTweets gets the tweets scheduled by the user, the next to be published first */
+func GetScheduledTweets(w http.ResponseWriter, r *http.Request) {
+	page := r.Context().Value(helpers.RequestPageKey{}).(int64)
+	limit := r.Context().Value(helpers.RequestLimitKey{}).(int64)
+	principal := helpers.GetPrincipal(r)
+	results, total, err := db.DbConn.GetScheduledTweets(principal.Id, page, limit)
+
+	if err != nil {
+		http.Error(w, "An error has happened trying to get the scheduled tweets from the DB "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if results == nil {
+		results = []*req.ScheduledTweet{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := res.ScheduledTweetsResponse{
+		Tweets: results,
+		Total:  total,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+/* DeleteScheduledTweet cancels a tweet scheduled by the user */
+func DeleteScheduledTweet(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	principal := helpers.GetPrincipal(r)
+	scheduled, isFound, err := db.DbConn.DeleteScheduledTweet(id, principal.Id)
+
+	if err != nil {
+		http.Error(w, "An error occurred trying to cancel the scheduled tweet: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	// The tweet could have already been published
+	if !isFound {
+		http.Error(w, "Scheduled tweet not found", http.StatusNotFound)
+
+		return
+	}
+
+	media.Delete(scheduled.Media)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/* GetMedia gets an image of a tweet */
+func GetMedia(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	index := getMediaIndex(r)
+	tweet, isFound, err := db.DbConn.GetTweet(id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find the tweet: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound || !tweet.Active || index >= len(tweet.Media) {
+		http.Error(w, "Image not found", http.StatusNotFound)
+
+		return
+	}
+
+	setMediaToResponse(w, tweet.Media[index])
+}
+
 /* Delete deletes a tweet that belongs to an user */
 func Delete(w http.ResponseWriter, r *http.Request) {
 	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
-	err := db.DbConn.DeleteTweet(id, jwt.UserId)
+	principal := helpers.GetPrincipal(r)
+	err := db.DbConn.DeleteTweet(id, principal.Id)
 
 	if err != nil {
 		http.Error(w, "An error occurred trying to delete the tweet: "+err.Error(), http.StatusInternalServerError)
@@ -87,3 +694,254 @@ func Delete(w http.ResponseWriter, r *http.Request) {
 	//w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// region "Helpers"
+
+/* decodeTweet reads a tweet from a json body or from a multipart form with its new images, the images kept are sent as values */
+func decodeTweet(r *http.Request) (req.Tweet, []*multipart.FileHeader, error) {
+	var tweet req.Tweet
+
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		err := json.NewDecoder(r.Body).Decode(&tweet)
+
+		return tweet, nil, err
+	}
+
+	err := r.ParseMultipartForm(32 << 20)
+
+	if err != nil {
+		return tweet, nil, err
+	}
+
+	tweet.Message = r.FormValue("message")
+	tweet.InReplyTo = r.FormValue("inReplyTo")
+	tweet.QuotedTweetId = r.FormValue("quotedTweetId")
+	tweet.Media = r.MultipartForm.Value["media"]
+
+	if publishAt := r.FormValue("publishAt"); len(publishAt) > 0 {
+		date, err := time.Parse(time.RFC3339, publishAt)
+
+		if err != nil {
+			return tweet, nil, err
+		}
+
+		tweet.PublishAt = &date
+	}
+
+	return tweet, r.MultipartForm.File["media"], nil
+}
+
+/* prepareTweet validates a new tweet and uploads its images, writing the error response if it is not valid */
+func prepareTweet(w http.ResponseWriter, userId string, tweet req.Tweet, headers []*multipart.FileHeader) (req.Tweet, bool) {
+	var err error
+
+	// A tweet with images can be sent without a message
+	if len(tweet.Message) < 1 && len(tweet.Media)+len(headers) < 1 {
+		http.Error(w, "The message cannot be empty", http.StatusBadRequest)
+
+		return tweet, false
+	}
+
+	if tweet.PublishAt != nil && !tweet.PublishAt.After(time.Now()) {
+		http.Error(w, "The publication date must be in the future", http.StatusBadRequest)
+
+		return tweet, false
+	}
+
+	err = media.Validate(headers, len(tweet.Media))
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return tweet, false
+	}
+
+	registry := req.Tweet{
+		UserId:  userId,
+		Message: tweet.Message,
+		Date:    time.Now(),
+		Active:  true,
+		Media:   tweet.Media,
+	}
+
+	if len(tweet.InReplyTo) > 0 {
+		parent, isFound := getReferencedOriginal(w, tweet.InReplyTo)
+
+		if !isFound {
+			return registry, false
+		}
+
+		// All the tweets of a thread share the id of the first one
+		registry.InReplyTo = parent.Id
+		registry.ConversationId = parent.ConversationId
+
+		if len(registry.ConversationId) < 1 {
+			registry.ConversationId = parent.Id
+		}
+	}
+
+	if len(tweet.QuotedTweetId) > 0 {
+		quoted, isFound := getReferencedTweet(w, tweet.QuotedTweetId)
+
+		if !isFound {
+			return registry, false
+		}
+
+		registry.QuotedTweetId = getOriginalId(quoted)
+	}
+
+	// The images are stored once the referenced tweets are validated
+	if len(headers) > 0 {
+		references, err := media.Upload(userId, headers)
+
+		if err != nil {
+			http.Error(w, "An error occurred trying to upload the images: "+err.Error(), http.StatusInternalServerError)
+
+			return registry, false
+		}
+
+		registry.Media = append(registry.Media, references...)
+	}
+
+	return registry, true
+}
+
+/* getMediaIndex gets the position of the requested image, the first one by default */
+func getMediaIndex(r *http.Request) int {
+	index, err := strconv.Atoi(r.URL.Query().Get("index"))
+
+	if err != nil || index < 0 {
+		return 0
+	}
+
+	return index
+}
+
+/* setMediaToResponse writes an image, stored in the local server or remotely, to the response */
+func setMediaToResponse(w http.ResponseWriter, reference string) {
+	var filepath string
+
+	isRemote, _ := strconv.ParseBool(os.Getenv("FILES_REMOTE"))
+
+	if !isRemote {
+		filepath = media.GetLocalPath(reference)
+	} else {
+		filepath = reference
+	}
+
+	err := fc.SetFileToResponse(filepath, w, isRemote)
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+/* getReferencedTweet gets an active tweet referenced by a new one, writing the error response if it is not found */
+func getReferencedTweet(w http.ResponseWriter, id string) (req.Tweet, bool) {
+	tweet, isFound, err := db.DbConn.GetTweet(id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find the tweet: "+err.Error(), http.StatusInternalServerError)
+
+		return tweet, false
+	}
+
+	if !isFound || !tweet.Active {
+		http.Error(w, "The referenced tweet does not exist", http.StatusNotFound)
+
+		return tweet, false
+	}
+
+	return tweet, true
+}
+
+/* getReferencedOriginal gets an active tweet referenced by a new one or a like, resolving the retweets to the shared tweet, writing the error response if it is not found */
+func getReferencedOriginal(w http.ResponseWriter, id string) (req.Tweet, bool) {
+	tweet, isFound := getReferencedTweet(w, id)
+
+	if !isFound || len(tweet.RetweetOf) < 1 {
+		return tweet, isFound
+	}
+
+	return getReferencedTweet(w, getOriginalId(tweet))
+}
+
+/* getStoredOriginalId resolves a retweet to the shared tweet, the deleted or missing tweets keep their id */
+func getStoredOriginalId(id string) (string, error) {
+	tweet, isFound, err := db.DbConn.GetTweet(id)
+
+	if err != nil || !isFound {
+		return id, err
+	}
+
+	return getOriginalId(tweet), nil
+}
+
+/* getOriginalId returns the id of the tweet shared by a retweet, so the retweets of a retweet point to the original */
+func getOriginalId(tweet req.Tweet) string {
+	if len(tweet.RetweetOf) > 0 {
+		return tweet.RetweetOf
+	}
+
+	return tweet.Id
+}
+
+/* getThread builds the tree of a conversation, the tweets must be ordered by date */
+func getThread(rootId string, tweets []*req.Tweet) *res.TweetThread {
+	var missingIds []string
+
+	threads := make(map[string]*res.TweetThread)
+	isLoaded := make(map[string]bool)
+
+	// The missing tweets, as the ones of deleted accounts, are shown as unavailable to keep their replies
+	getNode := func(id string) *res.TweetThread {
+		thread, isFound := threads[id]
+
+		if !isFound {
+			thread = &res.TweetThread{
+				Tweet:   &req.Tweet{Id: id, Unavailable: true},
+				Replies: []*res.TweetThread{},
+			}
+			threads[id] = thread
+			missingIds = append(missingIds, id)
+		}
+
+		return thread
+	}
+
+	for _, tweet := range tweets {
+		node := getNode(tweet.Id)
+		node.Tweet = tweet
+		isLoaded[tweet.Id] = true
+
+		// The deleted tweets keep their place in the thread without their content
+		if !tweet.Active {
+			node.Tweet = &req.Tweet{
+				Id:             tweet.Id,
+				Date:           tweet.Date,
+				InReplyTo:      tweet.InReplyTo,
+				ConversationId: tweet.ConversationId,
+				ReplyCount:     tweet.ReplyCount,
+				Unavailable:    true,
+			}
+		}
+
+		if tweet.Id != rootId {
+			parent := getNode(tweet.InReplyTo)
+			parent.Replies = append(parent.Replies, node)
+		}
+	}
+
+	root := getNode(rootId)
+
+	// The parent of a missing tweet is unknown, so it hangs from the first tweet
+	for _, id := range missingIds {
+		if !isLoaded[id] && id != rootId {
+			root.Replies = append(root.Replies, threads[id])
+		}
+	}
+
+	return root
+}
+
+// endregion
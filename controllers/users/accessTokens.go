@@ -0,0 +1,167 @@
+package users
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"db"
+	"helpers"
+	req "models/request"
+)
+
+// region "Actions"
+
+/* CreateAccessToken creates a personal access token, which is only returned in this response */
+func CreateAccessToken(w http.ResponseWriter, r *http.Request) {
+	var accessTokenCreate req.AccessTokenCreate
+
+	principal := helpers.GetPrincipal(r)
+	err := json.NewDecoder(r.Body).Decode(&accessTokenCreate)
+
+	if err != nil {
+		http.Error(w, "Invalid data: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	name := strings.TrimSpace(accessTokenCreate.Name)
+
+	if len(name) < 1 || len(name) > 100 {
+		http.Error(w, "The name is required and must have up to 100 characters", http.StatusBadRequest)
+
+		return
+	}
+
+	scopes, err := getValidScopes(accessTokenCreate.Scopes)
+
+	if err != nil {
+		http.Error(w, "Invalid scopes: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	if accessTokenCreate.ExpiresInDays < 0 {
+		http.Error(w, "The expiresInDays must be zero, for no expiration, or positive", http.StatusBadRequest)
+
+		return
+	}
+
+	token, err := helpers.GenerateToken(32)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	token = req.AccessTokenPrefix + token
+	accessToken := req.AccessToken{
+		UserId:    principal.Id,
+		Name:      name,
+		Hash:      helpers.HashToken(token),
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+	}
+
+	if accessTokenCreate.ExpiresInDays > 0 {
+		accessToken.ExpiresAt = accessToken.CreatedAt.AddDate(0, 0, accessTokenCreate.ExpiresInDays)
+	}
+
+	accessToken.Id, err = db.DbConn.InsertAccessToken(accessToken)
+
+	if err != nil {
+		http.Error(w, "An error has occurred when trying to create the access token: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	accessToken.Token = token
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	json.NewEncoder(w).Encode(accessToken)
+}
+
+/* GetAccessTokens gets the user's personal access tokens */
+func GetAccessTokens(w http.ResponseWriter, r *http.Request) {
+	principal := helpers.GetPrincipal(r)
+	accessTokens, err := db.DbConn.GetAccessTokens(principal.Id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find the access tokens: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if accessTokens == nil {
+		accessTokens = []*req.AccessToken{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(accessTokens)
+}
+
+/* DeleteAccessToken revokes one of the user's personal access tokens */
+func DeleteAccessToken(w http.ResponseWriter, r *http.Request) {
+	id := r.Context().Value(helpers.RequestQueryIdKey{}).(string)
+	principal := helpers.GetPrincipal(r)
+	isFound, err := db.DbConn.DeleteAccessToken(id, principal.Id)
+
+	if err != nil {
+		http.Error(w, "An error has occurred when trying to delete the access token: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound {
+		http.Error(w, "Access token not found", http.StatusNotFound)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// endregion
+
+// region "Helpers"
+
+func getValidScopes(scopes []string) ([]string, error) {
+	var validScopes []string
+
+	if len(scopes) < 1 {
+		return validScopes, errors.New("at least one scope is required")
+	}
+
+	for _, scope := range scopes {
+		isValid := false
+		isRepeated := false
+
+		for _, s := range req.Scopes {
+			isValid = isValid || s == scope
+		}
+
+		for _, s := range validScopes {
+			isRepeated = isRepeated || s == scope
+		}
+
+		if !isValid {
+			return validScopes, fmt.Errorf("unknown scope %s", scope)
+		}
+
+		if !isRepeated {
+			validScopes = append(validScopes, scope)
+		}
+	}
+
+	return validScopes, nil
+}
+
+// endregion
@@ -0,0 +1,89 @@
+package users
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"accounts"
+	"db"
+	"helpers"
+	req "models/request"
+	"throttle"
+)
+
+// region "Actions"
+
+/* DeleteAccount deletes the user, or schedules its deletion if there is a grace period */
+func DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	var accountDelete req.AccountDelete
+
+	principal := helpers.GetPrincipal(r)
+	err := json.NewDecoder(r.Body).Decode(&accountDelete)
+
+	if err != nil {
+		http.Error(w, "Invalid data: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	accountKey := throttle.AccountKey(principal.Email)
+	ipKey := throttle.IpKey(helpers.GetClientIp(r))
+
+	if isLocked(w, accountKey, ipKey) {
+		return
+	}
+
+	userDb, isUser := db.DbConn.TryLogin(principal.Email, accountDelete.Password)
+
+	if !isUser {
+		registerFailure(accountKey, ipKey)
+
+		http.Error(w, "The password is invalid", http.StatusBadRequest)
+
+		return
+	}
+
+	resetFailures(accountKey)
+
+	grace := helpers.GetDurationEnv("ACCOUNT_DELETION_GRACE", 0)
+
+	if grace == 0 {
+		err = accounts.DeleteAccount(userDb)
+
+		if err != nil {
+			http.Error(w, "An error has occurred when trying to delete the account: "+err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		expireTokenCookies(w)
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	// The user is logged out everywhere, logging in again before the deletion cancels it
+	err = db.DbConn.ScheduleUserDeletion(userDb.Id, time.Now().Add(grace))
+
+	if err == nil {
+		err = db.DbConn.RevokeUserTokens(userDb.Id)
+	}
+
+	if err == nil {
+		err = db.DbConn.DeleteSessions(userDb.Id, "")
+	}
+
+	if err != nil {
+		http.Error(w, "An error has occurred when trying to schedule the deletion: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	expireTokenCookies(w)
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// endregion
@@ -0,0 +1,33 @@
+package users
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"helpers"
+	"jwt"
+	res "models/response"
+)
+
+/* GetCsrfToken issues a new CSRF token, set in its cookie and returned in the body */
+func GetCsrfToken(w http.ResponseWriter, r *http.Request) {
+	csrfToken, err := helpers.GenerateToken(32)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	response := res.CsrfResponse{
+		CsrfToken: csrfToken,
+	}
+
+	http.SetCookie(w, helpers.NewCookie(helpers.CsrfCookieName, csrfToken, time.Now().Add(jwt.GetRefreshTTL()), false))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(response)
+}
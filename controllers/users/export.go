@@ -0,0 +1,128 @@
+package users
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"db"
+	"exports"
+	"helpers"
+	req "models/request"
+	res "models/response"
+
+	"github.com/gorilla/mux"
+)
+
+// region "Actions"
+
+/* ExportData starts the generation of the archive with the user's personal data */
+func ExportData(w http.ResponseWriter, r *http.Request) {
+	principal := helpers.GetPrincipal(r)
+	profile, isFound, err := db.DbConn.GetProfile(principal.Id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find a registry in the DB: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound {
+		http.Error(w, "No registry found in the DB", http.StatusNotFound)
+
+		return
+	}
+
+	export, err := exports.Start(profile)
+
+	if err != nil {
+		http.Error(w, "An error has occurred when trying to start the export: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	setExportToResponse(w, export)
+}
+
+/* GetExport gets the status of an export and, when it is ready, its download link */
+func GetExport(w http.ResponseWriter, r *http.Request) {
+	principal := helpers.GetPrincipal(r)
+	export, isFound, err := exports.Get(mux.Vars(r)["id"], principal.Id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find the export: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound {
+		http.Error(w, "Export not found", http.StatusNotFound)
+
+		return
+	}
+
+	setExportToResponse(w, export)
+}
+
+/* DownloadExport downloads the archive of an export with the token of its download link */
+func DownloadExport(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	filepath, isValid, err := exports.GetFile(id, r.URL.Query().Get("token"))
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find the export: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isValid {
+		http.Error(w, "The download link is invalid or has expired", http.StatusNotFound)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"export-%s.zip\"", id))
+
+	http.ServeFile(w, r, filepath)
+}
+
+// endregion
+
+// region "Helpers"
+
+func setExportToResponse(w http.ResponseWriter, export req.Export) {
+	response := res.ExportResponse{
+		Id:        export.Id,
+		Status:    export.Status,
+		Error:     export.Error,
+		CreatedAt: export.CreatedAt,
+		ExpiresAt: export.ExpiresAt,
+	}
+
+	status := http.StatusAccepted
+
+	if export.Status == exports.StatusReady {
+		token, expiresAt, err := exports.NewDownloadToken(export)
+
+		if err != nil {
+			http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		response.DownloadUrl = fmt.Sprintf("/user/export/%s/download?token=%s", export.Id, url.QueryEscape(token))
+		response.DownloadExpiresAt = expiresAt
+		status = http.StatusOK
+	} else if export.Status == exports.StatusFailed {
+		status = http.StatusOK
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// endregion
@@ -0,0 +1,88 @@
+package users
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"db"
+	"helpers"
+	"jwt"
+	req "models/request"
+
+	"github.com/gorilla/mux"
+)
+
+// region "Actions"
+
+/* GetSessions gets the user's active sessions */
+func GetSessions(w http.ResponseWriter, r *http.Request) {
+	principal := helpers.GetPrincipal(r)
+	sessions, err := db.DbConn.GetSessions(principal.Id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find the sessions: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if sessions == nil {
+		sessions = []*req.Session{}
+	}
+
+	for _, session := range sessions {
+		session.Current = session.Id == principal.Claims.SessionId
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(sessions)
+}
+
+/* DeleteSession revokes one of the user's sessions and all the tokens issued for it */
+func DeleteSession(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	principal := helpers.GetPrincipal(r)
+	isFound, err := db.DbConn.DeleteSession(id, principal.Id)
+
+	if err != nil {
+		http.Error(w, "An error has occurred when trying to delete the session: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound {
+		http.Error(w, "Session not found", http.StatusNotFound)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// endregion
+
+// region "Helpers"
+
+func createSession(r *http.Request, userId string) (string, error) {
+	userAgent := r.UserAgent()
+
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	now := time.Now()
+	session := req.Session{
+		UserId:     userId,
+		UserAgent:  userAgent,
+		Ip:         helpers.GetClientIp(r),
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(jwt.GetRefreshTTL()),
+	}
+
+	return db.DbConn.InsertSession(session)
+}
+
+// endregion
@@ -0,0 +1,386 @@
+package users
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"db"
+	"helpers"
+	"jwt"
+	req "models/request"
+	res "models/response"
+	"throttle"
+	"totp"
+)
+
+// region "Actions"
+
+/* EnrollTotp generates a two-factor authentication secret that must be confirmed with a code */
+func EnrollTotp(w http.ResponseWriter, r *http.Request) {
+	principal := helpers.GetPrincipal(r)
+	profile, isFound, err := db.DbConn.GetProfile(principal.Id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find a registry in the DB: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound {
+		http.Error(w, "No registry found in the DB", http.StatusNotFound)
+
+		return
+	}
+
+	if profile.TotpEnabled {
+		http.Error(w, "The two-factor authentication is already enabled", http.StatusBadRequest)
+
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	err = db.DbConn.SetTotp(profile.Id, secret, false)
+
+	if err != nil {
+		http.Error(w, "An error has occurred when trying to save the secret: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	issuer := os.Getenv("TOTP_ISSUER")
+
+	if len(issuer) < 1 {
+		issuer = "Twittor"
+	}
+
+	response := res.TotpEnrollResponse{
+		Secret: secret,
+		Uri:    totp.GetURI(secret, issuer, profile.Email),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(response)
+}
+
+/* ConfirmTotp enables the two-factor authentication and returns the recovery codes */
+func ConfirmTotp(w http.ResponseWriter, r *http.Request) {
+	var totpCode req.TotpCode
+
+	principal := helpers.GetPrincipal(r)
+	err := json.NewDecoder(r.Body).Decode(&totpCode)
+
+	if err != nil {
+		http.Error(w, "Invalid data: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	profile, isFound, err := db.DbConn.GetProfile(principal.Id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find a registry in the DB: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound {
+		http.Error(w, "No registry found in the DB", http.StatusNotFound)
+
+		return
+	}
+
+	if profile.TotpEnabled {
+		http.Error(w, "The two-factor authentication is already enabled", http.StatusBadRequest)
+
+		return
+	}
+
+	if len(profile.TotpSecret) < 1 {
+		http.Error(w, "The two-factor authentication enrolment has not been started", http.StatusBadRequest)
+
+		return
+	}
+
+	isValid, err := validateTotpCode(profile, totpCode.Code)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isValid {
+		http.Error(w, "The code is invalid", http.StatusBadRequest)
+
+		return
+	}
+
+	err = db.DbConn.SetTotp(profile.Id, profile.TotpSecret, true)
+
+	if err != nil {
+		http.Error(w, "An error has occurred when trying to enable the two-factor authentication: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	recoveryCodes, err := createRecoveryCodes(profile.Id)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	response := res.RecoveryCodesResponse{
+		RecoveryCodes: recoveryCodes,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(response)
+}
+
+/* DisableTotp disables the two-factor authentication */
+func DisableTotp(w http.ResponseWriter, r *http.Request) {
+	var totpCode req.TotpCode
+
+	principal := helpers.GetPrincipal(r)
+	err := json.NewDecoder(r.Body).Decode(&totpCode)
+
+	if err != nil {
+		http.Error(w, "Invalid data: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	profile, isFound, err := db.DbConn.GetProfile(principal.Id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find a registry in the DB: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound {
+		http.Error(w, "No registry found in the DB", http.StatusNotFound)
+
+		return
+	}
+
+	if !profile.TotpEnabled {
+		http.Error(w, "The two-factor authentication is not enabled", http.StatusBadRequest)
+
+		return
+	}
+
+	isValid, err := validateTotpCode(profile, totpCode.Code)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isValid {
+		http.Error(w, "The code is invalid", http.StatusBadRequest)
+
+		return
+	}
+
+	err = db.DbConn.SetTotp(profile.Id, "", false)
+
+	if err == nil {
+		err = db.DbConn.DeleteActionTokens(profile.Id, req.ActionRecoveryCode)
+	}
+
+	if err != nil {
+		http.Error(w, "An error has occurred when trying to disable the two-factor authentication: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/* LoginTwoFactor exchanges a challenge token and a two-factor code or a recovery code for the tokens */
+func LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var twoFactorLogin req.TwoFactorLogin
+
+	err := json.NewDecoder(r.Body).Decode(&twoFactorLogin)
+
+	if err != nil {
+		http.Error(w, "Invalid data: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	claims, err := jwt.ProcessChallengeJWT(twoFactorLogin.ChallengeToken)
+
+	if err != nil {
+		http.Error(w, "Error on the Token: "+err.Error(), http.StatusUnauthorized)
+
+		return
+	}
+
+	isFound, userDb, err := db.DbConn.IsUser(claims.Email)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound || !userDb.TotpEnabled {
+		http.Error(w, "The two-factor authentication is not enabled", http.StatusUnauthorized)
+
+		return
+	}
+
+	accountKey := throttle.AccountKey(userDb.Email)
+	ipKey := throttle.IpKey(helpers.GetClientIp(r))
+
+	if isLocked(w, accountKey, ipKey) {
+		return
+	}
+
+	isValid := false
+
+	if len(twoFactorLogin.Code) > 0 {
+		isValid, err = validateTotpCode(userDb, twoFactorLogin.Code)
+	} else if len(twoFactorLogin.RecoveryCode) > 0 {
+		isValid, err = consumeRecoveryCode(userDb.Id, twoFactorLogin.RecoveryCode)
+	}
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isValid {
+		registerFailure(accountKey, ipKey)
+
+		http.Error(w, "The code is invalid", http.StatusUnauthorized)
+
+		return
+	}
+
+	resetFailures(accountKey)
+
+	// The challenge token can be used only once
+	isRevoked, err := jwt.RevokeJWT(claims)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isRevoked {
+		http.Error(w, "Error on the Token: the token has already been used", http.StatusUnauthorized)
+
+		return
+	}
+
+	setNewSessionToResponse(w, r, userDb)
+}
+
+// endregion
+
+// region "Helpers"
+
+func setChallengeToResponse(w http.ResponseWriter, user req.User) {
+	challengeKey, err := jwt.GenerateChallengeJWT(user)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	response := res.LoginResponse{
+		ChallengeToken: challengeKey,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func createRecoveryCodes(userId string) ([]string, error) {
+	var recoveryCodes []string
+
+	total, err := strconv.Atoi(os.Getenv("TOTP_RECOVERY_CODES"))
+
+	if err != nil || total < 1 {
+		total = 10
+	}
+
+	// The new codes replace the previous ones
+	err = db.DbConn.DeleteActionTokens(userId, req.ActionRecoveryCode)
+
+	if err != nil {
+		return recoveryCodes, err
+	}
+
+	for i := 0; i < total; i++ {
+		code, err := helpers.GenerateToken(5)
+
+		if err != nil {
+			return recoveryCodes, err
+		}
+
+		actionToken := req.ActionToken{
+			Hash:   helpers.HashToken(code),
+			UserId: userId,
+			Action: req.ActionRecoveryCode,
+		}
+
+		err = db.DbConn.InsertActionToken(actionToken)
+
+		if err != nil {
+			return recoveryCodes, err
+		}
+
+		recoveryCodes = append(recoveryCodes, code)
+	}
+
+	return recoveryCodes, nil
+}
+
+func validateTotpCode(user req.User, code string) (bool, error) {
+	counter, isValid := totp.Validate(code, user.TotpSecret, time.Now(), user.TotpLastCounter)
+
+	if !isValid {
+		return false, nil
+	}
+
+	// The counter is saved only if it is later than the stored one, so concurrent requests with the same code are rejected too
+	return db.DbConn.UseTotpCounter(user.Id, counter)
+}
+
+func consumeRecoveryCode(userId string, code string) (bool, error) {
+	token, isValid, err := db.DbConn.ConsumeActionToken(helpers.HashToken(code), req.ActionRecoveryCode)
+
+	if err != nil || !isValid {
+		return false, err
+	}
+
+	return token.UserId == userId, nil
+}
+
+// endregion
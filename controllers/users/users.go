@@ -3,8 +3,12 @@ package users
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"log"
+	"math"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -14,8 +18,10 @@ import (
 	"db"
 	"helpers"
 	"jwt"
+	"mailer"
 	req "models/request"
 	res "models/response"
+	"throttle"
 )
 
 // region "Actions"
@@ -23,9 +29,16 @@ import (
 /* Insert permits to create a user in the DB */
 func Insert(w http.ResponseWriter, r *http.Request) {
 	user := r.Context().Value(helpers.RequestUserKey{}).(req.User)
+	ipKey := throttle.IpKey(helpers.GetClientIp(r))
 
-	if len(user.Password) < 6 {
-		http.Error(w, "The password must have at least 6 characters", http.StatusBadRequest)
+	if isLocked(w, ipKey) {
+		return
+	}
+
+	err := helpers.ValidatePassword(user.Password)
+
+	if err != nil {
+		http.Error(w, "Invalid password: "+err.Error(), http.StatusBadRequest)
 
 		return
 	}
@@ -39,12 +52,23 @@ func Insert(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if isFound {
+		// Probing the registered emails is limited as a failed login
+		registerFailure(ipKey)
+
 		http.Error(w, "The user already exists", http.StatusBadRequest)
 
 		return
 	}
 
-	_, err = db.DbConn.InsertUser(user)
+	// The email address is verified later through the token sent to it
+	user.Verified = false
+	user.Role = req.RoleUser
+
+	// The two-factor authentication is only enabled after confirming a secret
+	user.TotpEnabled = false
+	user.TotpSecret = ""
+
+	id, err := db.DbConn.InsertUser(user)
 
 	if err != nil {
 		http.Error(w, "There was an error trying to regist the user: "+err.Error(), http.StatusInternalServerError)
@@ -52,21 +76,72 @@ func Insert(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The user can ask for a new verification email, so the registration does not fail
+	err = sendVerificationEmail(id, user.Email)
+
+	if err != nil {
+		log.Println("Error sending the verification email: " + err.Error())
+	}
+
 	w.WriteHeader(http.StatusCreated)
 }
 
 /* Login does the login */
 func Login(w http.ResponseWriter, r *http.Request) {
 	user := r.Context().Value(helpers.RequestUserKey{}).(req.User)
+	accountKey := throttle.AccountKey(user.Email)
+	ipKey := throttle.IpKey(helpers.GetClientIp(r))
+
+	// The unknown emails are locked too, so the response does not reveal if an email exists
+	if isLocked(w, accountKey, ipKey) {
+		return
+	}
+
 	userDb, isUser := db.DbConn.TryLogin(user.Email, user.Password)
 
 	if !isUser {
+		registerFailure(accountKey, ipKey)
+
 		http.Error(w, "User and/or password invalid", http.StatusBadRequest)
 
 		return
 	}
 
-	jwtKey, err := jwt.GenerateJWT(userDb)
+	// The IP address counter is not reset, otherwise an attacker could reset it with its own account
+	resetFailures(accountKey)
+
+	// With the two-factor authentication enabled, the tokens are given after validating the code
+	if userDb.TotpEnabled {
+		setChallengeToResponse(w, userDb)
+
+		return
+	}
+
+	setNewSessionToResponse(w, r, userDb)
+}
+
+/* RefreshToken exchanges a refresh token for a new pair of tokens */
+func RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var refreshToken req.RefreshToken
+
+	err := json.NewDecoder(r.Body).Decode(&refreshToken)
+
+	// The browser clients send the refresh token in its cookie instead of the body
+	if err != nil && err != io.EOF {
+		http.Error(w, "Invalid data: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	claims, err := jwt.ProcessRefreshJWT(getRefreshToken(r, refreshToken))
+
+	if err != nil {
+		http.Error(w, "Error on the Token: "+err.Error(), http.StatusUnauthorized)
+
+		return
+	}
+
+	isFound, userDb, err := db.DbConn.IsUser(claims.Email)
 
 	if err != nil {
 		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
@@ -74,22 +149,320 @@ func Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := res.LoginResponse{
-		Token: jwtKey,
+	if !isFound {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+
+		return
+	}
+
+	// The refresh tokens are rotated, so each one can be used only once
+	isRevoked, err := jwt.RevokeJWT(claims)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	// A concurrent or replayed request has already used the token
+	if !isRevoked {
+		http.Error(w, "Error on the Token: the token has already been used", http.StatusUnauthorized)
+
+		return
+	}
+
+	// The refresh tokens issued before the sessions were recorded get a new session
+	if len(claims.SessionId) < 1 {
+		setNewSessionToResponse(w, r, userDb)
+
+		return
+	}
+
+	setTokensToResponse(w, userDb, claims.SessionId)
+}
+
+/* Logout revokes the access token and, if it is sent, the refresh token */
+func Logout(w http.ResponseWriter, r *http.Request) {
+	var refreshToken req.RefreshToken
+
+	principal := helpers.GetPrincipal(r)
+
+	// The body is optional
+	json.NewDecoder(r.Body).Decode(&refreshToken)
+
+	if token := getRefreshToken(r, refreshToken); len(token) > 0 {
+		claims, err := jwt.ProcessRefreshJWT(token)
+
+		if err == nil && claims.Id != principal.Id {
+			err = fmt.Errorf("the refresh token belongs to another user")
+		}
+
+		if err != nil {
+			http.Error(w, "Error on the refresh Token: "+err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		_, err = jwt.RevokeJWT(claims)
+
+		if err != nil {
+			http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	_, err := jwt.RevokeJWT(principal.Claims)
+
+	if err == nil && len(principal.Claims.SessionId) > 0 {
+		_, err = db.DbConn.DeleteSession(principal.Claims.SessionId, principal.Id)
+	}
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	expireTokenCookies(w)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/* VerifyEmail verifies the user's email address using a verification token */
+func VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	tokenQuery := r.URL.Query().Get("token")
+
+	if len(tokenQuery) < 1 {
+		http.Error(w, "The token param is required", http.StatusBadRequest)
+
+		return
+	}
+
+	token, isValid, err := db.DbConn.ConsumeActionToken(helpers.HashToken(tokenQuery), req.ActionVerifyEmail)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isValid {
+		http.Error(w, "The token is invalid or has expired", http.StatusBadRequest)
+
+		return
+	}
+
+	err = db.DbConn.VerifyUser(token.UserId)
+
+	if err != nil {
+		http.Error(w, "An error has occurred when trying to verify the email: "+err.Error(), http.StatusInternalServerError)
+
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+}
 
-	json.NewEncoder(w).Encode(response)
+/* ResendVerification sends a new verification email to the user */
+func ResendVerification(w http.ResponseWriter, r *http.Request) {
+	principal := helpers.GetPrincipal(r)
+	profile, isFound, err := db.DbConn.GetProfile(principal.Id)
+
+	if err != nil {
+		http.Error(w, "An error occurred when trying to find a registry in the DB: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isFound {
+		http.Error(w, "No registry found in the DB", http.StatusNotFound)
+
+		return
+	}
+
+	if profile.Verified {
+		http.Error(w, "The email address is already verified", http.StatusBadRequest)
+
+		return
+	}
+
+	err = sendVerificationEmail(profile.Id, profile.Email)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+/* ForgotPassword sends an email with a token to reset the password */
+func ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(helpers.RequestUserKey{}).(req.User)
+	isFound, userDb, err := db.DbConn.IsUser(user.Email)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	// The response is the same whether the user exists or not
+	if !isFound {
+		w.WriteHeader(http.StatusAccepted)
+
+		return
+	}
+
+	ttl := helpers.GetDurationEnv("RESET_TOKEN_TTL", time.Hour)
+	token, err := createActionToken(userDb.Id, req.ActionResetPassword, ttl)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	body := fmt.Sprintf("Use the following token to reset your password: %s\n\nThe token expires in %s.", token, ttl)
+	body += getActionLink("reset-password", token)
+
+	err = mailer.MailConn.Send(userDb.Email, "Reset your password", body)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+/* ResetPassword sets a new password using a reset token */
+func ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var passwordReset req.PasswordReset
+
+	err := json.NewDecoder(r.Body).Decode(&passwordReset)
+
+	if err != nil {
+		http.Error(w, "Invalid data: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	err = helpers.ValidatePassword(passwordReset.Password)
+
+	if err != nil {
+		http.Error(w, "Invalid password: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	token, isValid, err := db.DbConn.ConsumeActionToken(helpers.HashToken(passwordReset.Token), req.ActionResetPassword)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if !isValid {
+		http.Error(w, "The token is invalid or has expired", http.StatusBadRequest)
+
+		return
+	}
+
+	err = db.DbConn.ModifyPassword(token.UserId, passwordReset.Password)
+
+	if err != nil {
+		http.Error(w, "An error has occurred when trying to modify the password: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	err = db.DbConn.RevokeUserTokens(token.UserId)
+
+	if err == nil {
+		err = db.DbConn.DeleteSessions(token.UserId, "")
+	}
+
+	// The personal access tokens are not checked against the revocation date, so they are deleted
+	if err == nil {
+		err = db.DbConn.DeleteAccessTokens(token.UserId)
+	}
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/* ChangePassword changes the password of the user and revokes the existing tokens */
+func ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var passwordChange req.PasswordChange
+
+	principal := helpers.GetPrincipal(r)
+	err := json.NewDecoder(r.Body).Decode(&passwordChange)
+
+	if err != nil {
+		http.Error(w, "Invalid data: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	err = helpers.ValidatePassword(passwordChange.NewPassword)
+
+	if err != nil {
+		http.Error(w, "Invalid password: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
 
-	expirationTime := time.Now().Add(24 * time.Hour)
+	userDb, isUser := db.DbConn.TryLogin(principal.Email, passwordChange.CurrentPassword)
 
-	http.SetCookie(w, &http.Cookie{
-		Name:    "token",
-		Value:   jwtKey,
-		Expires: expirationTime,
-	})
+	if !isUser {
+		http.Error(w, "The current password is invalid", http.StatusBadRequest)
+
+		return
+	}
+
+	err = db.DbConn.ModifyPassword(userDb.Id, passwordChange.NewPassword)
+
+	if err != nil {
+		http.Error(w, "An error has occurred when trying to modify the password: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	err = db.DbConn.RevokeUserTokens(userDb.Id)
+
+	if err == nil {
+		// The other sessions are closed, the current one continues with the new tokens
+		err = db.DbConn.DeleteSessions(userDb.Id, principal.Claims.SessionId)
+	}
+
+	if err == nil {
+		err = db.DbConn.DeleteAccessTokens(userDb.Id)
+	}
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if len(principal.Claims.SessionId) < 1 {
+		setNewSessionToResponse(w, r, userDb)
+
+		return
+	}
+
+	// The current tokens have been revoked, so a new pair is returned
+	setTokensToResponse(w, userDb, principal.Claims.SessionId)
 }
 
 /* GetProfile gets an user profile */
@@ -119,6 +492,7 @@ func GetProfile(w http.ResponseWriter, r *http.Request) {
 func Modify(w http.ResponseWriter, r *http.Request) {
 	var user req.User
 
+	principal := helpers.GetPrincipal(r)
 	err := json.NewDecoder(r.Body).Decode(&user)
 
 	if err != nil {
@@ -127,7 +501,7 @@ func Modify(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = db.DbConn.ModifyRegistry(jwt.UserId, user)
+	err = db.DbConn.ModifyRegistry(principal.Id, user)
 
 	if err != nil {
 		http.Error(w, "An error has occurred when trying to modify the registry: "+err.Error(), http.StatusBadRequest)
@@ -144,6 +518,7 @@ func UploadAvatar(w http.ResponseWriter, r *http.Request) {
 	var isFound bool
 	var err error
 
+	principal := helpers.GetPrincipal(r)
 	file, header, err := fc.GetRequestFile("avatar", r)
 
 	if err != nil {
@@ -157,7 +532,7 @@ func UploadAvatar(w http.ResponseWriter, r *http.Request) {
 	if isRemote {
 		var profile req.User
 
-		profile, isFound, err = db.DbConn.GetProfile(jwt.UserId)
+		profile, isFound, err = db.DbConn.GetProfile(principal.Id)
 
 		if err != nil {
 			http.Error(w, "User not found", http.StatusNotFound)
@@ -171,9 +546,9 @@ func UploadAvatar(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		filename, err = uploadRemote(file, profile.Avatar, "avatar")
+		filename, err = uploadRemote(principal.Id, file, profile.Avatar, "avatar")
 	} else {
-		filename, err = uploadLocal("uploads/avatars", header, file)
+		filename, err = uploadLocal(principal.Id, "uploads/avatars", header, file)
 	}
 
 	if err != nil {
@@ -186,7 +561,7 @@ func UploadAvatar(w http.ResponseWriter, r *http.Request) {
 		Avatar: filename,
 	}
 
-	err = saveToDB(user)
+	err = saveToDB(principal.Id, user)
 
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -203,6 +578,7 @@ func UploadBanner(w http.ResponseWriter, r *http.Request) {
 	var isFound bool
 	var err error
 
+	principal := helpers.GetPrincipal(r)
 	file, header, err := fc.GetRequestFile("banner", r)
 
 	if err != nil {
@@ -216,7 +592,7 @@ func UploadBanner(w http.ResponseWriter, r *http.Request) {
 	if isRemote {
 		var profile req.User
 
-		profile, isFound, err = db.DbConn.GetProfile(jwt.UserId)
+		profile, isFound, err = db.DbConn.GetProfile(principal.Id)
 
 		if err != nil {
 			http.Error(w, "User not found", http.StatusNotFound)
@@ -230,9 +606,9 @@ func UploadBanner(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		filename, err = uploadRemote(file, profile.Banner, "banner")
+		filename, err = uploadRemote(principal.Id, file, profile.Banner, "banner")
 	} else {
-		filename, err = uploadLocal("uploads/banners", header, file)
+		filename, err = uploadLocal(principal.Id, "uploads/banners", header, file)
 	}
 
 	if err != nil {
@@ -245,7 +621,7 @@ func UploadBanner(w http.ResponseWriter, r *http.Request) {
 		Banner: filename,
 	}
 
-	err = saveToDB(user)
+	err = saveToDB(principal.Id, user)
 
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -338,9 +714,184 @@ func GetBanner(w http.ResponseWriter, r *http.Request) {
 
 // region "Helpers"
 
-func uploadLocal(filepath string, header *multipart.FileHeader, file multipart.File) (string, error) {
+func isLocked(w http.ResponseWriter, keys ...string) bool {
+	lockout, err := throttle.GetLockout(keys...)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return true
+	}
+
+	if lockout > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(lockout.Seconds()))))
+		http.Error(w, "Too many failed attempts, try again later", http.StatusTooManyRequests)
+
+		return true
+	}
+
+	return false
+}
+
+func registerFailure(keys ...string) {
+	err := throttle.RegisterFailure(keys...)
+
+	if err != nil {
+		log.Println("Error registering the failed attempt: " + err.Error())
+	}
+}
+
+func resetFailures(keys ...string) {
+	err := throttle.Reset(keys...)
+
+	if err != nil {
+		log.Println("Error resetting the failed attempts: " + err.Error())
+	}
+}
+
+func createActionToken(userId string, action string, ttl time.Duration) (string, error) {
+	// Only the last token sent to the user is valid
+	err := db.DbConn.DeleteActionTokens(userId, action)
+
+	if err != nil {
+		return "", err
+	}
+
+	token, err := helpers.GenerateToken(32)
+
+	if err != nil {
+		return "", err
+	}
+
+	actionToken := req.ActionToken{
+		Hash:      helpers.HashToken(token),
+		UserId:    userId,
+		Action:    action,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	err = db.DbConn.InsertActionToken(actionToken)
+
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func sendVerificationEmail(userId string, email string) error {
+	ttl := helpers.GetDurationEnv("VERIFY_TOKEN_TTL", 24*time.Hour)
+	token, err := createActionToken(userId, req.ActionVerifyEmail, ttl)
+
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Use the following token to verify your email address: %s\n\nThe token expires in %s.", token, ttl)
+	body += getActionLink("verify-email", token)
+
+	return mailer.MailConn.Send(email, "Verify your email address", body)
+}
+
+func getActionLink(path string, token string) string {
+	appUrl := os.Getenv("APP_URL")
+
+	if len(appUrl) < 1 {
+		return ""
+	}
+
+	return fmt.Sprintf("\n\n%s/%s?token=%s", strings.TrimSuffix(appUrl, "/"), path, url.QueryEscape(token))
+}
+
+func getRefreshToken(r *http.Request, refreshToken req.RefreshToken) string {
+	if len(refreshToken.RefreshToken) > 0 {
+		return refreshToken.RefreshToken
+	}
+
+	cookie, err := r.Cookie(helpers.RefreshCookieName)
+
+	if err != nil {
+		return ""
+	}
+
+	return cookie.Value
+}
+
+func setNewSessionToResponse(w http.ResponseWriter, r *http.Request, user req.User) {
+	// Logging in during the grace period cancels the scheduled deletion
+	if !user.DeleteAt.IsZero() {
+		err := db.DbConn.ScheduleUserDeletion(user.Id, time.Time{})
+
+		if err != nil {
+			http.Error(w, "An error has occurred when trying to cancel the deletion: "+err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	sessionId, err := createSession(r, user.Id)
+
+	if err != nil {
+		http.Error(w, "An error has occurred when trying to create the session: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	setTokensToResponse(w, user, sessionId)
+}
+
+func setTokensToResponse(w http.ResponseWriter, user req.User, sessionId string) {
+	jwtKey, err := jwt.GenerateJWT(user, sessionId)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	refreshKey, err := jwt.GenerateRefreshJWT(user, sessionId)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	response := res.LoginResponse{
+		Token:        jwtKey,
+		RefreshToken: refreshKey,
+	}
+
+	csrfToken, err := helpers.GenerateToken(32)
+
+	if err != nil {
+		http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	now := time.Now()
+
+	// The cookies must be set before writing the status code
+	http.SetCookie(w, helpers.NewCookie(helpers.TokenCookieName, jwtKey, now.Add(jwt.GetAccessTTL()), true))
+	http.SetCookie(w, helpers.NewCookie(helpers.RefreshCookieName, refreshKey, now.Add(jwt.GetRefreshTTL()), true))
+	http.SetCookie(w, helpers.NewCookie(helpers.CsrfCookieName, csrfToken, now.Add(jwt.GetRefreshTTL()), false))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func expireTokenCookies(w http.ResponseWriter) {
+	http.SetCookie(w, helpers.NewExpiredCookie(helpers.TokenCookieName))
+	http.SetCookie(w, helpers.NewExpiredCookie(helpers.RefreshCookieName))
+	http.SetCookie(w, helpers.NewExpiredCookie(helpers.CsrfCookieName))
+}
+
+func uploadLocal(userId string, filepath string, header *multipart.FileHeader, file multipart.File) (string, error) {
 	extension := strings.Split(header.Filename, ".")[1]
-	filename := fmt.Sprintf("%s.%s", jwt.UserId, extension)
+	filename := fmt.Sprintf("%s.%s", userId, extension)
 	filepath = fmt.Sprintf("%s/%s", filepath, filename)
 
 	err := helpers.UploadFileLocal(filepath, file)
@@ -352,8 +903,8 @@ func uploadLocal(filepath string, header *multipart.FileHeader, file multipart.F
 	return filename, nil
 }
 
-func uploadRemote(file multipart.File, fileUrl string, tag string) (string, error) {
-	publicId := fmt.Sprintf("%s-%s", jwt.UserId, tag)
+func uploadRemote(userId string, file multipart.File, fileUrl string, tag string) (string, error) {
+	publicId := fmt.Sprintf("%s-%s", userId, tag)
 
 	if fileUrl != "" {
 		err := helpers.DestroyRemote(publicId)
@@ -372,8 +923,8 @@ func uploadRemote(file multipart.File, fileUrl string, tag string) (string, erro
 	return fileUrl, nil
 }
 
-func saveToDB(user req.User) error {
-	err := db.DbConn.ModifyRegistry(jwt.UserId, user)
+func saveToDB(userId string, user req.User) error {
+	err := db.DbConn.ModifyRegistry(userId, user)
 
 	if err != nil {
 		return fmt.Errorf("error when saving the file in the DB: %s", err.Error())
@@ -2,6 +2,7 @@ package db
 
 import (
 	"log"
+	"time"
 
 	mr "models/request"
 
@@ -20,21 +21,104 @@ type DbAdapter interface {
 	InsertUser(user mr.User) (string, error)
 	IsUser(email string) (bool, mr.User, error)
 	ModifyRegistry(id string, user mr.User) error
+	ModifyPassword(id string, password string) error
+	VerifyUser(id string) error
+	RevokeUserTokens(id string) error
+	SetTotp(id string, secret string, isEnabled bool) error
+	UseTotpCounter(id string, counter int64) (bool, error)
+	SetRole(id string, role string) error
+	ScheduleUserDeletion(id string, deleteAt time.Time) error
+	GetUsersToDelete(before time.Time) ([]*mr.User, error)
+	DeleteUser(id string) error
 	TryLogin(email string, password string) (mr.User, bool)
 
 	// Tweets
 	DeleteTweet(id string, userId string) error
 	GetTweets(id string, page int64, limit int64) ([]*mr.Tweet, int64, error)
+	GetUserTweets(id string) ([]*mr.Tweet, error)
 	InsertTweet(tweet mr.Tweet) (string, error)
+	InsertRetweet(tweet mr.Tweet) (string, bool, error)
+	DeleteRetweet(userId string, tweetId string) (bool, error)
+	GetTweet(id string) (mr.Tweet, bool, error)
+	GetReplies(id string, page int64, limit int64) ([]*mr.Tweet, int64, error)
+	GetConversation(id string) ([]*mr.Tweet, error)
+	ModifyTweet(tweet mr.Tweet) (bool, error)
+	GetTweetRevisions(id string) ([]*mr.TweetRevision, error)
+	GetHashtagTweets(tag string, page int64, limit int64) ([]*mr.Tweet, int64, error)
+	GetMentionTweets(userId string, page int64, limit int64) ([]*mr.Tweet, int64, error)
+	SearchTweets(terms []string, isNewestFirst bool, page int64, limit int64) ([]*mr.Tweet, int64, error)
+	GetTrends(since time.Time, limit int64) ([]*mr.Trend, error)
+
+	// Likes
+	InsertLike(like mr.Like) (bool, error)
+	DeleteLike(userId string, tweetId string) (bool, error)
+	GetLikedTweetIds(userId string, tweetIds []string) ([]string, error)
+	GetLikedTweets(userId string, page int64, limit int64) ([]*mr.Tweet, int64, error)
+
+	// Bookmarks
+	InsertBookmark(bookmark mr.Bookmark) (bool, error)
+	DeleteBookmark(userId string, tweetId string) (bool, error)
+	GetBookmarkedTweets(userId string, page int64, limit int64) ([]*mr.Tweet, int64, error)
+
+	// Scheduled tweets
+	InsertScheduledTweet(tweet mr.ScheduledTweet) (string, error)
+	GetScheduledTweets(userId string, page int64, limit int64) ([]*mr.ScheduledTweet, int64, error)
+	GetDueScheduledTweets(before time.Time, limit int64) ([]*mr.ScheduledTweet, error)
+	DeleteScheduledTweet(id string, userId string) (mr.ScheduledTweet, bool, error)
+	PublishScheduledTweet(id string, tweet mr.Tweet) (string, bool, error)
+
+	// Drafts
+	InsertDraft(draft mr.Draft) (string, error)
+	GetDrafts(userId string, page int64, limit int64) ([]*mr.Draft, int64, error)
+	GetDraft(id string, userId string) (mr.Draft, bool, error)
+	ModifyDraft(draft mr.Draft) (bool, error)
+	DeleteDraft(id string, userId string) (mr.Draft, bool, error)
+	PublishDraft(id string, userId string, tweet mr.Tweet) (string, bool, error)
 
 	// Relations
 	IsRelation(relation mr.Relation) (bool, mr.Relation, error)
 	InsertRelation(relation mr.Relation) error
 	DeleteRelation(relation mr.Relation) error
 	GetFollowing(id string, page int64, limit int64, search string) ([]*mr.User, int64, error)
+	GetFollowers(id string, page int64, limit int64, search string) ([]*mr.User, int64, error)
 	GetNotFollowing(id string, page int64, limit int64, search string) ([]*mr.User, int64, error)
 	GetFollowingTweets(id string, page int64, limit int64, isOnlyTweets bool) (any, int64, error)
 	GetUsers(id string, page int64, limit int64, search string, searchType string) ([]*mr.User, int64, error)
+
+	// Tokens
+	RevokeToken(token mr.RevokedToken) (bool, error)
+	IsTokenRevoked(tokenId string) (bool, error)
+	InsertActionToken(token mr.ActionToken) error
+	ConsumeActionToken(hash string, action string) (mr.ActionToken, bool, error)
+	DeleteActionTokens(userId string, action string) error
+
+	// Access tokens
+	InsertAccessToken(token mr.AccessToken) (string, error)
+	GetAccessTokens(userId string) ([]*mr.AccessToken, error)
+	GetAccessToken(hash string) (mr.AccessToken, bool, error)
+	DeleteAccessToken(id string, userId string) (bool, error)
+	DeleteAccessTokens(userId string) error
+
+	// Sessions
+	InsertSession(session mr.Session) (string, error)
+	GetSession(id string) (mr.Session, bool, error)
+	GetSessions(userId string) ([]*mr.Session, error)
+	TouchSession(id string, lastSeenAt time.Time, expiresAt time.Time) error
+	DeleteSession(id string, userId string) (bool, error)
+	DeleteSessions(userId string, exceptId string) error
+
+	// Exports
+	InsertExport(export mr.Export) (string, error)
+	GetExport(id string) (mr.Export, bool, error)
+	GetExports(userId string) ([]*mr.Export, error)
+	GetExpiredExports(before time.Time) ([]*mr.Export, error)
+	ModifyExport(export mr.Export) (bool, error)
+	DeleteExport(id string) error
+
+	// Login attempts
+	GetLoginAttempt(key string) (mr.LoginAttempt, bool, error)
+	IncrementLoginAttempt(key string, expiresAt time.Time) (mr.LoginAttempt, error)
+	DeleteLoginAttempt(key string) error
 }
 
 /* DbConn is the connection to the database */
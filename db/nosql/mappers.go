@@ -22,17 +22,25 @@ func getUserModel(requestModel mr.User) (m.User, error) {
 	}
 
 	userModel = m.User{
-		Id:        objId,
-		Name:      requestModel.Name,
-		LastName:  requestModel.LastName,
-		Email:     requestModel.Email,
-		BirthDate: requestModel.BirthDate,
-		Avatar:    requestModel.Avatar,
-		Banner:    requestModel.Banner,
-		Biography: requestModel.Biography,
-		Location:  requestModel.Location,
-		WebSite:   requestModel.WebSite,
-		Password:  requestModel.Password,
+		Id:              objId,
+		Name:            requestModel.Name,
+		LastName:        requestModel.LastName,
+		Email:           requestModel.Email,
+		Handle:          requestModel.Handle,
+		BirthDate:       requestModel.BirthDate,
+		Avatar:          requestModel.Avatar,
+		Banner:          requestModel.Banner,
+		Biography:       requestModel.Biography,
+		Location:        requestModel.Location,
+		WebSite:         requestModel.WebSite,
+		Password:        requestModel.Password,
+		Verified:        requestModel.Verified,
+		TokensRevokedAt: requestModel.TokensRevokedAt,
+		TotpSecret:      requestModel.TotpSecret,
+		TotpEnabled:     requestModel.TotpEnabled,
+		TotpLastCounter: requestModel.TotpLastCounter,
+		Role:            requestModel.Role,
+		DeleteAt:        requestModel.DeleteAt,
 	}
 
 	return userModel, nil
@@ -41,17 +49,30 @@ func getUserModel(requestModel mr.User) (m.User, error) {
 /* getUserRequest obtains the Request User model */
 func getUserRequest(userModel m.User) mr.User {
 	requestModel := mr.User{
-		Id:        userModel.Id.Hex(),
-		Name:      userModel.Name,
-		LastName:  userModel.LastName,
-		Email:     userModel.Email,
-		BirthDate: userModel.BirthDate,
-		Avatar:    userModel.Avatar,
-		Banner:    userModel.Banner,
-		Biography: userModel.Biography,
-		Location:  userModel.Location,
-		WebSite:   userModel.WebSite,
-		Password:  userModel.Password,
+		Id:              userModel.Id.Hex(),
+		Name:            userModel.Name,
+		LastName:        userModel.LastName,
+		Email:           userModel.Email,
+		Handle:          userModel.Handle,
+		BirthDate:       userModel.BirthDate,
+		Avatar:          userModel.Avatar,
+		Banner:          userModel.Banner,
+		Biography:       userModel.Biography,
+		Location:        userModel.Location,
+		WebSite:         userModel.WebSite,
+		Password:        userModel.Password,
+		Verified:        userModel.Verified,
+		TokensRevokedAt: userModel.TokensRevokedAt,
+		TotpSecret:      userModel.TotpSecret,
+		TotpEnabled:     userModel.TotpEnabled,
+		TotpLastCounter: userModel.TotpLastCounter,
+		Role:            userModel.Role,
+		DeleteAt:        userModel.DeleteAt,
+	}
+
+	// The users registered before the roles were added have no role
+	if len(requestModel.Role) < 1 {
+		requestModel.Role = mr.RoleUser
 	}
 
 	return requestModel
@@ -73,12 +94,46 @@ func getTweetModel(requestModel mr.Tweet) (m.Tweet, error) {
 		return tweetModel, err
 	}
 
+	objInReplyTo, err := getObjectId(requestModel.InReplyTo)
+
+	if err != nil {
+		return tweetModel, err
+	}
+
+	objConversationId, err := getObjectId(requestModel.ConversationId)
+
+	if err != nil {
+		return tweetModel, err
+	}
+
+	objRetweetOf, err := getObjectId(requestModel.RetweetOf)
+
+	if err != nil {
+		return tweetModel, err
+	}
+
+	objQuotedTweetId, err := getObjectId(requestModel.QuotedTweetId)
+
+	if err != nil {
+		return tweetModel, err
+	}
+
 	tweetModel = m.Tweet{
-		Id:      objId,
-		UserId:  objUserId,
-		Message: requestModel.Message,
-		Date:    requestModel.Date,
-		Active:  requestModel.Active,
+		Id:             objId,
+		UserId:         objUserId,
+		Message:        requestModel.Message,
+		Date:           requestModel.Date,
+		Active:         requestModel.Active,
+		InReplyTo:      objInReplyTo,
+		ConversationId: objConversationId,
+		ReplyCount:     requestModel.ReplyCount,
+		RetweetOf:      objRetweetOf,
+		QuotedTweetId:  objQuotedTweetId,
+		RetweetCount:   requestModel.RetweetCount,
+		QuoteCount:     requestModel.QuoteCount,
+		LikeCount:      requestModel.LikeCount,
+		EditedAt:       requestModel.EditedAt,
+		Media:          requestModel.Media,
 	}
 
 	return tweetModel, nil
@@ -87,11 +142,165 @@ func getTweetModel(requestModel mr.Tweet) (m.Tweet, error) {
 /* getTweetRequest obtains the Request Tweet model */
 func getTweetRequest(tweetModel m.Tweet) mr.Tweet {
 	requestModel := mr.Tweet{
-		Id:      tweetModel.Id.Hex(),
-		UserId:  tweetModel.UserId.Hex(),
+		Id:             tweetModel.Id.Hex(),
+		UserId:         tweetModel.UserId.Hex(),
+		Message:        tweetModel.Message,
+		Date:           tweetModel.Date,
+		Active:         tweetModel.Active,
+		InReplyTo:      getHexId(tweetModel.InReplyTo),
+		ConversationId: getHexId(tweetModel.ConversationId),
+		ReplyCount:     tweetModel.ReplyCount,
+		RetweetOf:      getHexId(tweetModel.RetweetOf),
+		QuotedTweetId:  getHexId(tweetModel.QuotedTweetId),
+		RetweetCount:   tweetModel.RetweetCount,
+		QuoteCount:     tweetModel.QuoteCount,
+		LikeCount:      tweetModel.LikeCount,
+		Edited:         tweetModel.EditedAt != nil,
+		EditedAt:       tweetModel.EditedAt,
+		Media:          tweetModel.Media,
+	}
+
+	return requestModel
+}
+
+/* getTrendRequest obtains the Request Trend model */
+func getTrendRequest(countModel m.HashtagCount) mr.Trend {
+	requestModel := mr.Trend{
+		Tag:   countModel.Tag,
+		Count: countModel.Count,
+	}
+
+	return requestModel
+}
+
+/* getTweetRevisionModel obtains the DB TweetRevision model with the current message of a tweet */
+func getTweetRevisionModel(tweetModel m.Tweet) m.TweetRevision {
+	revisionModel := m.TweetRevision{
+		TweetId: tweetModel.Id,
 		Message: tweetModel.Message,
 		Date:    tweetModel.Date,
-		Active:  tweetModel.Active,
+	}
+
+	// The date of a revision is the one when its message was written
+	if tweetModel.EditedAt != nil {
+		revisionModel.Date = *tweetModel.EditedAt
+	}
+
+	return revisionModel
+}
+
+/* getTweetRevisionRequest obtains the Request TweetRevision model */
+func getTweetRevisionRequest(revisionModel m.TweetRevision) mr.TweetRevision {
+	requestModel := mr.TweetRevision{
+		Id:      revisionModel.Id.Hex(),
+		TweetId: revisionModel.TweetId.Hex(),
+		Message: revisionModel.Message,
+		Date:    revisionModel.Date,
+	}
+
+	return requestModel
+}
+
+/* getScheduledTweetModel obtains the DB ScheduledTweet model */
+func getScheduledTweetModel(requestModel mr.ScheduledTweet) (m.ScheduledTweet, error) {
+	var scheduledModel m.ScheduledTweet
+
+	ids := []string{
+		requestModel.Id,
+		requestModel.UserId,
+		requestModel.InReplyTo,
+		requestModel.ConversationId,
+		requestModel.QuotedTweetId,
+	}
+	objIds := make([]primitive.ObjectID, len(ids))
+
+	for i, id := range ids {
+		objId, err := getObjectId(id)
+
+		if err != nil {
+			return scheduledModel, err
+		}
+
+		objIds[i] = objId
+	}
+
+	scheduledModel = m.ScheduledTweet{
+		Id:             objIds[0],
+		UserId:         objIds[1],
+		Message:        requestModel.Message,
+		InReplyTo:      objIds[2],
+		ConversationId: objIds[3],
+		QuotedTweetId:  objIds[4],
+		Media:          requestModel.Media,
+		PublishAt:      requestModel.PublishAt,
+		Date:           requestModel.Date,
+	}
+
+	return scheduledModel, nil
+}
+
+/* getScheduledTweetRequest obtains the Request ScheduledTweet model */
+func getScheduledTweetRequest(scheduledModel m.ScheduledTweet) mr.ScheduledTweet {
+	requestModel := mr.ScheduledTweet{
+		Id:             scheduledModel.Id.Hex(),
+		UserId:         scheduledModel.UserId.Hex(),
+		Message:        scheduledModel.Message,
+		InReplyTo:      getHexId(scheduledModel.InReplyTo),
+		ConversationId: getHexId(scheduledModel.ConversationId),
+		QuotedTweetId:  getHexId(scheduledModel.QuotedTweetId),
+		Media:          scheduledModel.Media,
+		PublishAt:      scheduledModel.PublishAt,
+		Date:           scheduledModel.Date,
+	}
+
+	return requestModel
+}
+
+/* getDraftModel obtains the DB Draft model */
+func getDraftModel(requestModel mr.Draft) (m.Draft, error) {
+	var draftModel m.Draft
+
+	ids := []string{
+		requestModel.Id,
+		requestModel.UserId,
+		requestModel.InReplyTo,
+		requestModel.QuotedTweetId,
+	}
+	objIds := make([]primitive.ObjectID, len(ids))
+
+	for i, id := range ids {
+		objId, err := getObjectId(id)
+
+		if err != nil {
+			return draftModel, err
+		}
+
+		objIds[i] = objId
+	}
+
+	draftModel = m.Draft{
+		Id:            objIds[0],
+		UserId:        objIds[1],
+		Message:       requestModel.Message,
+		InReplyTo:     objIds[2],
+		QuotedTweetId: objIds[3],
+		Media:         requestModel.Media,
+		Date:          requestModel.Date,
+	}
+
+	return draftModel, nil
+}
+
+/* getDraftRequest obtains the Request Draft model */
+func getDraftRequest(draftModel m.Draft) mr.Draft {
+	requestModel := mr.Draft{
+		Id:            draftModel.Id.Hex(),
+		UserId:        draftModel.UserId.Hex(),
+		Message:       draftModel.Message,
+		InReplyTo:     getHexId(draftModel.InReplyTo),
+		QuotedTweetId: getHexId(draftModel.QuotedTweetId),
+		Media:         draftModel.Media,
+		Date:          draftModel.Date,
 	}
 
 	return requestModel
@@ -144,6 +353,244 @@ func getUserTweetRequest(userTweetModel m.UserTweet) mr.UserTweet {
 	requestModel.Tweet.Id = userTweetModel.Tweet.Id.Hex()
 	requestModel.Tweet.Message = userTweetModel.Tweet.Message
 	requestModel.Tweet.Date = userTweetModel.Tweet.Date
+	requestModel.Tweet.InReplyTo = getHexId(userTweetModel.Tweet.InReplyTo)
+	requestModel.Tweet.ConversationId = getHexId(userTweetModel.Tweet.ConversationId)
+	requestModel.Tweet.ReplyCount = userTweetModel.Tweet.ReplyCount
+	requestModel.Tweet.RetweetOf = getHexId(userTweetModel.Tweet.RetweetOf)
+	requestModel.Tweet.QuotedTweetId = getHexId(userTweetModel.Tweet.QuotedTweetId)
+	requestModel.Tweet.RetweetCount = userTweetModel.Tweet.RetweetCount
+	requestModel.Tweet.QuoteCount = userTweetModel.Tweet.QuoteCount
+	requestModel.Tweet.LikeCount = userTweetModel.Tweet.LikeCount
+	requestModel.Tweet.Edited = userTweetModel.Tweet.EditedAt != nil
+	requestModel.Tweet.EditedAt = userTweetModel.Tweet.EditedAt
+	requestModel.Tweet.Media = userTweetModel.Tweet.Media
+	requestModel.Tweet.Active = userTweetModel.Tweet.Active
+
+	return requestModel
+}
+
+/* getRevokedTokenModel obtains the DB RevokedToken model */
+func getRevokedTokenModel(requestModel mr.RevokedToken) (m.RevokedToken, error) {
+	var tokenModel m.RevokedToken
+
+	objUserId, err := getObjectId(requestModel.UserId)
+
+	if err != nil {
+		return tokenModel, err
+	}
+
+	tokenModel = m.RevokedToken{
+		TokenId:   requestModel.TokenId,
+		UserId:    objUserId,
+		ExpiresAt: requestModel.ExpiresAt,
+	}
+
+	return tokenModel, nil
+}
+
+/* getActionTokenModel obtains the DB ActionToken model */
+func getActionTokenModel(requestModel mr.ActionToken) (m.ActionToken, error) {
+	var tokenModel m.ActionToken
+
+	objUserId, err := getObjectId(requestModel.UserId)
+
+	if err != nil {
+		return tokenModel, err
+	}
+
+	tokenModel = m.ActionToken{
+		Hash:      requestModel.Hash,
+		UserId:    objUserId,
+		Action:    requestModel.Action,
+		ExpiresAt: requestModel.ExpiresAt,
+	}
+
+	return tokenModel, nil
+}
+
+/* getActionTokenRequest obtains the Request ActionToken model */
+func getActionTokenRequest(tokenModel m.ActionToken) mr.ActionToken {
+	requestModel := mr.ActionToken{
+		Hash:      tokenModel.Hash,
+		UserId:    tokenModel.UserId.Hex(),
+		Action:    tokenModel.Action,
+		ExpiresAt: tokenModel.ExpiresAt,
+	}
+
+	return requestModel
+}
+
+/* getLoginAttemptRequest obtains the Request LoginAttempt model */
+func getLoginAttemptRequest(attemptModel m.LoginAttempt) mr.LoginAttempt {
+	requestModel := mr.LoginAttempt{
+		Key:           attemptModel.Key,
+		Failures:      attemptModel.Failures,
+		LastFailureAt: attemptModel.LastFailureAt,
+		ExpiresAt:     attemptModel.ExpiresAt,
+	}
+
+	return requestModel
+}
+
+/* getAccessTokenModel obtains the DB AccessToken model */
+func getAccessTokenModel(requestModel mr.AccessToken) (m.AccessToken, error) {
+	var tokenModel m.AccessToken
+
+	objUserId, err := getObjectId(requestModel.UserId)
+
+	if err != nil {
+		return tokenModel, err
+	}
+
+	tokenModel = m.AccessToken{
+		UserId:    objUserId,
+		Name:      requestModel.Name,
+		Hash:      requestModel.Hash,
+		Scopes:    requestModel.Scopes,
+		CreatedAt: requestModel.CreatedAt,
+		ExpiresAt: requestModel.ExpiresAt,
+	}
+
+	return tokenModel, nil
+}
+
+/* getAccessTokenRequest obtains the Request AccessToken model */
+func getAccessTokenRequest(tokenModel m.AccessToken) mr.AccessToken {
+	requestModel := mr.AccessToken{
+		Id:        tokenModel.Id.Hex(),
+		UserId:    tokenModel.UserId.Hex(),
+		Name:      tokenModel.Name,
+		Hash:      tokenModel.Hash,
+		Scopes:    tokenModel.Scopes,
+		CreatedAt: tokenModel.CreatedAt,
+		ExpiresAt: tokenModel.ExpiresAt,
+	}
+
+	return requestModel
+}
+
+/* getLikeModel obtains the DB Like model */
+func getLikeModel(requestModel mr.Like) (m.Like, error) {
+	var likeModel m.Like
+
+	objUserId, err := getObjectId(requestModel.UserId)
+
+	if err != nil {
+		return likeModel, err
+	}
+
+	objTweetId, err := getObjectId(requestModel.TweetId)
+
+	if err != nil {
+		return likeModel, err
+	}
+
+	likeModel = m.Like{
+		UserId:  objUserId,
+		TweetId: objTweetId,
+		Date:    requestModel.Date,
+	}
+
+	return likeModel, nil
+}
+
+/* getBookmarkModel obtains the DB Bookmark model */
+func getBookmarkModel(requestModel mr.Bookmark) (m.Bookmark, error) {
+	var bookmarkModel m.Bookmark
+
+	objUserId, err := getObjectId(requestModel.UserId)
+
+	if err != nil {
+		return bookmarkModel, err
+	}
+
+	objTweetId, err := getObjectId(requestModel.TweetId)
+
+	if err != nil {
+		return bookmarkModel, err
+	}
+
+	bookmarkModel = m.Bookmark{
+		UserId:  objUserId,
+		TweetId: objTweetId,
+		Date:    requestModel.Date,
+	}
+
+	return bookmarkModel, nil
+}
+
+/* getSessionModel obtains the DB Session model */
+func getSessionModel(requestModel mr.Session) (m.Session, error) {
+	var sessionModel m.Session
+
+	objUserId, err := getObjectId(requestModel.UserId)
+
+	if err != nil {
+		return sessionModel, err
+	}
+
+	sessionModel = m.Session{
+		UserId:     objUserId,
+		UserAgent:  requestModel.UserAgent,
+		Ip:         requestModel.Ip,
+		CreatedAt:  requestModel.CreatedAt,
+		LastSeenAt: requestModel.LastSeenAt,
+		ExpiresAt:  requestModel.ExpiresAt,
+	}
+
+	return sessionModel, nil
+}
+
+/* getSessionRequest obtains the Request Session model */
+func getSessionRequest(sessionModel m.Session) mr.Session {
+	requestModel := mr.Session{
+		Id:         sessionModel.Id.Hex(),
+		UserId:     sessionModel.UserId.Hex(),
+		UserAgent:  sessionModel.UserAgent,
+		Ip:         sessionModel.Ip,
+		CreatedAt:  sessionModel.CreatedAt,
+		LastSeenAt: sessionModel.LastSeenAt,
+		ExpiresAt:  sessionModel.ExpiresAt,
+	}
+
+	return requestModel
+}
+
+/* getExportModel obtains the DB Export model */
+func getExportModel(requestModel mr.Export) (m.Export, error) {
+	var exportModel m.Export
+
+	objUserId, err := getObjectId(requestModel.UserId)
+
+	if err != nil {
+		return exportModel, err
+	}
+
+	exportModel = m.Export{
+		UserId:         objUserId,
+		Status:         requestModel.Status,
+		Error:          requestModel.Error,
+		TokenHash:      requestModel.TokenHash,
+		TokenExpiresAt: requestModel.TokenExpiresAt,
+		CreatedAt:      requestModel.CreatedAt,
+		ExpiresAt:      requestModel.ExpiresAt,
+	}
+
+	return exportModel, nil
+}
+
+/* getExportRequest obtains the Request Export model */
+func getExportRequest(exportModel m.Export) mr.Export {
+	requestModel := mr.Export{
+		Id:             exportModel.Id.Hex(),
+		UserId:         exportModel.UserId.Hex(),
+		Status:         exportModel.Status,
+		Error:          exportModel.Error,
+		TokenHash:      exportModel.TokenHash,
+		TokenExpiresAt: exportModel.TokenExpiresAt,
+		CreatedAt:      exportModel.CreatedAt,
+		ExpiresAt:      exportModel.ExpiresAt,
+	}
 
 	return requestModel
 }
@@ -152,6 +599,14 @@ func getUserTweetRequest(userTweetModel m.UserTweet) mr.UserTweet {
 
 // region "Helpers"
 
+func getHexId(objId primitive.ObjectID) string {
+	if objId.IsZero() {
+		return ""
+	}
+
+	return objId.Hex()
+}
+
 func getObjectId(id string) (primitive.ObjectID, error) {
 	var objId primitive.ObjectID
 	var err error
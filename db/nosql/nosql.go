@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"helpers"
+	"media"
 	m "models/nosql"
 	mr "models/request"
 
@@ -40,7 +43,16 @@ func (db *DbNoSql) Connect() error {
 
 	db.Connection = client
 
-	return nil
+	err = db.createIndexes()
+
+	if err != nil {
+		return err
+	}
+
+	// The users registered before the handles were added can not be mentioned until they have one
+	err = db.setMissingHandles()
+
+	return err
 }
 
 /* IsConnection makes a ping to the Database */
@@ -107,6 +119,13 @@ func (db *DbNoSql) InsertUser(user mr.User) (string, error) {
 	col := getCollection(db, "twittor", "users")
 
 	user.Password, _ = encryptPassword(user.Password)
+	handle, err := db.getAvailableHandle(helpers.GetHandle(user.Email))
+
+	if err != nil {
+		return "", err
+	}
+
+	user.Handle = handle
 	userModel, err := getUserModel(user)
 
 	if err != nil {
@@ -128,7 +147,7 @@ func (db *DbNoSql) InsertUser(user mr.User) (string, error) {
 	result := res.(*mongo.InsertOneResult)
 	objID, _ := result.InsertedID.(primitive.ObjectID)
 
-	return objID.String(), nil
+	return objID.Hex(), nil
 }
 
 /* IsUser checks that the user already exists in the DB */
@@ -215,614 +234,3133 @@ func (db *DbNoSql) ModifyRegistry(id string, user mr.User) error {
 	return err
 }
 
-/* TryLogin makes the login to the DB */
-func (db *DbNoSql) TryLogin(email string, password string) (mr.User, bool) {
-	var requestModel mr.User
-
-	isFound, requestModel, err := db.IsUser(email)
+/* ModifyPassword encrypts and modifies the password of an user in the DB */
+func (db *DbNoSql) ModifyPassword(id string, password string) error {
+	objId, err := getObjectId(id)
 
-	if err != nil || !isFound {
-		return requestModel, false
+	if err != nil {
+		return err
 	}
 
-	passwordBytes := []byte(password)
-	passwordDB := []byte(requestModel.Password)
-	err = bcrypt.CompareHashAndPassword(passwordDB, passwordBytes)
+	encryptedPassword, err := encryptPassword(password)
 
 	if err != nil {
-		return requestModel, false
+		return err
 	}
 
-	return requestModel, true
-}
+	col := getCollection(db, "twittor", "users")
+	filter := bson.M{"_id": objId}
+	updateString := bson.M{
+		"$set": bson.M{"password": encryptedPassword},
+	}
 
-// endregion
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, updateString)
 
-// region "Tweets"
+		return result, err
+	}
 
-/* DeleteTweet deletes a tweet in the DB */
-func (db *DbNoSql) DeleteTweet(id string, userId string) error {
-	err := db.deleteTweetLogical(id, userId)
+	_, err = db.executeTransaction(callback)
 
 	return err
 }
 
-/* Get gets an user's tweets from the DB */
-func (db *DbNoSql) GetTweets(id string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
-	var results []*mr.Tweet
-	var tweetsDbResults []*m.Tweet
-
+/* VerifyUser marks the email address of an user as verified in the DB */
+func (db *DbNoSql) VerifyUser(id string) error {
 	objId, err := getObjectId(id)
 
 	if err != nil {
-		return results, 0, err
+		return err
 	}
 
-	col := getCollection(db, "twittor", "tweet")
-	condition := bson.M{
-		"userId": objId,
-		"active": true,
+	col := getCollection(db, "twittor", "users")
+	filter := bson.M{"_id": objId}
+	updateString := bson.M{
+		"$set": bson.M{"verified": true},
 	}
 
-	ctxCount, cancelCount := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
-
-	defer cancelCount()
-
-	total, err := col.CountDocuments(ctxCount, condition)
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, updateString)
 
-	if err != nil {
-		return results, total, err
+		return result, err
 	}
 
-	opts := options.Find()
-
-	opts.SetSort(bson.D{{Key: "date", Value: -1}})
-	opts.SetSkip((page - 1) * limit)
-	opts.SetLimit(limit)
-
-	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+	_, err = db.executeTransaction(callback)
 
-	defer cancelFind()
+	return err
+}
 
-	cursor, err := col.Find(ctxFind, condition, opts)
+/* RevokeUserTokens invalidates all the tokens issued to an user until now */
+func (db *DbNoSql) RevokeUserTokens(id string) error {
+	objId, err := getObjectId(id)
 
 	if err != nil {
-		return results, total, err
+		return err
 	}
 
-	ctxCursor := context.TODO()
-
-	defer cursor.Close(ctxCursor)
+	col := getCollection(db, "twittor", "users")
+	filter := bson.M{"_id": objId}
+	updateString := bson.M{
+		"$set": bson.M{"tokensRevokedAt": time.Now()},
+	}
 
-	err = cursor.All(ctxCursor, &tweetsDbResults)
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, updateString)
 
-	if err != nil {
-		return results, total, err
+		return result, err
 	}
 
-	for _, tweetModel := range tweetsDbResults {
-		tweetRequest := getTweetRequest(*tweetModel)
-		results = append(results, &tweetRequest)
-	}
+	_, err = db.executeTransaction(callback)
 
-	return results, total, nil
+	return err
 }
 
-/* InsertTweet inserts a tweet in the DB */
-func (db *DbNoSql) InsertTweet(tweet mr.Tweet) (string, error) {
-	tweetModel, err := getTweetModel(tweet)
+/* SetTotp sets the two-factor authentication secret of an user in the DB */
+func (db *DbNoSql) SetTotp(id string, secret string, isEnabled bool) error {
+	objId, err := getObjectId(id)
 
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	col := getCollection(db, "twittor", "tweet")
-	callback := func(sessCtx mongo.SessionContext) (any, error) {
-		result, err := col.InsertOne(sessCtx, tweetModel)
-
-		return result, err
+	col := getCollection(db, "twittor", "users")
+	filter := bson.M{"_id": objId}
+	updateString := bson.M{
+		"$set": bson.M{
+			"totpSecret":  secret,
+			"totpEnabled": isEnabled,
+		},
 	}
 
-	res, err := db.executeTransaction(callback)
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, updateString)
 
-	if err != nil {
-		return "", err
+		return result, err
 	}
 
-	result := res.(*mongo.InsertOneResult)
-	objId := result.InsertedID.(primitive.ObjectID)
+	_, err = db.executeTransaction(callback)
 
-	// The same goes with objId.Hex()
-	return objId.String(), nil
+	return err
 }
 
-// endregion
-
-// region "Relations"
-
-/* IsRelation obtains a relation from the DB if exist */
-func (db *DbNoSql) IsRelation(relation mr.Relation) (bool, mr.Relation, error) {
-	var result mr.Relation
-
-	relationModel, err := getRelationModel(relation)
+/* UseTotpCounter saves the counter of the last two-factor code accepted, it returns false if a code of that counter or a later one had already been used */
+func (db *DbNoSql) UseTotpCounter(id string, counter int64) (bool, error) {
+	objId, err := getObjectId(id)
 
 	if err != nil {
-		return false, result, err
+		return false, err
 	}
 
-	col := getCollection(db, "twittor", "relation")
-	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+	col := getCollection(db, "twittor", "users")
+	filter := bson.M{
+		"_id": objId,
+		"$or": []bson.M{
+			{"totpLastCounter": bson.M{"$lt": counter}},
+			{"totpLastCounter": bson.M{"$exists": false}},
+		},
+	}
+	updateString := bson.M{
+		"$set": bson.M{"totpLastCounter": counter},
+	}
 
-	defer cancel()
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, updateString)
 
-	condition := bson.M{
-		"userId":         relationModel.UserId,
-		"userRelationId": relationModel.UserRelationId,
+		return result, err
 	}
 
-	err = col.FindOne(ctx, condition).Decode(&relationModel)
+	res, err := db.executeTransaction(callback)
 
-	if err != nil && err == mongo.ErrNoDocuments {
-		return false, result, nil
+	if err != nil {
+		return false, err
 	}
 
-	result = getRelationRequest(relationModel)
-
-	return true, result, err
+	return res.(*mongo.UpdateResult).ModifiedCount > 0, nil
 }
 
-/* InsertRelation creates a relation into the DB */
-func (db *DbNoSql) InsertRelation(relation mr.Relation) error {
-	col := getCollection(db, "twittor", "relation")
-	_, isFound, err := db.GetProfile(relation.UserRelationId)
+/* SetRole sets the role of an user */
+func (db *DbNoSql) SetRole(id string, role string) error {
+	objId, err := getObjectId(id)
 
 	if err != nil {
 		return err
 	}
 
-	if !isFound {
-		return errors.New("no registry found in the DB")
+	col := getCollection(db, "twittor", "users")
+	filter := bson.M{"_id": objId}
+	updateString := bson.M{
+		"$set": bson.M{"role": role},
 	}
 
-	isFound, relationDb, err := db.IsRelation(relation)
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, updateString)
 
-	if err != nil {
-		return err
+		return result, err
 	}
 
-	if !isFound {
-		relationModel, err := getRelationModel(relation)
-
-		if err != nil {
-			return err
-		}
-
-		callback := func(sessCtx mongo.SessionContext) (any, error) {
-			result, err := col.InsertOne(sessCtx, relationModel)
+	_, err = db.executeTransaction(callback)
 
-			return result, err
-		}
+	return err
+}
 
-		_, err = db.executeTransaction(callback)
+/* ScheduleUserDeletion sets when an user will be deleted, a zero deleteAt cancels the deletion */
+func (db *DbNoSql) ScheduleUserDeletion(id string, deleteAt time.Time) error {
+	objId, err := getObjectId(id)
 
+	if err != nil {
 		return err
 	}
 
-	if relationDb.Active {
-		return fmt.Errorf("the relation with the user id = %s already exists", relation.UserRelationId)
+	col := getCollection(db, "twittor", "users")
+	filter := bson.M{"_id": objId}
+	updateString := bson.M{
+		"$set": bson.M{"deleteAt": deleteAt},
 	}
 
-	err = db.updateRelation(relationDb, true)
+	if deleteAt.IsZero() {
+		updateString = bson.M{
+			"$unset": bson.M{"deleteAt": ""},
+		}
+	}
 
-	return err
-}
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, updateString)
 
-/* DeleteRelation deletes a relation in the DB */
-func (db *DbNoSql) DeleteRelation(relation mr.Relation) error {
-	err := db.updateRelation(relation, false)
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
 
 	return err
 }
 
-/* GetUsers gets a list of users */
-func (db *DbNoSql) GetUsers(id string, page int64, limit int64, search string, searchType string) ([]*mr.User, int64, error) {
+/* GetUsersToDelete gets the users with a deletion scheduled before a time */
+func (db *DbNoSql) GetUsersToDelete(before time.Time) ([]*mr.User, error) {
 	var results []*mr.User
-	var total int64
+	var usersDbResults []*m.User
 
 	col := getCollection(db, "twittor", "users")
-
-	query := bson.M{
-		"name": bson.M{"$regex": search, "$options": "im"},
+	condition := bson.M{
+		"deleteAt": bson.M{"$lte": before},
 	}
+	opts := options.Find()
 
-	findOpts := options.Find()
-
-	findOpts.SetSort(bson.D{{Key: "birthDate", Value: -1}})
-	findOpts.SetSkip((page - 1) * limit)
-	findOpts.SetLimit(limit)
+	opts.SetProjection(bson.M{"tweets": 0, "following": 0})
 
 	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
 	defer cancelFind()
 
-	cursor, err := col.Find(ctxFind, query, findOpts)
+	cursor, err := col.Find(ctxFind, condition, opts)
 
 	if err != nil {
-		return results, total, err
+		return results, err
 	}
 
 	ctxCursor := context.TODO()
 
 	defer cursor.Close(ctxCursor)
 
-	var include, isRelation bool
+	err = cursor.All(ctxCursor, &usersDbResults)
 
-	for cursor.Next(ctxCursor) {
-		var result m.User
+	if err != nil {
+		return results, err
+	}
 
-		isRelation = false
-		include = false
+	for _, userModel := range usersDbResults {
+		userRequest := getUserRequest(*userModel)
+		results = append(results, &userRequest)
+	}
 
-		err := cursor.Decode(&result)
+	return results, nil
+}
+
+/* DeleteUser deletes an user with all its data */
+func (db *DbNoSql) DeleteUser(id string) error {
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return err
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		tweetIds, err := getCollection(db, "twittor", "tweet").Distinct(sessCtx, "_id", bson.M{"userId": objId})
 
 		if err != nil {
-			return results, total, err
+			return nil, err
 		}
 
-		userRequest := getUserRequest(result)
+		err = db.deleteUserLikes(sessCtx, objId, tweetIds)
 
-		relationRequest := mr.Relation{
-			UserId:         id,
-			UserRelationId: userRequest.Id,
-			Active:         true,
+		if err != nil {
+			return nil, err
 		}
 
-		isFound, relationDb, err := db.IsRelation(relationRequest)
+		err = db.revertUserTweetCounters(sessCtx, objId)
 
 		if err != nil {
-			return results, total, err
+			return nil, err
 		}
 
-		if isFound {
-			isRelation = relationDb.Active
+		// The revisions of the edited tweets are deleted with them
+		if len(tweetIds) > 0 {
+			_, err = getCollection(db, "twittor", "tweetRevisions").DeleteMany(sessCtx, bson.M{"tweetId": bson.M{"$in": tweetIds}})
+
+			if err != nil {
+				return nil, err
+			}
 		}
 
-		if relationRequest.UserRelationId == id {
-			include = false
-		} else if (searchType == "new" && !isRelation) || (searchType == "follow" && isRelation) {
-			include = true
+		// The tweets and the relations in both directions are deleted with the user
+		_, err = getCollection(db, "twittor", "tweet").DeleteMany(sessCtx, bson.M{"userId": objId})
+
+		if err != nil {
+			return nil, err
 		}
 
-		if include {
-			userRequest.Email = ""
-			userRequest.Password = ""
-			userRequest.Avatar = ""
-			userRequest.Banner = ""
-			userRequest.Biography = ""
-			userRequest.Location = ""
-			userRequest.WebSite = ""
+		// The mentions of the user in other tweets are left as plain text
+		_, err = getCollection(db, "twittor", "tweet").UpdateMany(sessCtx,
+			bson.M{"mentions": objId},
+			bson.M{"$pull": bson.M{"mentions": objId}})
 
-			results = append(results, &userRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = getCollection(db, "twittor", "relation").DeleteMany(sessCtx, bson.M{
+			"$or": []bson.M{
+				{"userId": objId},
+				{"userRelationId": objId},
+			},
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, colName := range []string{"revokedTokens", "actionTokens", "accessTokens", "sessions", "scheduledTweets", "drafts", "bookmarks"} {
+			_, err = getCollection(db, "twittor", colName).DeleteMany(sessCtx, bson.M{"userId": objId})
+
+			if err != nil {
+				return nil, err
+			}
 		}
+
+		result, err := getCollection(db, "twittor", "users").DeleteOne(sessCtx, bson.M{"_id": objId})
+
+		return result, err
 	}
 
-	err = cursor.Err()
+	_, err = db.executeTransaction(callback)
+
+	return err
+}
+
+/* TryLogin makes the login to the DB */
+func (db *DbNoSql) TryLogin(email string, password string) (mr.User, bool) {
+	var requestModel mr.User
+
+	isFound, requestModel, err := db.IsUser(email)
+
+	if err != nil || !isFound {
+		return requestModel, false
+	}
+
+	passwordBytes := []byte(password)
+	passwordDB := []byte(requestModel.Password)
+	err = bcrypt.CompareHashAndPassword(passwordDB, passwordBytes)
 
 	if err != nil {
-		fmt.Println(err.Error())
+		return requestModel, false
+	}
 
-		return results, total, err
+	// The password is encrypted again when the configured cost has been increased
+	cost, err := bcrypt.Cost(passwordDB)
+
+	if err == nil && cost < helpers.GetBcryptCost() {
+		err = db.ModifyPassword(requestModel.Id, password)
+
+		if err != nil {
+			log.Println("Error encrypting the password again: " + err.Error())
+		}
 	}
 
-	// This total only reflects the total returned registries
-	total = int64(len(results))
+	return requestModel, true
+}
 
-	return results, total, nil
+// endregion
+
+// region "Tweets"
+
+/* DeleteTweet deletes a tweet in the DB */
+func (db *DbNoSql) DeleteTweet(id string, userId string) error {
+	err := db.deleteTweetLogical(id, userId)
+
+	return err
 }
 
-/* GetFollowing gets an user's following list */
-func (db *DbNoSql) GetFollowing(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
-	var results []*mr.User
+/* Get gets an user's tweets from the DB */
+func (db *DbNoSql) GetTweets(id string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	var results []*mr.Tweet
+	var tweetsDbResults []*m.Tweet
 
-	objId, _ := getObjectId(id)
+	objId, err := getObjectId(id)
 
-	// region Pipeline
+	if err != nil {
+		return results, 0, err
+	}
 
-	matchId := bson.M{"$match": bson.M{"userId": objId, "active": true}}
-	lookupUsers := bson.M{"$lookup": bson.M{
-		"from":         "users",
-		"localField":   "userRelationId",
-		"foreignField": "_id",
-		"as":           "result"}}
-	projectResult := bson.M{"$project": bson.M{
-		"user": bson.M{"$arrayElemAt": [2]any{"$result", 0}},
-		"_id":  0}}
-	matchName := bson.M{"$match": bson.M{"user.name": bson.M{"$regex": search, "$options": "im"}}}
+	col := getCollection(db, "twittor", "tweet")
+	condition := bson.M{
+		"userId": objId,
+		"active": true,
+	}
 
-	count := bson.M{"$count": "total"}
+	ctxCount, cancelCount := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
-	sort := bson.M{"$sort": bson.M{"user.birthDate": -1}}
-	skip := bson.M{"$skip": (page - 1) * limit}
-	agLimit := bson.M{"$limit": limit}
-	projectUser := bson.M{"$project": bson.M{
-		"_id":       "$user._id",
-		"name":      "$user.name",
-		"lastName":  "$user.lastName",
-		"birthDate": "$user.birthDate"}}
+	defer cancelCount()
 
-	basePipeline := []bson.M{matchId, lookupUsers, projectResult, matchName}
-	countPipeline := append(basePipeline, count)
-	aggPipeline := append(basePipeline, sort, skip, agLimit, projectUser)
+	total, err := col.CountDocuments(ctxCount, condition)
 
-	// endregion
+	if err != nil {
+		return results, total, err
+	}
 
-	dbResults, total, err := getResults[m.User](db, "relation", countPipeline, aggPipeline)
+	opts := options.Find()
 
-	if err == nil {
-		for _, userModel := range dbResults {
-			userRequest := getUserRequest(*userModel)
-			results = append(results, &userRequest)
-		}
+	opts.SetSort(bson.D{{Key: "date", Value: -1}})
+	opts.SetSkip((page - 1) * limit)
+	opts.SetLimit(limit)
+
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, condition, opts)
+
+	if err != nil {
+		return results, total, err
 	}
 
-	return results, total, err
+	ctxCursor := context.TODO()
+
+	defer cursor.Close(ctxCursor)
+
+	err = cursor.All(ctxCursor, &tweetsDbResults)
+
+	if err != nil {
+		return results, total, err
+	}
+
+	for _, tweetModel := range tweetsDbResults {
+		tweetRequest := getTweetRequest(*tweetModel)
+		results = append(results, &tweetRequest)
+	}
+
+	return results, total, nil
 }
 
-/* GetNotFollowing gets an user's not following list */
-func (db *DbNoSql) GetNotFollowing(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
-	var results []*mr.User
+/* GetUserTweets gets all the user's tweets, including the deleted ones */
+func (db *DbNoSql) GetUserTweets(id string) ([]*mr.Tweet, error) {
+	var results []*mr.Tweet
+	var tweetsDbResults []*m.Tweet
 
-	objId, _ := getObjectId(id)
+	objId, err := getObjectId(id)
 
-	// region Pipeline
+	if err != nil {
+		return results, err
+	}
 
-	matchId := bson.M{"$match": bson.M{"_id": objId}}
-	lookupRelation := bson.M{"$lookup": bson.M{
-		"from":         "relation",
-		"localField":   "_id",
-		"foreignField": "userId",
-		"as":           "r",
-		"pipeline": [1]bson.M{{
-			"$match": bson.M{
-				"$expr": bson.M{
-					"$eq": [2]any{"$active", true},
-				},
-			}},
-		}},
+	col := getCollection(db, "twittor", "tweet")
+	condition := bson.M{"userId": objId}
+	opts := options.Find()
+
+	opts.SetSort(bson.D{{Key: "date", Value: -1}})
+
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, condition, opts)
+
+	if err != nil {
+		return results, err
 	}
-	lookupUsers := bson.M{"$lookup": bson.M{
-		"from": "users",
-		"as":   "u",
-		"let":  bson.M{"userId": "$r.userRelationId"},
-		"pipeline": [1]bson.M{{
-			"$match": bson.M{
-				"$expr": bson.M{
-					"$not": bson.M{
-						"$in": [2]string{
-							"$_id",
-							"$$userId",
-						}},
-				}},
-		}},
-	}}
-	projectArray := bson.M{"$project": bson.M{"u": "$u", "_id": 0}}
-	unwind := bson.M{"$unwind": bson.M{
-		"path":                       "$u",
-		"preserveNullAndEmptyArrays": false,
-	}}
-	matchName := bson.M{"$match": bson.M{
-		"u._id":  bson.M{"$ne": objId},
-		"u.name": bson.M{"$regex": search, "$options": "im"},
-	}}
 
-	count := bson.M{"$count": "total"}
+	ctxCursor := context.TODO()
 
-	sort := bson.M{"$sort": bson.M{"u.birthDate": -1}}
-	skip := bson.M{"$skip": (page - 1) * limit}
-	agLimit := bson.M{"$limit": limit}
-	projectUser := bson.M{"$project": bson.M{
-		"_id":       "$u._id",
-		"name":      "$u.name",
-		"lastName":  "$u.lastName",
-		"birthDate": "$u.birthDate",
-	}}
+	defer cursor.Close(ctxCursor)
 
-	basePipeline := []bson.M{matchId, lookupRelation, lookupUsers, projectArray, unwind, matchName}
-	countPipeline := append(basePipeline, count)
-	aggPipeline := append(basePipeline, sort, skip, agLimit, projectUser)
+	err = cursor.All(ctxCursor, &tweetsDbResults)
 
-	// endregion
+	if err != nil {
+		return results, err
+	}
 
-	dbResults, total, err := getResults[m.User](db, "users", countPipeline, aggPipeline)
+	for _, tweetModel := range tweetsDbResults {
+		tweetRequest := getTweetRequest(*tweetModel)
+		results = append(results, &tweetRequest)
+	}
 
-	if err == nil {
-		for _, userModel := range dbResults {
-			userRequest := getUserRequest(*userModel)
-			results = append(results, &userRequest)
+	return results, nil
+}
+
+/* InsertTweet inserts a tweet in the DB */
+func (db *DbNoSql) InsertTweet(tweet mr.Tweet) (string, error) {
+	tweetModel, err := getTweetModel(tweet)
+
+	if err != nil {
+		return "", err
+	}
+
+	id, _, err := db.insertTweet(tweetModel, nil)
+
+	return id, err
+}
+
+/* InsertRetweet inserts a retweet in the DB, it returns false if the user had already retweeted the tweet */
+func (db *DbNoSql) InsertRetweet(tweet mr.Tweet) (string, bool, error) {
+	tweetModel, err := getTweetModel(tweet)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	// The unique index only allows an active retweet of a tweet by user
+	id, _, err := db.insertTweet(tweetModel, nil)
+
+	if mongo.IsDuplicateKeyError(err) {
+		return "", false, nil
+	}
+
+	return id, err == nil, err
+}
+
+/* DeleteRetweet deletes the user's retweet of a tweet, it returns false if there was not any */
+func (db *DbNoSql) DeleteRetweet(userId string, tweetId string) (bool, error) {
+	var tweetModel m.Tweet
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return false, err
+	}
+
+	objTweetId, err := getObjectId(tweetId)
+
+	if err != nil {
+		return false, err
+	}
+
+	col := getCollection(db, "twittor", "tweet")
+	condition := bson.M{
+		"userId":    objUserId,
+		"retweetOf": objTweetId,
+		"active":    true,
+	}
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err = col.FindOne(ctx, condition).Decode(&tweetModel)
+
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	err = db.deleteTweetLogical(tweetModel.Id.Hex(), userId)
+
+	return err == nil, err
+}
+
+/* GetTweet gets a tweet, including a deleted one */
+func (db *DbNoSql) GetTweet(id string) (mr.Tweet, bool, error) {
+	var tweetRequest mr.Tweet
+	var tweetModel m.Tweet
+
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return tweetRequest, false, err
+	}
+
+	col := getCollection(db, "twittor", "tweet")
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err = col.FindOne(ctx, bson.M{"_id": objId}).Decode(&tweetModel)
+
+	if err == mongo.ErrNoDocuments {
+		return tweetRequest, false, nil
+	} else if err != nil {
+		return tweetRequest, false, err
+	}
+
+	tweetRequest = getTweetRequest(tweetModel)
+
+	return tweetRequest, true, nil
+}
+
+/* GetReplies gets the direct replies to a tweet */
+func (db *DbNoSql) GetReplies(id string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	condition := bson.M{
+		"inReplyTo": objId,
+		"active":    true,
+	}
+
+	return db.findTweets(condition, page, limit, false)
+}
+
+/* GetConversation gets all the tweets of a conversation, including the deleted ones, ordered by date */
+func (db *DbNoSql) GetConversation(id string) ([]*mr.Tweet, error) {
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	condition := bson.M{
+		"$or": []bson.M{
+			{"_id": objId},
+			{"conversationId": objId},
+		},
+	}
+
+	results, _, err := db.findTweets(condition, 0, 0, false)
+
+	return results, err
+}
+
+/* ModifyTweet changes the message of an user's tweet keeping the previous one as a revision, it returns false if the tweet is not found */
+func (db *DbNoSql) ModifyTweet(tweet mr.Tweet) (bool, error) {
+	objId, err := getObjectId(tweet.Id)
+
+	if err != nil {
+		return false, err
+	}
+
+	objUserId, err := getObjectId(tweet.UserId)
+
+	if err != nil {
+		return false, err
+	}
+
+	mentions, err := db.getMentionedIds(tweet.Message, objUserId)
+
+	if err != nil {
+		return false, err
+	}
+
+	tweetsCol := getCollection(db, "twittor", "tweet")
+	revisionsCol := getCollection(db, "twittor", "tweetRevisions")
+	condition := bson.M{
+		"_id":    objId,
+		"userId": objUserId,
+		"active": true,
+	}
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		var tweetModel m.Tweet
+
+		err := tweetsCol.FindOne(sessCtx, condition).Decode(&tweetModel)
+
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = revisionsCol.InsertOne(sessCtx, getTweetRevisionModel(tweetModel))
+
+		if err != nil {
+			return nil, err
+		}
+
+		hashtags := helpers.GetHashtags(tweet.Message)
+
+		// The hashtags of the previous message stop counting in the trends
+		err = db.updateHashtagCounts(sessCtx, tweetModel.Hashtags, tweetModel.Date, -1)
+
+		if err == nil {
+			err = db.updateHashtagCounts(sessCtx, hashtags, tweetModel.Date, 1)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return tweetsCol.UpdateOne(sessCtx, condition, bson.M{"$set": bson.M{
+			"message":  tweet.Message,
+			"editedAt": tweet.EditedAt,
+			"hashtags": hashtags,
+			"mentions": mentions,
+		}})
+	}
+
+	result, err := db.executeTransaction(callback)
+
+	return result != nil, err
+}
+
+/* GetTweetRevisions gets the previous messages of an edited tweet, the oldest first */
+func (db *DbNoSql) GetTweetRevisions(id string) ([]*mr.TweetRevision, error) {
+	var results []*mr.TweetRevision
+	var revisionsDbResults []*m.TweetRevision
+
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return results, err
+	}
+
+	col := getCollection(db, "twittor", "tweetRevisions")
+	opts := options.Find().SetSort(bson.M{"date": 1})
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, bson.M{"tweetId": objId}, opts)
+
+	if err != nil {
+		return results, err
+	}
+
+	ctxCursor := context.TODO()
+
+	defer cursor.Close(ctxCursor)
+
+	err = cursor.All(ctxCursor, &revisionsDbResults)
+
+	if err != nil {
+		return results, err
+	}
+
+	for _, revisionModel := range revisionsDbResults {
+		revisionRequest := getTweetRevisionRequest(*revisionModel)
+		results = append(results, &revisionRequest)
+	}
+
+	return results, nil
+}
+
+/* GetHashtagTweets gets the tweets with a hashtag, the newest first */
+func (db *DbNoSql) GetHashtagTweets(tag string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	condition := bson.M{
+		"hashtags": tag,
+		"active":   true,
+	}
+
+	return db.findTweets(condition, page, limit, true)
+}
+
+/* GetMentionTweets gets the tweets that mention an user, the newest first */
+func (db *DbNoSql) GetMentionTweets(userId string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	condition := bson.M{
+		"mentions": objUserId,
+		"active":   true,
+	}
+
+	return db.findTweets(condition, page, limit, true)
+}
+
+/* SearchTweets gets the tweets with any of the terms, by relevance or the newest first */
+func (db *DbNoSql) SearchTweets(terms []string, isNewestFirst bool, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	condition := bson.M{
+		"$text":  bson.M{"$search": strings.Join(terms, " ")},
+		"active": true,
+	}
+	sort := bson.D{{Key: "date", Value: -1}}
+
+	if !isNewestFirst {
+		sort = append(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}}, sort...)
+	}
+
+	return db.findSortedTweets(condition, page, limit, sort)
+}
+
+/* GetTrends gets the most used hashtags since a date, the counts are kept by time bucket when the tweets are written */
+func (db *DbNoSql) GetTrends(since time.Time, limit int64) ([]*mr.Trend, error) {
+	var results []*mr.Trend
+	var countsDbResults []*m.HashtagCount
+
+	col := getCollection(db, "twittor", "hashtagCounts")
+	pipeline := []bson.M{
+		{"$match": bson.M{"bucket": bson.M{"$gte": helpers.GetTrendsBucket(since)}}},
+		{"$group": bson.M{"_id": "$tag", "count": bson.M{"$sum": "$count"}}},
+		{"$match": bson.M{"count": bson.M{"$gt": 0}}},
+		{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		{"$limit": limit},
+		{"$project": bson.M{"_id": 0, "tag": "$_id", "count": 1}},
+	}
+	ctxAgg, cancelAgg := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelAgg()
+
+	cursor, err := col.Aggregate(ctxAgg, pipeline)
+
+	if err != nil {
+		return results, err
+	}
+
+	ctxCursor := context.TODO()
+
+	defer cursor.Close(ctxCursor)
+
+	err = cursor.All(ctxCursor, &countsDbResults)
+
+	if err != nil {
+		return results, err
+	}
+
+	for _, countModel := range countsDbResults {
+		trendRequest := getTrendRequest(*countModel)
+		results = append(results, &trendRequest)
+	}
+
+	return results, nil
+}
+
+// endregion
+
+// region "Likes"
+
+/* InsertLike adds an user's like to a tweet, it returns false if the user had already liked it */
+func (db *DbNoSql) InsertLike(like mr.Like) (bool, error) {
+	likeModel, err := getLikeModel(like)
+
+	if err != nil {
+		return false, err
+	}
+
+	likesCol := getCollection(db, "twittor", "likes")
+	tweetsCol := getCollection(db, "twittor", "tweet")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := likesCol.InsertOne(sessCtx, likeModel)
+
+		if err != nil {
+			return result, err
+		}
+
+		// The count is kept in the tweet, so it is not counted each time the tweet is read
+		_, err = tweetsCol.UpdateOne(sessCtx,
+			bson.M{"_id": likeModel.TweetId},
+			bson.M{"$inc": bson.M{"likeCount": 1}})
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	// The unique index only allows a like of a tweet by user
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+/* DeleteLike removes an user's like from a tweet, it returns false if the user had not liked it */
+func (db *DbNoSql) DeleteLike(userId string, tweetId string) (bool, error) {
+	likeModel, err := getLikeModel(mr.Like{UserId: userId, TweetId: tweetId})
+
+	if err != nil {
+		return false, err
+	}
+
+	likesCol := getCollection(db, "twittor", "likes")
+	tweetsCol := getCollection(db, "twittor", "tweet")
+	filter := bson.M{
+		"userId":  likeModel.UserId,
+		"tweetId": likeModel.TweetId,
+	}
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := likesCol.DeleteOne(sessCtx, filter)
+
+		if err != nil || result.DeletedCount < 1 {
+			return result, err
+		}
+
+		_, err = tweetsCol.UpdateOne(sessCtx,
+			bson.M{"_id": likeModel.TweetId},
+			bson.M{"$inc": bson.M{"likeCount": -1}})
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return false, err
+	}
+
+	return res.(*mongo.DeleteResult).DeletedCount > 0, nil
+}
+
+/* GetLikedTweetIds returns which of the tweets are liked by an user */
+func (db *DbNoSql) GetLikedTweetIds(userId string, tweetIds []string) ([]string, error) {
+	var results []string
+	var likesDbResults []*m.Like
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return results, err
+	}
+
+	objTweetIds := make([]primitive.ObjectID, 0, len(tweetIds))
+
+	for _, tweetId := range tweetIds {
+		objTweetId, err := getObjectId(tweetId)
+
+		if err != nil {
+			return results, err
+		}
+
+		objTweetIds = append(objTweetIds, objTweetId)
+	}
+
+	col := getCollection(db, "twittor", "likes")
+	condition := bson.M{
+		"userId":  objUserId,
+		"tweetId": bson.M{"$in": objTweetIds},
+	}
+	opts := options.Find().SetProjection(bson.M{"tweetId": 1})
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, condition, opts)
+
+	if err != nil {
+		return results, err
+	}
+
+	ctxCursor := context.TODO()
+
+	defer cursor.Close(ctxCursor)
+
+	err = cursor.All(ctxCursor, &likesDbResults)
+
+	if err != nil {
+		return results, err
+	}
+
+	for _, likeModel := range likesDbResults {
+		results = append(results, likeModel.TweetId.Hex())
+	}
+
+	return results, nil
+}
+
+/* GetLikedTweets gets the tweets liked by an user, the last liked first */
+func (db *DbNoSql) GetLikedTweets(userId string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	var results []*mr.Tweet
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return results, 0, err
+	}
+
+	// region Pipeline
+
+	matchUser := bson.M{"$match": bson.M{"userId": objUserId}}
+	lookupTweets := bson.M{"$lookup": bson.M{
+		"from":         "tweet",
+		"localField":   "tweetId",
+		"foreignField": "_id",
+		"as":           "tweet"}}
+	unwindTweets := bson.M{"$unwind": bson.M{
+		"path":                       "$tweet",
+		"preserveNullAndEmptyArrays": false}}
+	matchActive := bson.M{"$match": bson.M{"tweet.active": true}}
+
+	count := bson.M{"$count": "total"}
+
+	sort := bson.M{"$sort": bson.M{"date": -1}}
+	skip := bson.M{"$skip": (page - 1) * limit}
+	agLimit := bson.M{"$limit": limit}
+	replaceRoot := bson.M{"$replaceRoot": bson.M{"newRoot": "$tweet"}}
+
+	basePipeline := []bson.M{matchUser, lookupTweets, unwindTweets, matchActive}
+	countPipeline := append(basePipeline, count)
+	aggPipeline := append(basePipeline, sort, skip, agLimit, replaceRoot)
+
+	// endregion
+
+	dbResults, total, err := getResults[m.Tweet](db, "likes", countPipeline, aggPipeline)
+
+	if err == nil {
+		for _, tweetModel := range dbResults {
+			tweetRequest := getTweetRequest(*tweetModel)
+			results = append(results, &tweetRequest)
+		}
+	}
+
+	return results, total, err
+}
+
+// endregion
+
+// region "Bookmarks"
+
+/* InsertBookmark saves a tweet in the user's bookmarks, it returns false if the user had already bookmarked it */
+func (db *DbNoSql) InsertBookmark(bookmark mr.Bookmark) (bool, error) {
+	bookmarkModel, err := getBookmarkModel(bookmark)
+
+	if err != nil {
+		return false, err
+	}
+
+	col := getCollection(db, "twittor", "bookmarks")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.InsertOne(sessCtx, bookmarkModel)
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	// The unique index only allows a bookmark of a tweet by user
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+/* DeleteBookmark removes a tweet from the user's bookmarks, it returns false if the user had not bookmarked it */
+func (db *DbNoSql) DeleteBookmark(userId string, tweetId string) (bool, error) {
+	bookmarkModel, err := getBookmarkModel(mr.Bookmark{UserId: userId, TweetId: tweetId})
+
+	if err != nil {
+		return false, err
+	}
+
+	col := getCollection(db, "twittor", "bookmarks")
+	filter := bson.M{
+		"userId":  bookmarkModel.UserId,
+		"tweetId": bookmarkModel.TweetId,
+	}
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.DeleteOne(sessCtx, filter)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return false, err
+	}
+
+	return res.(*mongo.DeleteResult).DeletedCount > 0, nil
+}
+
+/* GetBookmarkedTweets gets the tweets bookmarked by an user, the last bookmarked first. The tweets deleted after being bookmarked are returned as inactive */
+func (db *DbNoSql) GetBookmarkedTweets(userId string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	var results []*mr.Tweet
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return results, 0, err
+	}
+
+	// region Pipeline
+
+	matchUser := bson.M{"$match": bson.M{"userId": objUserId}}
+
+	count := bson.M{"$count": "total"}
+
+	sort := bson.M{"$sort": bson.M{"date": -1}}
+	skip := bson.M{"$skip": (page - 1) * limit}
+	agLimit := bson.M{"$limit": limit}
+	lookupTweets := bson.M{"$lookup": bson.M{
+		"from":         "tweet",
+		"localField":   "tweetId",
+		"foreignField": "_id",
+		"as":           "tweet"}}
+	unwindTweets := bson.M{"$unwind": bson.M{
+		"path":                       "$tweet",
+		"preserveNullAndEmptyArrays": true}}
+	// The tweets removed with their author are kept as inactive ones, so they are not silently lost
+	replaceRoot := bson.M{"$replaceRoot": bson.M{"newRoot": bson.M{
+		"$ifNull": bson.A{"$tweet", bson.M{"_id": "$tweetId", "active": false}}}}}
+
+	countPipeline := []bson.M{matchUser, count}
+	aggPipeline := []bson.M{matchUser, sort, skip, agLimit, lookupTweets, unwindTweets, replaceRoot}
+
+	// endregion
+
+	dbResults, total, err := getResults[m.Tweet](db, "bookmarks", countPipeline, aggPipeline)
+
+	if err == nil {
+		for _, tweetModel := range dbResults {
+			tweetRequest := getTweetRequest(*tweetModel)
+			results = append(results, &tweetRequest)
+		}
+	}
+
+	return results, total, err
+}
+
+// endregion
+
+// region "Scheduled tweets"
+
+/* InsertScheduledTweet inserts a tweet to be published later in the DB */
+func (db *DbNoSql) InsertScheduledTweet(tweet mr.ScheduledTweet) (string, error) {
+	scheduledModel, err := getScheduledTweetModel(tweet)
+
+	if err != nil {
+		return "", err
+	}
+
+	col := getCollection(db, "twittor", "scheduledTweets")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.InsertOne(sessCtx, scheduledModel)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return "", err
+	}
+
+	result := res.(*mongo.InsertOneResult)
+	objID, _ := result.InsertedID.(primitive.ObjectID)
+
+	return objID.Hex(), nil
+}
+
+/* GetScheduledTweets gets the tweets scheduled by an user, the next to be published first */
+func (db *DbNoSql) GetScheduledTweets(userId string, page int64, limit int64) ([]*mr.ScheduledTweet, int64, error) {
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	condition := bson.M{"userId": objUserId}
+
+	return db.findScheduledTweets(condition, page, limit)
+}
+
+/* GetDueScheduledTweets gets the scheduled tweets whose publication date has been reached, the oldest first */
+func (db *DbNoSql) GetDueScheduledTweets(before time.Time, limit int64) ([]*mr.ScheduledTweet, error) {
+	condition := bson.M{"publishAt": bson.M{"$lte": before}}
+	results, _, err := db.findScheduledTweets(condition, 1, limit)
+
+	return results, err
+}
+
+/* DeleteScheduledTweet deletes a tweet scheduled by an user, it returns the deleted tweet */
+func (db *DbNoSql) DeleteScheduledTweet(id string, userId string) (mr.ScheduledTweet, bool, error) {
+	var scheduledModel m.ScheduledTweet
+	var scheduledRequest mr.ScheduledTweet
+
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return scheduledRequest, false, err
+	}
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return scheduledRequest, false, err
+	}
+
+	col := getCollection(db, "twittor", "scheduledTweets")
+	filter := bson.M{
+		"_id":    objId,
+		"userId": objUserId,
+	}
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err = col.FindOneAndDelete(ctx, filter).Decode(&scheduledModel)
+
+	if err == mongo.ErrNoDocuments {
+		return scheduledRequest, false, nil
+	} else if err != nil {
+		return scheduledRequest, false, err
+	}
+
+	scheduledRequest = getScheduledTweetRequest(scheduledModel)
+
+	return scheduledRequest, true, nil
+}
+
+/* PublishScheduledTweet inserts the tweet of a scheduled one and deletes it, it returns false if it was already published or cancelled */
+func (db *DbNoSql) PublishScheduledTweet(id string, tweet mr.Tweet) (string, bool, error) {
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	tweetModel, err := getTweetModel(tweet)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	col := getCollection(db, "twittor", "scheduledTweets")
+	claim := func(sessCtx mongo.SessionContext) (bool, error) {
+		result, err := col.DeleteOne(sessCtx, bson.M{"_id": objId})
+
+		if err != nil {
+			return false, err
+		}
+
+		return result.DeletedCount > 0, nil
+	}
+
+	return db.insertTweet(tweetModel, claim)
+}
+
+// endregion
+
+// region "Drafts"
+
+/* InsertDraft inserts a draft of a tweet in the DB */
+func (db *DbNoSql) InsertDraft(draft mr.Draft) (string, error) {
+	draftModel, err := getDraftModel(draft)
+
+	if err != nil {
+		return "", err
+	}
+
+	col := getCollection(db, "twittor", "drafts")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.InsertOne(sessCtx, draftModel)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return "", err
+	}
+
+	result := res.(*mongo.InsertOneResult)
+	objID, _ := result.InsertedID.(primitive.ObjectID)
+
+	return objID.Hex(), nil
+}
+
+/* GetDrafts gets the drafts of an user, the last saved first */
+func (db *DbNoSql) GetDrafts(userId string, page int64, limit int64) ([]*mr.Draft, int64, error) {
+	var results []*mr.Draft
+	var draftsDbResults []*m.Draft
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return results, 0, err
+	}
+
+	col := getCollection(db, "twittor", "drafts")
+	condition := bson.M{"userId": objUserId}
+	opts := options.Find()
+
+	opts.SetSort(bson.D{{Key: "date", Value: -1}})
+
+	ctxCount, cancelCount := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelCount()
+
+	total, err := col.CountDocuments(ctxCount, condition)
+
+	if err != nil {
+		return results, total, err
+	}
+
+	// Without a limit all the drafts are returned
+	if limit > 0 {
+		opts.SetSkip((page - 1) * limit)
+		opts.SetLimit(limit)
+	}
+
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, condition, opts)
+
+	if err != nil {
+		return results, total, err
+	}
+
+	ctxCursor := context.TODO()
+
+	defer cursor.Close(ctxCursor)
+
+	err = cursor.All(ctxCursor, &draftsDbResults)
+
+	if err != nil {
+		return results, total, err
+	}
+
+	for _, draftModel := range draftsDbResults {
+		draftRequest := getDraftRequest(*draftModel)
+		results = append(results, &draftRequest)
+	}
+
+	return results, total, nil
+}
+
+/* GetDraft gets a draft of an user */
+func (db *DbNoSql) GetDraft(id string, userId string) (mr.Draft, bool, error) {
+	var draftModel m.Draft
+	var draftRequest mr.Draft
+
+	filter, err := getDraftFilter(id, userId)
+
+	if err != nil {
+		return draftRequest, false, err
+	}
+
+	col := getCollection(db, "twittor", "drafts")
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err = col.FindOne(ctx, filter).Decode(&draftModel)
+
+	if err == mongo.ErrNoDocuments {
+		return draftRequest, false, nil
+	} else if err != nil {
+		return draftRequest, false, err
+	}
+
+	draftRequest = getDraftRequest(draftModel)
+
+	return draftRequest, true, nil
+}
+
+/* ModifyDraft replaces the content of a draft of an user, it returns false if the draft does not exist */
+func (db *DbNoSql) ModifyDraft(draft mr.Draft) (bool, error) {
+	draftModel, err := getDraftModel(draft)
+
+	if err != nil {
+		return false, err
+	}
+
+	col := getCollection(db, "twittor", "drafts")
+	filter := bson.M{
+		"_id":    draftModel.Id,
+		"userId": draftModel.UserId,
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.ReplaceOne(sessCtx, filter, draftModel)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return false, err
+	}
+
+	result := res.(*mongo.UpdateResult)
+
+	return result.MatchedCount > 0, nil
+}
+
+/* DeleteDraft deletes a draft of an user, it returns the deleted draft */
+func (db *DbNoSql) DeleteDraft(id string, userId string) (mr.Draft, bool, error) {
+	var draftModel m.Draft
+	var draftRequest mr.Draft
+
+	filter, err := getDraftFilter(id, userId)
+
+	if err != nil {
+		return draftRequest, false, err
+	}
+
+	col := getCollection(db, "twittor", "drafts")
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err = col.FindOneAndDelete(ctx, filter).Decode(&draftModel)
+
+	if err == mongo.ErrNoDocuments {
+		return draftRequest, false, nil
+	} else if err != nil {
+		return draftRequest, false, err
+	}
+
+	draftRequest = getDraftRequest(draftModel)
+
+	return draftRequest, true, nil
+}
+
+/* PublishDraft inserts the tweet of a draft and deletes it, it returns false if the draft does not exist anymore */
+func (db *DbNoSql) PublishDraft(id string, userId string, tweet mr.Tweet) (string, bool, error) {
+	filter, err := getDraftFilter(id, userId)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	tweetModel, err := getTweetModel(tweet)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	col := getCollection(db, "twittor", "drafts")
+	claim := func(sessCtx mongo.SessionContext) (bool, error) {
+		result, err := col.DeleteOne(sessCtx, filter)
+
+		if err != nil {
+			return false, err
+		}
+
+		return result.DeletedCount > 0, nil
+	}
+
+	return db.insertTweet(tweetModel, claim)
+}
+
+// endregion
+
+// region "Relations"
+
+/* IsRelation obtains a relation from the DB if exist */
+func (db *DbNoSql) IsRelation(relation mr.Relation) (bool, mr.Relation, error) {
+	var result mr.Relation
+
+	relationModel, err := getRelationModel(relation)
+
+	if err != nil {
+		return false, result, err
+	}
+
+	col := getCollection(db, "twittor", "relation")
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	condition := bson.M{
+		"userId":         relationModel.UserId,
+		"userRelationId": relationModel.UserRelationId,
+	}
+
+	err = col.FindOne(ctx, condition).Decode(&relationModel)
+
+	if err != nil && err == mongo.ErrNoDocuments {
+		return false, result, nil
+	}
+
+	result = getRelationRequest(relationModel)
+
+	return true, result, err
+}
+
+/* InsertRelation creates a relation into the DB */
+func (db *DbNoSql) InsertRelation(relation mr.Relation) error {
+	col := getCollection(db, "twittor", "relation")
+	_, isFound, err := db.GetProfile(relation.UserRelationId)
+
+	if err != nil {
+		return err
+	}
+
+	if !isFound {
+		return errors.New("no registry found in the DB")
+	}
+
+	isFound, relationDb, err := db.IsRelation(relation)
+
+	if err != nil {
+		return err
+	}
+
+	if !isFound {
+		relationModel, err := getRelationModel(relation)
+
+		if err != nil {
+			return err
+		}
+
+		callback := func(sessCtx mongo.SessionContext) (any, error) {
+			result, err := col.InsertOne(sessCtx, relationModel)
+
+			return result, err
+		}
+
+		_, err = db.executeTransaction(callback)
+
+		return err
+	}
+
+	if relationDb.Active {
+		return fmt.Errorf("the relation with the user id = %s already exists", relation.UserRelationId)
+	}
+
+	err = db.updateRelation(relationDb, true)
+
+	return err
+}
+
+/* DeleteRelation deletes a relation in the DB */
+func (db *DbNoSql) DeleteRelation(relation mr.Relation) error {
+	err := db.updateRelation(relation, false)
+
+	return err
+}
+
+/* GetUsers gets a list of users */
+func (db *DbNoSql) GetUsers(id string, page int64, limit int64, search string, searchType string) ([]*mr.User, int64, error) {
+	var results []*mr.User
+	var total int64
+
+	col := getCollection(db, "twittor", "users")
+
+	query := bson.M{
+		"name": bson.M{"$regex": search, "$options": "im"},
+	}
+
+	findOpts := options.Find()
+
+	findOpts.SetSort(bson.D{{Key: "birthDate", Value: -1}})
+	findOpts.SetSkip((page - 1) * limit)
+	findOpts.SetLimit(limit)
+
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, query, findOpts)
+
+	if err != nil {
+		return results, total, err
+	}
+
+	ctxCursor := context.TODO()
+
+	defer cursor.Close(ctxCursor)
+
+	var include, isRelation bool
+
+	for cursor.Next(ctxCursor) {
+		var result m.User
+
+		isRelation = false
+		include = false
+
+		err := cursor.Decode(&result)
+
+		if err != nil {
+			return results, total, err
+		}
+
+		userRequest := getUserRequest(result)
+
+		relationRequest := mr.Relation{
+			UserId:         id,
+			UserRelationId: userRequest.Id,
+			Active:         true,
+		}
+
+		isFound, relationDb, err := db.IsRelation(relationRequest)
+
+		if err != nil {
+			return results, total, err
+		}
+
+		if isFound {
+			isRelation = relationDb.Active
+		}
+
+		if relationRequest.UserRelationId == id {
+			include = false
+		} else if (searchType == "new" && !isRelation) || (searchType == "follow" && isRelation) {
+			include = true
+		}
+
+		if include {
+			userRequest.Email = ""
+			userRequest.Password = ""
+			userRequest.Avatar = ""
+			userRequest.Banner = ""
+			userRequest.Biography = ""
+			userRequest.Location = ""
+			userRequest.WebSite = ""
+
+			results = append(results, &userRequest)
+		}
+	}
+
+	err = cursor.Err()
+
+	if err != nil {
+		fmt.Println(err.Error())
+
+		return results, total, err
+	}
+
+	// This total only reflects the total returned registries
+	total = int64(len(results))
+
+	return results, total, nil
+}
+
+/* GetFollowing gets an user's following list */
+func (db *DbNoSql) GetFollowing(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
+	var results []*mr.User
+
+	objId, _ := getObjectId(id)
+
+	// region Pipeline
+
+	matchId := bson.M{"$match": bson.M{"userId": objId, "active": true}}
+	lookupUsers := bson.M{"$lookup": bson.M{
+		"from":         "users",
+		"localField":   "userRelationId",
+		"foreignField": "_id",
+		"as":           "result"}}
+	projectResult := bson.M{"$project": bson.M{
+		"user": bson.M{"$arrayElemAt": [2]any{"$result", 0}},
+		"_id":  0}}
+	matchName := bson.M{"$match": bson.M{"user.name": bson.M{"$regex": search, "$options": "im"}}}
+
+	count := bson.M{"$count": "total"}
+
+	sort := bson.M{"$sort": bson.M{"user.birthDate": -1}}
+	skip := bson.M{"$skip": (page - 1) * limit}
+	agLimit := bson.M{"$limit": limit}
+	projectUser := bson.M{"$project": bson.M{
+		"_id":       "$user._id",
+		"name":      "$user.name",
+		"lastName":  "$user.lastName",
+		"birthDate": "$user.birthDate"}}
+
+	basePipeline := []bson.M{matchId, lookupUsers, projectResult, matchName}
+	countPipeline := append(basePipeline, count)
+	aggPipeline := append(basePipeline, sort, skip, agLimit, projectUser)
+
+	// endregion
+
+	dbResults, total, err := getResults[m.User](db, "relation", countPipeline, aggPipeline)
+
+	if err == nil {
+		for _, userModel := range dbResults {
+			userRequest := getUserRequest(*userModel)
+			results = append(results, &userRequest)
+		}
+	}
+
+	return results, total, err
+}
+
+/* GetFollowers gets an user's followers list */
+func (db *DbNoSql) GetFollowers(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
+	var results []*mr.User
+
+	objId, _ := getObjectId(id)
+
+	// region Pipeline
+
+	matchId := bson.M{"$match": bson.M{"userRelationId": objId, "active": true}}
+	lookupUsers := bson.M{"$lookup": bson.M{
+		"from":         "users",
+		"localField":   "userId",
+		"foreignField": "_id",
+		"as":           "result"}}
+	projectResult := bson.M{"$project": bson.M{
+		"user": bson.M{"$arrayElemAt": [2]any{"$result", 0}},
+		"_id":  0}}
+	matchName := bson.M{"$match": bson.M{"user.name": bson.M{"$regex": search, "$options": "im"}}}
+
+	count := bson.M{"$count": "total"}
+
+	sort := bson.M{"$sort": bson.M{"user.birthDate": -1}}
+	skip := bson.M{"$skip": (page - 1) * limit}
+	agLimit := bson.M{"$limit": limit}
+	projectUser := bson.M{"$project": bson.M{
+		"_id":       "$user._id",
+		"name":      "$user.name",
+		"lastName":  "$user.lastName",
+		"birthDate": "$user.birthDate"}}
+
+	basePipeline := []bson.M{matchId, lookupUsers, projectResult, matchName}
+	countPipeline := append(basePipeline, count)
+	aggPipeline := append(basePipeline, sort, skip, agLimit, projectUser)
+
+	// endregion
+
+	dbResults, total, err := getResults[m.User](db, "relation", countPipeline, aggPipeline)
+
+	if err == nil {
+		for _, userModel := range dbResults {
+			userRequest := getUserRequest(*userModel)
+			results = append(results, &userRequest)
+		}
+	}
+
+	return results, total, err
+}
+
+/* GetNotFollowing gets an user's not following list */
+func (db *DbNoSql) GetNotFollowing(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
+	var results []*mr.User
+
+	objId, _ := getObjectId(id)
+
+	// region Pipeline
+
+	matchId := bson.M{"$match": bson.M{"_id": objId}}
+	lookupRelation := bson.M{"$lookup": bson.M{
+		"from":         "relation",
+		"localField":   "_id",
+		"foreignField": "userId",
+		"as":           "r",
+		"pipeline": [1]bson.M{{
+			"$match": bson.M{
+				"$expr": bson.M{
+					"$eq": [2]any{"$active", true},
+				},
+			}},
+		}},
+	}
+	lookupUsers := bson.M{"$lookup": bson.M{
+		"from": "users",
+		"as":   "u",
+		"let":  bson.M{"userId": "$r.userRelationId"},
+		"pipeline": [1]bson.M{{
+			"$match": bson.M{
+				"$expr": bson.M{
+					"$not": bson.M{
+						"$in": [2]string{
+							"$_id",
+							"$$userId",
+						}},
+				}},
+		}},
+	}}
+	projectArray := bson.M{"$project": bson.M{"u": "$u", "_id": 0}}
+	unwind := bson.M{"$unwind": bson.M{
+		"path":                       "$u",
+		"preserveNullAndEmptyArrays": false,
+	}}
+	matchName := bson.M{"$match": bson.M{
+		"u._id":  bson.M{"$ne": objId},
+		"u.name": bson.M{"$regex": search, "$options": "im"},
+	}}
+
+	count := bson.M{"$count": "total"}
+
+	sort := bson.M{"$sort": bson.M{"u.birthDate": -1}}
+	skip := bson.M{"$skip": (page - 1) * limit}
+	agLimit := bson.M{"$limit": limit}
+	projectUser := bson.M{"$project": bson.M{
+		"_id":       "$u._id",
+		"name":      "$u.name",
+		"lastName":  "$u.lastName",
+		"birthDate": "$u.birthDate",
+	}}
+
+	basePipeline := []bson.M{matchId, lookupRelation, lookupUsers, projectArray, unwind, matchName}
+	countPipeline := append(basePipeline, count)
+	aggPipeline := append(basePipeline, sort, skip, agLimit, projectUser)
+
+	// endregion
+
+	dbResults, total, err := getResults[m.User](db, "users", countPipeline, aggPipeline)
+
+	if err == nil {
+		for _, userModel := range dbResults {
+			userRequest := getUserRequest(*userModel)
+			results = append(results, &userRequest)
+		}
+	}
+
+	return results, total, err
+}
+
+/* GetFollowingTweets returns the following's tweets */
+func (db *DbNoSql) GetFollowingTweets(id string, page int64, limit int64, isOnlyTweets bool) (any, int64, error) {
+	var results any
+	var total int64
+	var err error
+
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return results, 0, err
+	}
+
+	conditions := make([]bson.M, 0)
+	conditionsCount := make([]bson.M, 0)
+	conditionsAgg := make([]bson.M, 0)
+
+	skip := (page - 1) * limit
+
+	conditions = append(conditions, bson.M{"$match": bson.M{"userId": objId, "active": true}})
+	conditions = append(conditions, bson.M{
+		"$lookup": bson.M{
+			"from":         "tweet",
+			"localField":   "userRelationId",
+			"foreignField": "userId",
+			"as":           "tweet",
+			"pipeline": []bson.M{{
+				"$match": bson.M{
+					"active": true}},
+			},
+		}})
+	conditions = append(conditions, bson.M{
+		"$unwind": bson.M{
+			"path":                       "$tweet",
+			"preserveNullAndEmptyArrays": false,
+		},
+	})
+
+	conditionsCount = append(conditionsCount, conditions...)
+	conditionsCount = append(conditionsCount, bson.M{"$count": "total"})
+
+	conditionsAgg = append(conditionsAgg, conditions...)
+	conditionsAgg = append(conditionsAgg, bson.M{"$sort": bson.M{"tweet.date": -1}})
+	conditionsAgg = append(conditionsAgg, bson.M{"$skip": skip})
+	conditionsAgg = append(conditionsAgg, bson.M{"$limit": limit})
+
+	if isOnlyTweets {
+		conditionsAgg = append(conditionsAgg, bson.M{
+			"$project": bson.M{
+				"_id":            "$tweet._id",
+				"userId":         "$tweet.userId",
+				"message":        "$tweet.message",
+				"date":           "$tweet.date",
+				"inReplyTo":      "$tweet.inReplyTo",
+				"conversationId": "$tweet.conversationId",
+				"replyCount":     "$tweet.replyCount",
+				"retweetOf":      "$tweet.retweetOf",
+				"quotedTweetId":  "$tweet.quotedTweetId",
+				"retweetCount":   "$tweet.retweetCount",
+				"quoteCount":     "$tweet.quoteCount",
+				"likeCount":      "$tweet.likeCount",
+				"editedAt":       "$tweet.editedAt",
+				"media":          "$tweet.media",
+			}})
+
+		var dbResults []*m.Tweet
+		var reqResults []*mr.Tweet
+
+		dbResults, total, err = getResults[m.Tweet](db, "relation", conditionsCount, conditionsAgg)
+
+		if err == nil {
+			for _, tweetModel := range dbResults {
+				tweetRequest := getTweetRequest(*tweetModel)
+				reqResults = append(reqResults, &tweetRequest)
+			}
+
+			results = reqResults
+		}
+	} else {
+		var dbResults []*m.UserTweet
+		var reqResults []*mr.UserTweet
+
+		dbResults, total, err = getResults[m.UserTweet](db, "relation", conditionsCount, conditionsAgg)
+
+		if err == nil {
+			for _, userTweetModel := range dbResults {
+				userTweetRequest := getUserTweetRequest(*userTweetModel)
+				reqResults = append(reqResults, &userTweetRequest)
+			}
+
+			results = reqResults
+		}
+	}
+
+	return results, total, err
+}
+
+// endregion
+
+// region "Tokens"
+
+/* RevokeToken adds a token to the revocation list in the DB, it returns false if the token had already been revoked */
+func (db *DbNoSql) RevokeToken(token mr.RevokedToken) (bool, error) {
+	tokenModel, err := getRevokedTokenModel(token)
+
+	if err != nil {
+		return false, err
+	}
+
+	col := getCollection(db, "twittor", "revokedTokens")
+	filter := bson.M{"tokenId": tokenModel.TokenId}
+	update := bson.M{"$setOnInsert": tokenModel}
+	opts := options.Update().SetUpsert(true)
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, update, opts)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return false, err
+	}
+
+	// The token is only revoked by this call if it was not in the list yet
+	return res.(*mongo.UpdateResult).UpsertedCount > 0, nil
+}
+
+/* IsTokenRevoked checks if a token is in the revocation list */
+func (db *DbNoSql) IsTokenRevoked(tokenId string) (bool, error) {
+	col := getCollection(db, "twittor", "revokedTokens")
+	condition := bson.M{"tokenId": tokenId}
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err := col.FindOne(ctx, condition).Err()
+
+	if err != nil && err == mongo.ErrNoDocuments {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* InsertActionToken inserts a single-use action token in the DB */
+func (db *DbNoSql) InsertActionToken(token mr.ActionToken) error {
+	tokenModel, err := getActionTokenModel(token)
+
+	if err != nil {
+		return err
+	}
+
+	col := getCollection(db, "twittor", "actionTokens")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.InsertOne(sessCtx, tokenModel)
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	return err
+}
+
+/* ConsumeActionToken deletes an action token from the DB and returns it if it was not expired */
+func (db *DbNoSql) ConsumeActionToken(hash string, action string) (mr.ActionToken, bool, error) {
+	var tokenModel m.ActionToken
+	var tokenRequest mr.ActionToken
+
+	col := getCollection(db, "twittor", "actionTokens")
+	condition := bson.M{
+		"hash":   hash,
+		"action": action,
+	}
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	// Deleting the token when it is found guarantees that it can be used only once
+	err := col.FindOneAndDelete(ctx, condition).Decode(&tokenModel)
+
+	if err != nil && err == mongo.ErrNoDocuments {
+		return tokenRequest, false, nil
+	} else if err != nil {
+		return tokenRequest, false, err
+	}
+
+	tokenRequest = getActionTokenRequest(tokenModel)
+
+	if !tokenRequest.ExpiresAt.IsZero() && tokenRequest.ExpiresAt.Before(time.Now()) {
+		return tokenRequest, false, nil
+	}
+
+	return tokenRequest, true, nil
+}
+
+/* DeleteActionTokens deletes all the action tokens of an user for an action */
+func (db *DbNoSql) DeleteActionTokens(userId string, action string) error {
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return err
+	}
+
+	col := getCollection(db, "twittor", "actionTokens")
+	filter := bson.M{
+		"userId": objUserId,
+		"action": action,
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.DeleteMany(sessCtx, filter)
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	return err
+}
+
+// endregion
+
+// region "Access tokens"
+
+/* InsertAccessToken inserts a personal access token in the DB */
+func (db *DbNoSql) InsertAccessToken(token mr.AccessToken) (string, error) {
+	tokenModel, err := getAccessTokenModel(token)
+
+	if err != nil {
+		return "", err
+	}
+
+	col := getCollection(db, "twittor", "accessTokens")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.InsertOne(sessCtx, tokenModel)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return "", err
+	}
+
+	result := res.(*mongo.InsertOneResult)
+	objID, _ := result.InsertedID.(primitive.ObjectID)
+
+	return objID.Hex(), nil
+}
+
+/* GetAccessTokens gets the personal access tokens of an user */
+func (db *DbNoSql) GetAccessTokens(userId string) ([]*mr.AccessToken, error) {
+	var results []*mr.AccessToken
+	var tokensDbResults []*m.AccessToken
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return results, err
+	}
+
+	col := getCollection(db, "twittor", "accessTokens")
+	condition := bson.M{"userId": objUserId}
+	opts := options.Find()
+
+	opts.SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, condition, opts)
+
+	if err != nil {
+		return results, err
+	}
+
+	ctxCursor := context.TODO()
+
+	defer cursor.Close(ctxCursor)
+
+	err = cursor.All(ctxCursor, &tokensDbResults)
+
+	if err != nil {
+		return results, err
+	}
+
+	for _, tokenModel := range tokensDbResults {
+		tokenRequest := getAccessTokenRequest(*tokenModel)
+		results = append(results, &tokenRequest)
+	}
+
+	return results, nil
+}
+
+/* GetAccessToken gets a personal access token by its hash */
+func (db *DbNoSql) GetAccessToken(hash string) (mr.AccessToken, bool, error) {
+	var tokenModel m.AccessToken
+	var tokenRequest mr.AccessToken
+
+	col := getCollection(db, "twittor", "accessTokens")
+	condition := bson.M{"hash": hash}
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err := col.FindOne(ctx, condition).Decode(&tokenModel)
+
+	if err != nil && err == mongo.ErrNoDocuments {
+		return tokenRequest, false, nil
+	} else if err != nil {
+		return tokenRequest, false, err
+	}
+
+	tokenRequest = getAccessTokenRequest(tokenModel)
+
+	return tokenRequest, true, nil
+}
+
+/* DeleteAccessToken deletes a personal access token of an user */
+func (db *DbNoSql) DeleteAccessToken(id string, userId string) (bool, error) {
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return false, err
+	}
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return false, err
+	}
+
+	col := getCollection(db, "twittor", "accessTokens")
+	filter := bson.M{
+		"_id":    objId,
+		"userId": objUserId,
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.DeleteOne(sessCtx, filter)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return false, err
+	}
+
+	result := res.(*mongo.DeleteResult)
+
+	return result.DeletedCount > 0, nil
+}
+
+/* DeleteAccessTokens deletes all the personal access tokens of an user */
+func (db *DbNoSql) DeleteAccessTokens(userId string) error {
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return err
+	}
+
+	col := getCollection(db, "twittor", "accessTokens")
+	filter := bson.M{"userId": objUserId}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.DeleteMany(sessCtx, filter)
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	return err
+}
+
+// endregion
+
+// region "Sessions"
+
+/* InsertSession inserts a session in the DB */
+func (db *DbNoSql) InsertSession(session mr.Session) (string, error) {
+	sessionModel, err := getSessionModel(session)
+
+	if err != nil {
+		return "", err
+	}
+
+	col := getCollection(db, "twittor", "sessions")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.InsertOne(sessCtx, sessionModel)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return "", err
+	}
+
+	result := res.(*mongo.InsertOneResult)
+	objID, _ := result.InsertedID.(primitive.ObjectID)
+
+	return objID.Hex(), nil
+}
+
+/* GetSession gets a session by its id */
+func (db *DbNoSql) GetSession(id string) (mr.Session, bool, error) {
+	var sessionModel m.Session
+	var sessionRequest mr.Session
+
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return sessionRequest, false, err
+	}
+
+	col := getCollection(db, "twittor", "sessions")
+	condition := bson.M{"_id": objId}
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err = col.FindOne(ctx, condition).Decode(&sessionModel)
+
+	if err != nil && err == mongo.ErrNoDocuments {
+		return sessionRequest, false, nil
+	} else if err != nil {
+		return sessionRequest, false, err
+	}
+
+	sessionRequest = getSessionRequest(sessionModel)
+
+	return sessionRequest, true, nil
+}
+
+/* GetSessions gets the active sessions of an user */
+func (db *DbNoSql) GetSessions(userId string) ([]*mr.Session, error) {
+	var results []*mr.Session
+	var sessionsDbResults []*m.Session
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return results, err
+	}
+
+	col := getCollection(db, "twittor", "sessions")
+	condition := bson.M{
+		"userId":    objUserId,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+	opts := options.Find()
+
+	opts.SetSort(bson.D{{Key: "lastSeenAt", Value: -1}})
+
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, condition, opts)
+
+	if err != nil {
+		return results, err
+	}
+
+	ctxCursor := context.TODO()
+
+	defer cursor.Close(ctxCursor)
+
+	err = cursor.All(ctxCursor, &sessionsDbResults)
+
+	if err != nil {
+		return results, err
+	}
+
+	for _, sessionModel := range sessionsDbResults {
+		sessionRequest := getSessionRequest(*sessionModel)
+		results = append(results, &sessionRequest)
+	}
+
+	return results, nil
+}
+
+/* TouchSession updates the last time a session was seen and its expiration */
+func (db *DbNoSql) TouchSession(id string, lastSeenAt time.Time, expiresAt time.Time) error {
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return err
+	}
+
+	col := getCollection(db, "twittor", "sessions")
+	filter := bson.M{"_id": objId}
+	update := bson.M{
+		"$set": bson.M{
+			"lastSeenAt": lastSeenAt,
+			"expiresAt":  expiresAt,
+		},
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, update)
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	return err
+}
+
+/* DeleteSession deletes a session of an user */
+func (db *DbNoSql) DeleteSession(id string, userId string) (bool, error) {
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return false, err
+	}
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return false, err
+	}
+
+	col := getCollection(db, "twittor", "sessions")
+	filter := bson.M{
+		"_id":    objId,
+		"userId": objUserId,
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.DeleteOne(sessCtx, filter)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return false, err
+	}
+
+	result := res.(*mongo.DeleteResult)
+
+	return result.DeletedCount > 0, nil
+}
+
+/* DeleteSessions deletes all the sessions of an user except the one with the exceptId, if it is set */
+func (db *DbNoSql) DeleteSessions(userId string, exceptId string) error {
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return err
+	}
+
+	col := getCollection(db, "twittor", "sessions")
+	filter := bson.M{"userId": objUserId}
+
+	if len(exceptId) > 0 {
+		objExceptId, err := getObjectId(exceptId)
+
+		if err != nil {
+			return err
+		}
+
+		filter["_id"] = bson.M{"$ne": objExceptId}
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.DeleteMany(sessCtx, filter)
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	return err
+}
+
+// endregion
+
+// region "Exports"
+
+/* InsertExport inserts an export in the DB */
+func (db *DbNoSql) InsertExport(export mr.Export) (string, error) {
+	exportModel, err := getExportModel(export)
+
+	if err != nil {
+		return "", err
+	}
+
+	col := getCollection(db, "twittor", "exports")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.InsertOne(sessCtx, exportModel)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return "", err
+	}
+
+	result := res.(*mongo.InsertOneResult)
+	objID, _ := result.InsertedID.(primitive.ObjectID)
+
+	return objID.Hex(), nil
+}
+
+/* GetExport gets an export by its id */
+func (db *DbNoSql) GetExport(id string) (mr.Export, bool, error) {
+	var exportModel m.Export
+	var exportRequest mr.Export
+
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return exportRequest, false, err
+	}
+
+	col := getCollection(db, "twittor", "exports")
+	condition := bson.M{"_id": objId}
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err = col.FindOne(ctx, condition).Decode(&exportModel)
+
+	if err != nil && err == mongo.ErrNoDocuments {
+		return exportRequest, false, nil
+	} else if err != nil {
+		return exportRequest, false, err
+	}
+
+	exportRequest = getExportRequest(exportModel)
+
+	return exportRequest, true, nil
+}
+
+/* GetExports gets the exports of an user that have not expired, the newest first */
+func (db *DbNoSql) GetExports(userId string) ([]*mr.Export, error) {
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	condition := bson.M{
+		"userId":    objUserId,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+
+	return db.findExports(condition, bson.D{{Key: "createdAt", Value: -1}})
+}
+
+/* GetExpiredExports gets the exports that expired before a date */
+func (db *DbNoSql) GetExpiredExports(before time.Time) ([]*mr.Export, error) {
+	condition := bson.M{"expiresAt": bson.M{"$lte": before}}
+
+	return db.findExports(condition, bson.D{{Key: "expiresAt", Value: 1}})
+}
+
+/* ModifyExport updates the status and the download token of an export, it returns false if it does not exist */
+func (db *DbNoSql) ModifyExport(export mr.Export) (bool, error) {
+	objId, err := getObjectId(export.Id)
+
+	if err != nil {
+		return false, err
+	}
+
+	col := getCollection(db, "twittor", "exports")
+	filter := bson.M{"_id": objId}
+	update := bson.M{
+		"$set": bson.M{
+			"status":         export.Status,
+			"error":          export.Error,
+			"tokenHash":      export.TokenHash,
+			"tokenExpiresAt": export.TokenExpiresAt,
+		},
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, update)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return false, err
+	}
+
+	return res.(*mongo.UpdateResult).MatchedCount > 0, nil
+}
+
+/* DeleteExport deletes an export */
+func (db *DbNoSql) DeleteExport(id string) error {
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return err
+	}
+
+	col := getCollection(db, "twittor", "exports")
+	filter := bson.M{"_id": objId}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.DeleteOne(sessCtx, filter)
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	return err
+}
+
+// endregion
+
+// region "Login attempts"
+
+/* GetLoginAttempt gets the failed login attempts counted for a key */
+func (db *DbNoSql) GetLoginAttempt(key string) (mr.LoginAttempt, bool, error) {
+	var attemptModel m.LoginAttempt
+	var attemptRequest mr.LoginAttempt
+
+	col := getCollection(db, "twittor", "loginAttempts")
+	condition := bson.M{
+		"key":       key,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err := col.FindOne(ctx, condition).Decode(&attemptModel)
+
+	if err != nil && err == mongo.ErrNoDocuments {
+		return attemptRequest, false, nil
+	} else if err != nil {
+		return attemptRequest, false, err
+	}
+
+	attemptRequest = getLoginAttemptRequest(attemptModel)
+
+	return attemptRequest, true, nil
+}
+
+/* IncrementLoginAttempt adds a failed login attempt to a key and returns the updated counter */
+func (db *DbNoSql) IncrementLoginAttempt(key string, expiresAt time.Time) (mr.LoginAttempt, error) {
+	var attemptModel m.LoginAttempt
+
+	now := time.Now()
+	col := getCollection(db, "twittor", "loginAttempts")
+	filter := bson.M{"key": key}
+	update := bson.M{
+		"$inc": bson.M{"failures": 1},
+		"$set": bson.M{
+			"lastFailureAt": now,
+			"expiresAt":     expiresAt,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		// The TTL index does not remove the expired counters immediately
+		_, err := col.DeleteOne(sessCtx, bson.M{"key": key, "expiresAt": bson.M{"$lte": now}})
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = col.FindOneAndUpdate(sessCtx, filter, update, opts).Decode(&attemptModel)
+
+		return attemptModel, err
+	}
+
+	_, err := db.executeTransaction(callback)
+
+	return getLoginAttemptRequest(attemptModel), err
+}
+
+/* DeleteLoginAttempt resets the failed login attempts of a key */
+func (db *DbNoSql) DeleteLoginAttempt(key string) error {
+	col := getCollection(db, "twittor", "loginAttempts")
+	filter := bson.M{"key": key}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.DeleteOne(sessCtx, filter)
+
+		return result, err
+	}
+
+	_, err := db.executeTransaction(callback)
+
+	return err
+}
+
+// endregion
+
+// region "Helpers"
+
+func (db *DbNoSql) deleteRelationFisical(relation mr.Relation) error {
+	relationModel, err := getRelationModel(relation)
+
+	if err != nil {
+		return err
+	}
+
+	col := getCollection(db, "twittor", "relation")
+	filter := bson.M{
+		"userId":         relationModel.UserId,
+		"userRelationId": relationModel.UserRelationId,
+	}
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.DeleteOne(sessCtx, filter)
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	return err
+}
+
+func (db *DbNoSql) updateRelation(relation mr.Relation, value bool) error {
+	relationModel, err := getRelationModel(relation)
+
+	if err != nil {
+		return err
+	}
+
+	col := getCollection(db, "twittor", "relation")
+	filter := bson.M{
+		"userId":         relationModel.UserId,
+		"userRelationId": relationModel.UserRelationId,
+	}
+	updateString := map[string]map[string]bool{"$set": {"active": value}}
+
+	// Also bson.M{"$set": bson.M{"active": false},} in the updateString
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, updateString)
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	return err
+}
+
+func (db *DbNoSql) deleteTweetFisical(id string, userId string) error {
+	var tweetModel m.Tweet
+
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return err
+	}
+
+	col := getCollection(db, "twittor", "tweet")
+	condition := bson.M{
+		"_id": objId,
+	}
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	err = col.FindOne(ctxFind, condition).Decode(&tweetModel)
+
+	if err != nil {
+		return err
+	}
+
+	objUserId, _ := getObjectId(userId)
+
+	if objUserId != tweetModel.UserId {
+		return errors.New("invalid operation - cannot delete a non-owner tweet")
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.DeleteOne(sessCtx, condition)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	// The images are removed once the tweet is no longer in the DB
+	if err == nil && res.(*mongo.DeleteResult).DeletedCount > 0 {
+		media.Delete(tweetModel.Media)
+	}
+
+	return err
+}
+
+func (db *DbNoSql) insertTweet(tweetModel m.Tweet, claim func(sessCtx mongo.SessionContext) (bool, error)) (string, bool, error) {
+	mentions, err := db.getMentionedIds(tweetModel.Message, tweetModel.UserId)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	tweetModel.Hashtags = helpers.GetHashtags(tweetModel.Message)
+	tweetModel.Mentions = mentions
+
+	col := getCollection(db, "twittor", "tweet")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		// The claim runs in the same transaction, so the tweet is only inserted by who gets it
+		if claim != nil {
+			isClaimed, err := claim(sessCtx)
+
+			if err != nil || !isClaimed {
+				return nil, err
+			}
+		}
+
+		result, err := col.InsertOne(sessCtx, tweetModel)
+
+		if err == nil {
+			err = updateTweetCounters(sessCtx, col, tweetModel, 1)
+		}
+
+		if err == nil {
+			err = db.updateHashtagCounts(sessCtx, tweetModel.Hashtags, tweetModel.Date, 1)
+		}
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil || res == nil {
+		return "", false, err
+	}
+
+	result := res.(*mongo.InsertOneResult)
+	objId := result.InsertedID.(primitive.ObjectID)
+
+	return objId.Hex(), true, nil
+}
+
+/* getMentionedIds resolves the users mentioned in a message by their handles */
+func (db *DbNoSql) getMentionedIds(message string, authorId primitive.ObjectID) ([]primitive.ObjectID, error) {
+	var results []primitive.ObjectID
+	var usersDbResults []*m.User
+
+	mentions := helpers.GetMentions(message)
+
+	if len(mentions) == 0 {
+		return results, nil
+	}
+
+	col := getCollection(db, "twittor", "users")
+	condition := bson.M{"handle": bson.M{"$in": mentions}}
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	cursor, err := col.Find(ctx, condition, opts)
+
+	if err == nil {
+		err = cursor.All(ctx, &usersDbResults)
+	}
+
+	if err != nil {
+		return results, err
+	}
+
+	// The unknown handles are left as plain text
+	for _, userModel := range usersDbResults {
+		if userModel.Id != authorId {
+			results = append(results, userModel.Id)
+		}
+	}
+
+	return results, nil
+}
+
+/* getAvailableHandle returns the base handle, or the first one with a numeric suffix, that no user has */
+func (db *DbNoSql) getAvailableHandle(base string) (string, error) {
+	col := getCollection(db, "twittor", "users")
+	opts := options.Count().SetLimit(1)
+	handle := base
+
+	for i := 2; ; i++ {
+		ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+		count, err := col.CountDocuments(ctx, bson.M{"handle": handle}, opts)
+
+		cancel()
+
+		if err != nil {
+			return "", err
+		}
+
+		if count == 0 {
+			return handle, nil
+		}
+
+		handle = fmt.Sprintf("%s%d", base, i)
+	}
+}
+
+/* setMissingHandles sets the handles of the users registered before the handles were added, the oldest users first */
+func (db *DbNoSql) setMissingHandles() error {
+	var usersDbResults []*m.User
+
+	col := getCollection(db, "twittor", "users")
+	condition := bson.M{"handle": bson.M{"$exists": false}}
+	opts := options.Find().SetProjection(bson.M{"_id": 1, "email": 1}).SetSort(bson.M{"_id": 1})
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, condition, opts)
+
+	if err == nil {
+		err = cursor.All(ctxFind, &usersDbResults)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, userModel := range usersDbResults {
+		handle, err := db.getAvailableHandle(helpers.GetHandle(userModel.Email))
+
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+		_, err = col.UpdateByID(ctx, userModel.Id, bson.M{"$set": bson.M{"handle": handle}})
+
+		cancel()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/* updateHashtagCounts updates the counts of the trending hashtags in the time bucket of a tweet */
+func (db *DbNoSql) updateHashtagCounts(sessCtx mongo.SessionContext, hashtags []string, date time.Time, delta int) error {
+	bucket := helpers.GetTrendsBucket(date)
+	expiresAt := helpers.GetTrendsBucketExpiration(bucket)
+
+	// The buckets out of the trends window are not read anymore
+	if len(hashtags) == 0 || expiresAt.Before(time.Now()) {
+		return nil
+	}
+
+	col := getCollection(db, "twittor", "hashtagCounts")
+	opts := options.Update().SetUpsert(true)
+
+	for _, hashtag := range hashtags {
+		_, err := col.UpdateOne(sessCtx,
+			bson.M{"tag": hashtag, "bucket": bucket},
+			bson.M{
+				"$inc":         bson.M{"count": delta},
+				"$setOnInsert": bson.M{"expiresAt": expiresAt},
+			},
+			opts)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/* deleteUserLikes deletes the likes given by an user and the likes of its tweets */
+func (db *DbNoSql) deleteUserLikes(sessCtx mongo.SessionContext, userId primitive.ObjectID, tweetIds []any) error {
+	likesCol := getCollection(db, "twittor", "likes")
+	tweetsCol := getCollection(db, "twittor", "tweet")
+
+	likedIds, err := likesCol.Distinct(sessCtx, "tweetId", bson.M{"userId": userId})
+
+	if err != nil {
+		return err
+	}
+
+	// The counts of the tweets liked by the user are kept consistent
+	if len(likedIds) > 0 {
+		_, err = tweetsCol.UpdateMany(sessCtx,
+			bson.M{"_id": bson.M{"$in": likedIds}},
+			bson.M{"$inc": bson.M{"likeCount": -1}})
+
+		if err != nil {
+			return err
 		}
 	}
 
-	return results, total, err
+	_, err = likesCol.DeleteMany(sessCtx, bson.M{"userId": userId})
+
+	if err != nil {
+		return err
+	}
+
+	if len(tweetIds) == 0 {
+		return nil
+	}
+
+	_, err = likesCol.DeleteMany(sessCtx, bson.M{"tweetId": bson.M{"$in": tweetIds}})
+
+	return err
 }
 
-/* GetFollowingTweets returns the following's tweets */
-func (db *DbNoSql) GetFollowingTweets(id string, page int64, limit int64, isOnlyTweets bool) (any, int64, error) {
-	var results any
-	var total int64
-	var err error
+/* revertUserTweetCounters undoes the counters added by the active tweets of an user to other tweets and to the trends */
+func (db *DbNoSql) revertUserTweetCounters(sessCtx mongo.SessionContext, userId primitive.ObjectID) error {
+	var tweetsDbResults []m.Tweet
 
-	objId, err := getObjectId(id)
+	col := getCollection(db, "twittor", "tweet")
+	cursor, err := col.Find(sessCtx, bson.M{"userId": userId, "active": true})
 
 	if err != nil {
-		return results, 0, err
+		return err
 	}
 
-	conditions := make([]bson.M, 0)
-	conditionsCount := make([]bson.M, 0)
-	conditionsAgg := make([]bson.M, 0)
+	defer cursor.Close(sessCtx)
 
-	skip := (page - 1) * limit
+	err = cursor.All(sessCtx, &tweetsDbResults)
 
-	conditions = append(conditions, bson.M{"$match": bson.M{"userId": objId, "active": true}})
-	conditions = append(conditions, bson.M{
-		"$lookup": bson.M{
-			"from":         "tweet",
-			"localField":   "userRelationId",
-			"foreignField": "userId",
-			"as":           "tweet",
-			"pipeline": []bson.M{{
-				"$match": bson.M{
-					"active": true}},
-			},
-		}})
-	conditions = append(conditions, bson.M{
-		"$unwind": bson.M{
-			"path":                       "$tweet",
-			"preserveNullAndEmptyArrays": false,
-		},
-	})
+	if err != nil {
+		return err
+	}
 
-	conditionsCount = append(conditionsCount, conditions...)
-	conditionsCount = append(conditionsCount, bson.M{"$count": "total"})
+	for _, tweetModel := range tweetsDbResults {
+		err = updateTweetCounters(sessCtx, col, tweetModel, -1)
 
-	conditionsAgg = append(conditionsAgg, conditions...)
-	conditionsAgg = append(conditionsAgg, bson.M{"$sort": bson.M{"tweet.date": -1}})
-	conditionsAgg = append(conditionsAgg, bson.M{"$skip": skip})
-	conditionsAgg = append(conditionsAgg, bson.M{"$limit": limit})
+		if err == nil {
+			err = db.updateHashtagCounts(sessCtx, tweetModel.Hashtags, tweetModel.Date, -1)
+		}
 
-	if isOnlyTweets {
-		conditionsAgg = append(conditionsAgg, bson.M{
-			"$project": bson.M{
-				"_id":     "$tweet._id",
-				"userId":  "$tweet.userId",
-				"message": "$tweet.message",
-				"date":    "$tweet.date",
-			}})
+		if err != nil {
+			return err
+		}
+	}
 
-		var dbResults []*m.Tweet
-		var reqResults []*mr.Tweet
+	return nil
+}
 
-		dbResults, total, err = getResults[m.Tweet](db, "relation", conditionsCount, conditionsAgg)
+/* updateTweetCounters updates the counters of the tweets replied, retweeted or quoted by a tweet */
+func updateTweetCounters(sessCtx mongo.SessionContext, col *mongo.Collection, tweetModel m.Tweet, delta int) error {
+	counters := map[string]primitive.ObjectID{
+		"replyCount":   tweetModel.InReplyTo,
+		"retweetCount": tweetModel.RetweetOf,
+		"quoteCount":   tweetModel.QuotedTweetId,
+	}
 
-		if err == nil {
-			for _, tweetModel := range dbResults {
-				tweetRequest := getTweetRequest(*tweetModel)
-				reqResults = append(reqResults, &tweetRequest)
-			}
+	for counter, objId := range counters {
+		if objId.IsZero() {
+			continue
+		}
 
-			results = reqResults
+		_, err := col.UpdateOne(sessCtx, bson.M{"_id": objId}, bson.M{"$inc": bson.M{counter: delta}})
+
+		if err != nil {
+			return err
 		}
-	} else {
-		var dbResults []*m.UserTweet
-		var reqResults []*mr.UserTweet
+	}
 
-		dbResults, total, err = getResults[m.UserTweet](db, "relation", conditionsCount, conditionsAgg)
+	return nil
+}
 
-		if err == nil {
-			for _, userTweetModel := range dbResults {
-				userTweetRequest := getUserTweetRequest(*userTweetModel)
-				reqResults = append(reqResults, &userTweetRequest)
-			}
+func (db *DbNoSql) findTweets(condition bson.M, page int64, limit int64, isNewestFirst bool) ([]*mr.Tweet, int64, error) {
+	order := 1
 
-			results = reqResults
-		}
+	if isNewestFirst {
+		order = -1
 	}
 
-	return results, total, err
+	return db.findSortedTweets(condition, page, limit, bson.D{{Key: "date", Value: order}})
 }
 
-// endregion
+func (db *DbNoSql) findSortedTweets(condition bson.M, page int64, limit int64, sort bson.D) ([]*mr.Tweet, int64, error) {
+	var results []*mr.Tweet
+	var tweetsDbResults []*m.Tweet
 
-// region "Helpers"
+	col := getCollection(db, "twittor", "tweet")
+	opts := options.Find()
 
-func (db *DbNoSql) deleteRelationFisical(relation mr.Relation) error {
-	relationModel, err := getRelationModel(relation)
+	opts.SetSort(sort)
+
+	ctxCount, cancelCount := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelCount()
+
+	total, err := col.CountDocuments(ctxCount, condition)
 
 	if err != nil {
-		return err
+		return results, total, err
 	}
 
-	col := getCollection(db, "twittor", "relation")
-	filter := bson.M{
-		"userId":         relationModel.UserId,
-		"userRelationId": relationModel.UserRelationId,
+	// Without a limit all the tweets are returned
+	if limit > 0 {
+		opts.SetSkip((page - 1) * limit)
+		opts.SetLimit(limit)
 	}
-	callback := func(sessCtx mongo.SessionContext) (any, error) {
-		result, err := col.DeleteOne(sessCtx, filter)
 
-		return result, err
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, condition, opts)
+
+	if err != nil {
+		return results, total, err
 	}
 
-	_, err = db.executeTransaction(callback)
+	ctxCursor := context.TODO()
 
-	return err
-}
+	defer cursor.Close(ctxCursor)
 
-func (db *DbNoSql) updateRelation(relation mr.Relation, value bool) error {
-	relationModel, err := getRelationModel(relation)
+	err = cursor.All(ctxCursor, &tweetsDbResults)
 
 	if err != nil {
-		return err
+		return results, total, err
 	}
 
-	col := getCollection(db, "twittor", "relation")
-	filter := bson.M{
-		"userId":         relationModel.UserId,
-		"userRelationId": relationModel.UserRelationId,
+	for _, tweetModel := range tweetsDbResults {
+		tweetRequest := getTweetRequest(*tweetModel)
+		results = append(results, &tweetRequest)
 	}
-	updateString := map[string]map[string]bool{"$set": {"active": value}}
 
-	// Also bson.M{"$set": bson.M{"active": false},} in the updateString
+	return results, total, nil
+}
 
-	callback := func(sessCtx mongo.SessionContext) (any, error) {
-		result, err := col.UpdateOne(sessCtx, filter, updateString)
+func getDraftFilter(id string, userId string) (bson.M, error) {
+	objId, err := getObjectId(id)
 
-		return result, err
+	if err != nil {
+		return nil, err
 	}
 
-	_, err = db.executeTransaction(callback)
+	objUserId, err := getObjectId(userId)
 
-	return err
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"_id":    objId,
+		"userId": objUserId,
+	}
+
+	return filter, nil
 }
 
-func (db *DbNoSql) deleteTweetFisical(id string, userId string) error {
-	var tweetModel m.Tweet
+func (db *DbNoSql) findScheduledTweets(condition bson.M, page int64, limit int64) ([]*mr.ScheduledTweet, int64, error) {
+	var results []*mr.ScheduledTweet
+	var scheduledDbResults []*m.ScheduledTweet
 
-	objId, err := getObjectId(id)
+	col := getCollection(db, "twittor", "scheduledTweets")
+	opts := options.Find()
+
+	opts.SetSort(bson.D{{Key: "publishAt", Value: 1}})
+
+	ctxCount, cancelCount := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelCount()
+
+	total, err := col.CountDocuments(ctxCount, condition)
 
 	if err != nil {
-		return err
+		return results, total, err
 	}
 
-	col := getCollection(db, "twittor", "tweet")
-	condition := bson.M{
-		"_id": objId,
+	// Without a limit all the tweets are returned
+	if limit > 0 {
+		opts.SetSkip((page - 1) * limit)
+		opts.SetLimit(limit)
 	}
+
 	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
 	defer cancelFind()
 
-	err = col.FindOne(ctxFind, condition).Decode(&tweetModel)
+	cursor, err := col.Find(ctxFind, condition, opts)
 
 	if err != nil {
-		return err
+		return results, total, err
 	}
 
-	objUserId, _ := getObjectId(userId)
+	ctxCursor := context.TODO()
 
-	if objUserId != tweetModel.UserId {
-		return errors.New("invalid operation - cannot delete a non-owner tweet")
+	defer cursor.Close(ctxCursor)
+
+	err = cursor.All(ctxCursor, &scheduledDbResults)
+
+	if err != nil {
+		return results, total, err
 	}
 
-	callback := func(sessCtx mongo.SessionContext) (any, error) {
-		result, err := col.DeleteOne(sessCtx, condition)
+	for _, scheduledModel := range scheduledDbResults {
+		scheduledRequest := getScheduledTweetRequest(*scheduledModel)
+		results = append(results, &scheduledRequest)
+	}
 
-		return result, err
+	return results, total, nil
+}
+
+func (db *DbNoSql) findExports(condition bson.M, sort bson.D) ([]*mr.Export, error) {
+	var results []*mr.Export
+	var exportsDbResults []*m.Export
+
+	col := getCollection(db, "twittor", "exports")
+	opts := options.Find()
+
+	opts.SetSort(sort)
+
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, condition, opts)
+
+	if err != nil {
+		return results, err
 	}
 
-	_, err = db.executeTransaction(callback)
+	ctxCursor := context.TODO()
 
-	return err
+	defer cursor.Close(ctxCursor)
+
+	err = cursor.All(ctxCursor, &exportsDbResults)
+
+	if err != nil {
+		return results, err
+	}
+
+	for _, exportModel := range exportsDbResults {
+		exportRequest := getExportRequest(*exportModel)
+		results = append(results, &exportRequest)
+	}
+
+	return results, nil
 }
 
 func (db *DbNoSql) deleteTweetLogical(id string, userId string) error {
@@ -855,6 +3393,11 @@ func (db *DbNoSql) deleteTweetLogical(id string, userId string) error {
 		return errors.New("invalid operation - cannot delete a non-owner tweet")
 	}
 
+	// Only the request that deactivates the tweet changes the counters, so concurrent deletes do not decrement them twice
+	filter := bson.M{
+		"_id":    objId,
+		"active": true,
+	}
 	updateString := bson.M{
 		"$set": bson.M{"active": false},
 	}
@@ -862,7 +3405,17 @@ func (db *DbNoSql) deleteTweetLogical(id string, userId string) error {
 	// Also map[string]map[string]bool{"$set": {"active": false}} in the updateString
 
 	callback := func(sessCtx mongo.SessionContext) (any, error) {
-		result, err := col.UpdateOne(sessCtx, condition, updateString)
+		result, err := col.UpdateOne(sessCtx, filter, updateString)
+		isDeactivated := err == nil && result.ModifiedCount == 1
+
+		// The replies, retweets and quotes are kept, only the counters of the referenced tweets change
+		if isDeactivated {
+			err = updateTweetCounters(sessCtx, col, tweetModel, -1)
+		}
+
+		if err == nil && isDeactivated {
+			err = db.updateHashtagCounts(sessCtx, tweetModel.Hashtags, tweetModel.Date, -1)
+		}
 
 		return result, err
 	}
@@ -873,16 +3426,104 @@ func (db *DbNoSql) deleteTweetLogical(id string, userId string) error {
 }
 
 func encryptPassword(password string) (string, error) {
-	// Minimum - cost: 6
-	// Common user - cost: 6
-	// Admin user - cost: 8
-
-	cost := 8
+	cost := helpers.GetBcryptCost()
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), cost)
 
 	return string(bytes), err
 }
 
+func (db *DbNoSql) createIndexes() error {
+	indexes := map[string][]mongo.IndexModel{
+		"users": {
+			{
+				Keys: bson.D{{Key: "handle", Value: 1}},
+				Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+					"handle": bson.M{"$type": "string"},
+				}),
+			},
+		},
+		"revokedTokens": {
+			{Keys: bson.D{{Key: "tokenId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"actionTokens": {
+			{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "action", Value: 1}}},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"accessTokens": {
+			{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "userId", Value: 1}}},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"sessions": {
+			{Keys: bson.D{{Key: "userId", Value: 1}}},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"loginAttempts": {
+			{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"likes": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "tweetId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "date", Value: -1}}},
+			{Keys: bson.D{{Key: "tweetId", Value: 1}}},
+		},
+		"drafts": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "date", Value: -1}}},
+		},
+		"exports": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}},
+		},
+		"bookmarks": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "tweetId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "date", Value: -1}}},
+		},
+		"scheduledTweets": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "publishAt", Value: 1}}},
+			{Keys: bson.D{{Key: "publishAt", Value: 1}}},
+		},
+		"tweetRevisions": {
+			{Keys: bson.D{{Key: "tweetId", Value: 1}, {Key: "date", Value: 1}}},
+		},
+		"hashtagCounts": {
+			{Keys: bson.D{{Key: "tag", Value: 1}, {Key: "bucket", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "bucket", Value: 1}}},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"tweet": {
+			{Keys: bson.D{{Key: "hashtags", Value: 1}, {Key: "date", Value: -1}}},
+			{Keys: bson.D{{Key: "mentions", Value: 1}, {Key: "date", Value: -1}}},
+			{Keys: bson.D{{Key: "inReplyTo", Value: 1}, {Key: "date", Value: 1}}},
+			{Keys: bson.D{{Key: "conversationId", Value: 1}}},
+			{Keys: bson.D{{Key: "message", Value: "text"}}, Options: options.Index().SetDefaultLanguage("english")},
+			{
+				Keys: bson.D{{Key: "userId", Value: 1}, {Key: "retweetOf", Value: 1}},
+				Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+					"retweetOf": bson.M{"$exists": true},
+					"active":    true,
+				}),
+			},
+		},
+	}
+
+	for colName, models := range indexes {
+		col := getCollection(db, "twittor", colName)
+		ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+		_, err := col.Indexes().CreateMany(ctx, models)
+
+		cancel()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (db *DbNoSql) executeTransaction(callback func(sessCtx mongo.SessionContext) (any, error)) (any, error) {
 	session, err := db.Connection.StartSession()
 
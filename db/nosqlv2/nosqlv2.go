@@ -6,8 +6,12 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"regexp"
+	"strings"
+	"time"
 
 	"helpers"
+	"media"
 	m "models/nosqlv2"
 	mr "models/request"
 
@@ -40,7 +44,16 @@ func (db *DbNoSqlV2) Connect() error {
 
 	db.Connection = client
 
-	return nil
+	err = db.createIndexes()
+
+	if err != nil {
+		return err
+	}
+
+	// The users registered before the handles were added can not be mentioned until they have one
+	err = db.setMissingHandles()
+
+	return err
 }
 
 /* IsConnection makes a ping to the Database */
@@ -107,6 +120,13 @@ func (db *DbNoSqlV2) InsertUser(user mr.User) (string, error) {
 	col := getCollection(db, "twitton", "users")
 
 	user.Password, _ = encryptPassword(user.Password)
+	handle, err := db.getAvailableHandle(helpers.GetHandle(user.Email))
+
+	if err != nil {
+		return "", err
+	}
+
+	user.Handle = handle
 	userModel, err := getUserModel(user)
 
 	if err != nil {
@@ -128,7 +148,7 @@ func (db *DbNoSqlV2) InsertUser(user mr.User) (string, error) {
 	result := res.(*mongo.InsertOneResult)
 	objID, _ := result.InsertedID.(primitive.ObjectID)
 
-	return objID.String(), nil
+	return objID.Hex(), nil
 }
 
 /* IsUser checks that the user already exists in the DB */
@@ -215,187 +235,197 @@ func (db *DbNoSqlV2) ModifyRegistry(id string, user mr.User) error {
 	return err
 }
 
-/* TryLogin makes the login to the DB */
-func (db *DbNoSqlV2) TryLogin(email string, password string) (mr.User, bool) {
-	var requestModel mr.User
-
-	isFound, requestModel, err := db.IsUser(email)
+/* ModifyPassword encrypts and modifies the password of an user in the DB */
+func (db *DbNoSqlV2) ModifyPassword(id string, password string) error {
+	objId, err := getObjectId(id)
 
-	if err != nil || !isFound {
-		return requestModel, false
+	if err != nil {
+		return err
 	}
 
-	passwordBytes := []byte(password)
-	passwordDB := []byte(requestModel.Password)
-	err = bcrypt.CompareHashAndPassword(passwordDB, passwordBytes)
+	encryptedPassword, err := encryptPassword(password)
 
 	if err != nil {
-		return requestModel, false
+		return err
 	}
 
-	return requestModel, true
-}
+	col := getCollection(db, "twitton", "users")
+	filter := bson.M{"_id": objId}
+	updateString := bson.M{
+		"$set": bson.M{"password": encryptedPassword},
+	}
 
-// endregion
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, updateString)
 
-// region "Tweets"
+		return result, err
+	}
 
-/* DeleteTweet deletes a tweet in the DB */
-func (db *DbNoSqlV2) DeleteTweet(id string, userId string) error {
-	err := db.deleteTweetLogical(id, userId)
+	_, err = db.executeTransaction(callback)
 
 	return err
 }
 
-/* Get gets an user's tweets from the DB */
-func (db *DbNoSqlV2) GetTweets(id string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
-	var results []*mr.Tweet
-
+/* VerifyUser marks the email address of an user as verified in the DB */
+func (db *DbNoSqlV2) VerifyUser(id string) error {
 	objId, err := getObjectId(id)
 
 	if err != nil {
-		return results, 0, err
+		return err
 	}
 
-	// region Pipeline
+	col := getCollection(db, "twitton", "users")
+	filter := bson.M{"_id": objId}
+	updateString := bson.M{
+		"$set": bson.M{"verified": true},
+	}
 
-	matchId := bson.M{"$match": bson.M{"_id": objId}}
-	projectTweets := bson.M{"$project": bson.M{
-		"t":   "$tweets",
-		"_id": 0}}
-	unwindTweets := bson.M{"$unwind": bson.M{
-		"path":                       "$t",
-		"preserveNullAndEmptyArrays": false}}
-	filterTweets := bson.M{"$match": bson.M{"t.active": true}}
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, updateString)
 
-	count := bson.M{"$count": "total"}
+		return result, err
+	}
 
-	sort := bson.M{"$sort": bson.M{"t.date": -1}}
-	skip := bson.M{"$skip": (page - 1) * limit}
-	agLimit := bson.M{"$limit": limit}
-	projectResult := bson.M{"$project": bson.M{
-		"_id":     "$t._id",
-		"message": "$t.message",
-		"date":    "$t.date",
-		"active":  "$t.active"}}
+	_, err = db.executeTransaction(callback)
 
-	basePipeline := []bson.M{matchId, projectTweets, unwindTweets, filterTweets}
-	countPipeline := append(basePipeline, count)
-	aggPipeline := append(basePipeline, sort, skip, agLimit, projectResult)
+	return err
+}
 
-	// endregion
+/* RevokeUserTokens invalidates all the tokens issued to an user until now */
+func (db *DbNoSqlV2) RevokeUserTokens(id string) error {
+	objId, err := getObjectId(id)
 
-	dbResults, total, err := getResults[m.Tweet](db, "users", countPipeline, aggPipeline)
+	if err != nil {
+		return err
+	}
 
-	if err == nil {
-		for _, tweetModel := range dbResults {
-			tweetRequest := getTweetRequest(*tweetModel)
-			results = append(results, &tweetRequest)
+	col := getCollection(db, "twitton", "users")
+	filter := bson.M{"_id": objId}
+	updateString := bson.M{
+		"$set": bson.M{"tokensRevokedAt": time.Now()},
+	}
 
-			tweetRequest.UserId = ""
-		}
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, updateString)
+
+		return result, err
 	}
 
-	return results, total, err
+	_, err = db.executeTransaction(callback)
+
+	return err
 }
 
-/* InsertTweet inserts a tweet in the DB */
-func (db *DbNoSqlV2) InsertTweet(tweet mr.Tweet) (string, error) {
-	objId, _ := getObjectId(tweet.UserId)
-	update := bson.M{
-		"$push": bson.M{
-			"tweets": bson.D{
-				primitive.E{Key: "_id", Value: primitive.NewObjectID()},
-				primitive.E{Key: "message", Value: tweet.Message},
-				primitive.E{Key: "date", Value: tweet.Date},
-				primitive.E{Key: "active", Value: tweet.Active},
-			},
-		},
+/* SetTotp sets the two-factor authentication secret of an user in the DB */
+func (db *DbNoSqlV2) SetTotp(id string, secret string, isEnabled bool) error {
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return err
 	}
 
 	col := getCollection(db, "twitton", "users")
+	filter := bson.M{"_id": objId}
+	updateString := bson.M{
+		"$set": bson.M{
+			"totpSecret":  secret,
+			"totpEnabled": isEnabled,
+		},
+	}
+
 	callback := func(sessCtx mongo.SessionContext) (any, error) {
-		result, err := col.UpdateByID(sessCtx, objId, update)
+		result, err := col.UpdateOne(sessCtx, filter, updateString)
 
 		return result, err
 	}
 
-	res, err := db.executeTransaction(callback)
+	_, err = db.executeTransaction(callback)
+
+	return err
+}
+
+/* UseTotpCounter saves the counter of the last two-factor code accepted, it returns false if a code of that counter or a later one had already been used */
+func (db *DbNoSqlV2) UseTotpCounter(id string, counter int64) (bool, error) {
+	objId, err := getObjectId(id)
 
 	if err != nil {
-		return "", err
+		return false, err
 	}
 
-	result := res.(*mongo.UpdateResult)
-	objID, _ := result.UpsertedID.(primitive.ObjectID)
-
-	// The same goes with objId.String()
-	return objID.Hex(), nil
-}
+	col := getCollection(db, "twitton", "users")
+	filter := bson.M{
+		"_id": objId,
+		"$or": []bson.M{
+			{"totpLastCounter": bson.M{"$lt": counter}},
+			{"totpLastCounter": bson.M{"$exists": false}},
+		},
+	}
+	updateString := bson.M{
+		"$set": bson.M{"totpLastCounter": counter},
+	}
 
-// endregion
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, updateString)
 
-// region "Relations"
+		return result, err
+	}
 
-/* IsRelation obtains a relation from the DB if exist */
-func (db *DbNoSqlV2) IsRelation(relation mr.Relation) (bool, mr.Relation, error) {
-	objId, _ := getObjectId(relation.UserId)
-	objUserFollowingId, err := getObjectId(relation.UserRelationId)
+	res, err := db.executeTransaction(callback)
 
 	if err != nil {
-		return false, relation, err
+		return false, err
 	}
 
-	col := getCollection(db, "twitton", "users")
-	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+	return res.(*mongo.UpdateResult).ModifiedCount > 0, nil
+}
 
-	defer cancel()
+/* SetRole sets the role of an user */
+func (db *DbNoSqlV2) SetRole(id string, role string) error {
+	objId, err := getObjectId(id)
 
-	filter := bson.M{
-		"_id": objId,
-		"following": bson.M{
-			"$in": [1]primitive.ObjectID{
-				objUserFollowingId,
-			}},
+	if err != nil {
+		return err
 	}
 
-	err = col.FindOne(ctx, filter).Err()
+	col := getCollection(db, "twitton", "users")
+	filter := bson.M{"_id": objId}
+	updateString := bson.M{
+		"$set": bson.M{"role": role},
+	}
 
-	if err != nil && err == mongo.ErrNoDocuments {
-		return false, relation, nil
-	} else if err != nil && err != mongo.ErrNoDocuments {
-		return false, relation, err
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, updateString)
+
+		return result, err
 	}
 
-	relation.Active = true
+	_, err = db.executeTransaction(callback)
 
-	return true, relation, nil
+	return err
 }
 
-/* InsertRelation creates a relation into the DB */
-func (db *DbNoSqlV2) InsertRelation(relation mr.Relation) error {
-	_, isFound, err := db.GetProfile(relation.UserRelationId)
+/* ScheduleUserDeletion sets when an user will be deleted, a zero deleteAt cancels the deletion */
+func (db *DbNoSqlV2) ScheduleUserDeletion(id string, deleteAt time.Time) error {
+	objId, err := getObjectId(id)
 
 	if err != nil {
 		return err
 	}
 
-	if !isFound {
-		return errors.New("no registry found in the DB")
+	col := getCollection(db, "twitton", "users")
+	filter := bson.M{"_id": objId}
+	updateString := bson.M{
+		"$set": bson.M{"deleteAt": deleteAt},
 	}
 
-	objId, _ := getObjectId(relation.UserId)
-	objUserFollowingId, _ := getObjectId(relation.UserRelationId)
-
-	update := bson.M{
-		"$addToSet": bson.M{
-			"following": objUserFollowingId,
-		},
+	if deleteAt.IsZero() {
+		updateString = bson.M{
+			"$unset": bson.M{"deleteAt": ""},
+		}
 	}
 
-	col := getCollection(db, "twitton", "users")
 	callback := func(sessCtx mongo.SessionContext) (any, error) {
-		result, err := col.UpdateByID(sessCtx, objId, update)
+		result, err := col.UpdateOne(sessCtx, filter, updateString)
 
 		return result, err
 	}
@@ -405,328 +435,2690 @@ func (db *DbNoSqlV2) InsertRelation(relation mr.Relation) error {
 	return err
 }
 
-/* DeleteRelation deletes a relation in the DB */
-func (db *DbNoSqlV2) DeleteRelation(relation mr.Relation) error {
-	err := db.deleteRelationFisical(relation)
-
-	return err
-}
-
-/* GetUsers gets a list of users */
-func (db *DbNoSqlV2) GetUsers(id string, page int64, limit int64, search string, searchType string) ([]*mr.User, int64, error) {
+/* GetUsersToDelete gets the users with a deletion scheduled before a time */
+func (db *DbNoSqlV2) GetUsersToDelete(before time.Time) ([]*mr.User, error) {
 	var results []*mr.User
-	var total int64
+	var usersDbResults []*m.User
 
 	col := getCollection(db, "twitton", "users")
-
-	query := bson.M{
-		"name": bson.M{"$regex": search, "$options": "im"},
+	condition := bson.M{
+		"deleteAt": bson.M{"$lte": before},
 	}
+	opts := options.Find()
 
-	findOpts := options.Find()
-
-	findOpts.SetSort(bson.D{{Key: "birthDate", Value: -1}})
-	findOpts.SetSkip((page - 1) * limit)
-	findOpts.SetLimit(limit)
+	opts.SetProjection(bson.M{"tweets": 0, "following": 0})
 
 	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
 	defer cancelFind()
 
-	cursor, err := col.Find(ctxFind, query, findOpts)
+	cursor, err := col.Find(ctxFind, condition, opts)
 
 	if err != nil {
-		return results, total, err
+		return results, err
 	}
 
 	ctxCursor := context.TODO()
 
 	defer cursor.Close(ctxCursor)
 
-	var include, isRelation bool
+	err = cursor.All(ctxCursor, &usersDbResults)
 
-	for cursor.Next(ctxCursor) {
-		var result m.User
+	if err != nil {
+		return results, err
+	}
 
-		isRelation = false
-		include = false
+	for _, userModel := range usersDbResults {
+		userRequest := getUserRequest(*userModel)
+		results = append(results, &userRequest)
+	}
 
-		err := cursor.Decode(&result)
+	return results, nil
+}
+
+/* DeleteUser deletes an user with all its data */
+func (db *DbNoSqlV2) DeleteUser(id string) error {
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return err
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		tweetIds, err := getCollection(db, "twitton", "users").Distinct(sessCtx, "tweets._id", bson.M{"_id": objId})
 
 		if err != nil {
-			return results, total, err
+			return nil, err
 		}
 
-		userRequest := getUserRequest(result)
+		err = db.deleteUserLikes(sessCtx, objId, tweetIds)
 
-		relationRequest := mr.Relation{
-			UserId:         id,
-			UserRelationId: userRequest.Id,
-			Active:         true,
+		if err != nil {
+			return nil, err
 		}
 
-		isFound, relationDb, err := db.IsRelation(relationRequest)
+		err = db.revertUserTweetCounters(sessCtx, objId)
 
 		if err != nil {
-			return results, total, err
+			return nil, err
 		}
 
-		if isFound {
-			isRelation = relationDb.Active
+		// The revisions of the edited tweets are deleted with them
+		if len(tweetIds) > 0 {
+			_, err = getCollection(db, "twitton", "tweetRevisions").DeleteMany(sessCtx, bson.M{"tweetId": bson.M{"$in": tweetIds}})
+
+			if err != nil {
+				return nil, err
+			}
 		}
 
-		if relationRequest.UserRelationId == id {
-			include = false
-		} else if (searchType == "new" && !isRelation) || (searchType == "follow" && isRelation) {
-			include = true
+		// The tweets are embedded in the user, so only the other users following it must be updated
+		_, err = getCollection(db, "twitton", "users").UpdateMany(sessCtx,
+			bson.M{"following": objId},
+			bson.M{"$pull": bson.M{"following": objId}})
+
+		if err != nil {
+			return nil, err
 		}
 
-		if include {
-			userRequest.Email = ""
-			userRequest.Password = ""
-			userRequest.Avatar = ""
-			userRequest.Banner = ""
-			userRequest.Biography = ""
-			userRequest.Location = ""
-			userRequest.WebSite = ""
+		// The mentions of the user in other tweets are left as plain text
+		_, err = getCollection(db, "twitton", "users").UpdateMany(sessCtx,
+			bson.M{"tweets.mentions": objId},
+			bson.M{"$pull": bson.M{"tweets.$[].mentions": objId}})
 
-			results = append(results, &userRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, colName := range []string{"revokedTokens", "actionTokens", "accessTokens", "sessions", "scheduledTweets", "drafts", "bookmarks"} {
+			_, err = getCollection(db, "twitton", colName).DeleteMany(sessCtx, bson.M{"userId": objId})
+
+			if err != nil {
+				return nil, err
+			}
 		}
+
+		result, err := getCollection(db, "twitton", "users").DeleteOne(sessCtx, bson.M{"_id": objId})
+
+		return result, err
 	}
 
-	err = cursor.Err()
+	_, err = db.executeTransaction(callback)
+
+	return err
+}
+
+/* TryLogin makes the login to the DB */
+func (db *DbNoSqlV2) TryLogin(email string, password string) (mr.User, bool) {
+	var requestModel mr.User
+
+	isFound, requestModel, err := db.IsUser(email)
+
+	if err != nil || !isFound {
+		return requestModel, false
+	}
+
+	passwordBytes := []byte(password)
+	passwordDB := []byte(requestModel.Password)
+	err = bcrypt.CompareHashAndPassword(passwordDB, passwordBytes)
 
 	if err != nil {
-		fmt.Println(err.Error())
+		return requestModel, false
+	}
 
-		return results, total, err
+	// The password is encrypted again when the configured cost has been increased
+	cost, err := bcrypt.Cost(passwordDB)
+
+	if err == nil && cost < helpers.GetBcryptCost() {
+		err = db.ModifyPassword(requestModel.Id, password)
+
+		if err != nil {
+			log.Println("Error encrypting the password again: " + err.Error())
+		}
 	}
 
-	// This total only reflects the total returned registries
-	total = int64(len(results))
+	return requestModel, true
+}
 
-	return results, total, nil
+// endregion
+
+// region "Tweets"
+
+/* DeleteTweet deletes a tweet in the DB */
+func (db *DbNoSqlV2) DeleteTweet(id string, userId string) error {
+	err := db.deleteTweetLogical(id, userId)
+
+	return err
 }
 
-/* GetFollowing gets an user's following list */
-func (db *DbNoSqlV2) GetFollowing(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
-	var results []*mr.User
+/* Get gets an user's tweets from the DB */
+func (db *DbNoSqlV2) GetTweets(id string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	var results []*mr.Tweet
 
-	objId, _ := getObjectId(id)
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return results, 0, err
+	}
 
 	// region Pipeline
 
 	matchId := bson.M{"$match": bson.M{"_id": objId}}
-	lookupUsers := bson.M{"$lookup": bson.M{
-		"from":         "users",
-		"localField":   "following",
-		"foreignField": "_id",
-		"as":           "r"}}
-	projectResult := bson.M{"$project": bson.M{
-		"u":   "$r",
+	projectTweets := bson.M{"$project": bson.M{
+		"t":   "$tweets",
 		"_id": 0}}
-	unwind := bson.M{"$unwind": bson.M{
-		"path":                       "$u",
+	unwindTweets := bson.M{"$unwind": bson.M{
+		"path":                       "$t",
 		"preserveNullAndEmptyArrays": false}}
-	matchName := bson.M{"$match": bson.M{"u.name": bson.M{"$regex": search, "$options": "im"}}}
+	filterTweets := bson.M{"$match": bson.M{"t.active": true}}
 
 	count := bson.M{"$count": "total"}
 
-	sort := bson.M{"$sort": bson.M{"u.birthDate": -1}}
+	sort := bson.M{"$sort": bson.M{"t.date": -1}}
 	skip := bson.M{"$skip": (page - 1) * limit}
 	agLimit := bson.M{"$limit": limit}
-	projectUser := bson.M{"$project": bson.M{
-		"_id":       "$u._id",
-		"name":      "$u.name",
-		"lastName":  "$u.lastName",
-		"birthDate": "$u.birthDate"}}
+	projectResult := bson.M{"$project": bson.M{
+		"_id":            "$t._id",
+		"message":        "$t.message",
+		"date":           "$t.date",
+		"active":         "$t.active",
+		"inReplyTo":      "$t.inReplyTo",
+		"conversationId": "$t.conversationId",
+		"replyCount":     "$t.replyCount",
+		"retweetOf":      "$t.retweetOf",
+		"quotedTweetId":  "$t.quotedTweetId",
+		"retweetCount":   "$t.retweetCount",
+		"quoteCount":     "$t.quoteCount",
+		"likeCount":      "$t.likeCount",
+		"editedAt":       "$t.editedAt",
+		"media":          "$t.media"}}
 
-	basePipeline := []bson.M{matchId, lookupUsers, projectResult, unwind, matchName}
+	basePipeline := []bson.M{matchId, projectTweets, unwindTweets, filterTweets}
 	countPipeline := append(basePipeline, count)
-	aggPipeline := append(basePipeline, sort, skip, agLimit, projectUser)
+	aggPipeline := append(basePipeline, sort, skip, agLimit, projectResult)
 
 	// endregion
 
-	dbResults, total, err := getResults[m.User](db, "users", countPipeline, aggPipeline)
+	dbResults, total, err := getResults[m.Tweet](db, "users", countPipeline, aggPipeline)
 
 	if err == nil {
-		for _, userModel := range dbResults {
-			userRequest := getUserRequest(*userModel)
-			results = append(results, &userRequest)
+		for _, tweetModel := range dbResults {
+			tweetRequest := getTweetRequest(*tweetModel)
+			results = append(results, &tweetRequest)
+
+			tweetRequest.UserId = ""
 		}
 	}
 
 	return results, total, err
 }
 
-/* GetNotFollowing gets an user's not following list */
-func (db *DbNoSqlV2) GetNotFollowing(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
-	var results []*mr.User
+/* GetUserTweets gets all the user's tweets, including the deleted ones */
+func (db *DbNoSqlV2) GetUserTweets(id string) ([]*mr.Tweet, error) {
+	var results []*mr.Tweet
 
-	objId, _ := getObjectId(id)
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return results, err
+	}
 
 	// region Pipeline
 
 	matchId := bson.M{"$match": bson.M{"_id": objId}}
-	lookupUsers := bson.M{"$lookup": bson.M{
-		"from": "users",
-		"as":   "r",
-		"let":  bson.M{"userId": "$following"},
-		"pipeline": [1]bson.M{{
-			"$match": bson.M{
-				"$expr": bson.M{
-					"$not": bson.M{"$in": [2]string{
-						"$_id",
-						"$$userId",
-					}},
-				}},
-		}},
-	}}
-	projectArray := bson.M{"$project": bson.M{"u": "$r", "_id": 0}}
-	unwind := bson.M{"$unwind": bson.M{
-		"path":                       "$u",
-		"preserveNullAndEmptyArrays": false,
-	}}
-	matchName := bson.M{"$match": bson.M{
-		"u._id":  bson.M{"$ne": objId},
-		"u.name": bson.M{"$regex": search, "$options": "im"},
-	}}
+	projectTweets := bson.M{"$project": bson.M{
+		"t":   "$tweets",
+		"_id": 0}}
+	unwindTweets := bson.M{"$unwind": bson.M{
+		"path":                       "$t",
+		"preserveNullAndEmptyArrays": false}}
 
 	count := bson.M{"$count": "total"}
 
-	sort := bson.M{"$sort": bson.M{"u.birthDate": -1}}
-	skip := bson.M{"$skip": (page - 1) * limit}
-	agLimit := bson.M{"$limit": limit}
-	projectUser := bson.M{"$project": bson.M{
-		"_id":       "$u._id",
-		"name":      "$u.name",
-		"lastName":  "$u.lastName",
-		"birthDate": "$u.birthDate"}}
-
-	basePipeline := []bson.M{matchId, lookupUsers, projectArray, unwind, matchName}
+	sort := bson.M{"$sort": bson.M{"t.date": -1}}
+	projectResult := bson.M{"$project": bson.M{
+		"_id":            "$t._id",
+		"message":        "$t.message",
+		"date":           "$t.date",
+		"active":         "$t.active",
+		"inReplyTo":      "$t.inReplyTo",
+		"conversationId": "$t.conversationId",
+		"replyCount":     "$t.replyCount",
+		"retweetOf":      "$t.retweetOf",
+		"quotedTweetId":  "$t.quotedTweetId",
+		"retweetCount":   "$t.retweetCount",
+		"quoteCount":     "$t.quoteCount",
+		"likeCount":      "$t.likeCount",
+		"editedAt":       "$t.editedAt",
+		"media":          "$t.media"}}
+
+	basePipeline := []bson.M{matchId, projectTweets, unwindTweets}
 	countPipeline := append(basePipeline, count)
-	aggPipeline := append(basePipeline, sort, skip, agLimit, projectUser)
+	aggPipeline := append(basePipeline, sort, projectResult)
 
 	// endregion
 
-	dbResults, total, err := getResults[m.User](db, "users", countPipeline, aggPipeline)
+	dbResults, _, err := getResults[m.Tweet](db, "users", countPipeline, aggPipeline)
 
 	if err == nil {
-		for _, userModel := range dbResults {
-			userRequest := getUserRequest(*userModel)
-			results = append(results, &userRequest)
+		for _, tweetModel := range dbResults {
+			tweetRequest := getTweetRequest(*tweetModel)
+			tweetRequest.UserId = id
+			results = append(results, &tweetRequest)
 		}
 	}
 
-	return results, total, err
+	return results, err
 }
 
-/* GetFollowingTweets returns the following's tweets */
-func (db *DbNoSqlV2) GetFollowingTweets(id string, page int64, limit int64, isOnlyTweets bool) (any, int64, error) {
-	var results any
-	var total int64
-	var err error
+/* InsertTweet inserts a tweet in the DB */
+func (db *DbNoSqlV2) InsertTweet(tweet mr.Tweet) (string, error) {
+	tweetModel, err := getTweetModel(tweet)
+
+	if err != nil {
+		return "", err
+	}
+
+	id, _, err := db.insertTweet(tweetModel, bson.M{"_id": tweetModel.UserId}, nil)
+
+	return id, err
+}
+
+/* InsertRetweet inserts a retweet in the DB, it returns false if the user had already retweeted the tweet */
+func (db *DbNoSqlV2) InsertRetweet(tweet mr.Tweet) (string, bool, error) {
+	tweetModel, err := getTweetModel(tweet)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	// The tweets are embedded, so the user is only matched if there is not an active retweet of the tweet
+	filter := bson.M{
+		"_id": tweetModel.UserId,
+		"tweets": bson.M{"$not": bson.M{"$elemMatch": bson.M{
+			"retweetOf": tweetModel.RetweetOf,
+			"active":    true,
+		}}},
+	}
+
+	return db.insertTweet(tweetModel, filter, nil)
+}
+
+/* DeleteRetweet deletes the user's retweet of a tweet, it returns false if there was not any */
+func (db *DbNoSqlV2) DeleteRetweet(userId string, tweetId string) (bool, error) {
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return false, err
+	}
+
+	objTweetId, err := getObjectId(tweetId)
+
+	if err != nil {
+		return false, err
+	}
+
+	condition := bson.M{
+		"_id":              objUserId,
+		"tweets.retweetOf": objTweetId,
+		"tweets.active":    true,
+	}
+
+	results, _, err := db.findTweets(condition, 0, 0, false)
+
+	if err != nil || len(results) < 1 {
+		return false, err
+	}
+
+	err = db.deleteTweetLogical(results[0].Id, userId)
+
+	return err == nil, err
+}
+
+/* GetTweet gets a tweet, including a deleted one */
+func (db *DbNoSqlV2) GetTweet(id string) (mr.Tweet, bool, error) {
+	var tweetRequest mr.Tweet
 
 	objId, err := getObjectId(id)
 
 	if err != nil {
-		return results, 0, err
+		return tweetRequest, false, err
+	}
+
+	results, _, err := db.findTweets(bson.M{"tweets._id": objId}, 0, 0, false)
+
+	if err != nil || len(results) < 1 {
+		return tweetRequest, false, err
+	}
+
+	return *results[0], true, nil
+}
+
+/* GetReplies gets the direct replies to a tweet */
+func (db *DbNoSqlV2) GetReplies(id string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	condition := bson.M{
+		"tweets.inReplyTo": objId,
+		"tweets.active":    true,
+	}
+
+	return db.findTweets(condition, page, limit, false)
+}
+
+/* GetConversation gets all the tweets of a conversation, including the deleted ones, ordered by date */
+func (db *DbNoSqlV2) GetConversation(id string) ([]*mr.Tweet, error) {
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	condition := bson.M{
+		"$or": []bson.M{
+			{"tweets._id": objId},
+			{"tweets.conversationId": objId},
+		},
+	}
+
+	results, _, err := db.findTweets(condition, 0, 0, false)
+
+	return results, err
+}
+
+/* ModifyTweet changes the message of an user's tweet keeping the previous one as a revision, it returns false if the tweet is not found */
+func (db *DbNoSqlV2) ModifyTweet(tweet mr.Tweet) (bool, error) {
+	current, isFound, err := db.GetTweet(tweet.Id)
+
+	if err != nil || !isFound || !current.Active || current.UserId != tweet.UserId {
+		return false, err
+	}
+
+	currentModel, err := getTweetModel(current)
+
+	if err != nil {
+		return false, err
+	}
+
+	// The tweet is only updated if it has not been edited since it was read, so no revision is lost
+	filter := bson.M{
+		"_id": currentModel.UserId,
+		"tweets": bson.M{"$elemMatch": bson.M{
+			"_id":      currentModel.Id,
+			"active":   true,
+			"editedAt": currentModel.EditedAt,
+		}},
+	}
+	mentions, err := db.getMentionedIds(tweet.Message, currentModel.UserId)
+
+	if err != nil {
+		return false, err
+	}
+
+	hashtags := helpers.GetHashtags(tweet.Message)
+	update := bson.M{
+		"$set": bson.M{
+			"tweets.$.message":  tweet.Message,
+			"tweets.$.editedAt": tweet.EditedAt,
+			"tweets.$.hashtags": hashtags,
+			"tweets.$.mentions": mentions,
+		},
+	}
+
+	usersCol := getCollection(db, "twitton", "users")
+	revisionsCol := getCollection(db, "twitton", "tweetRevisions")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := usersCol.UpdateOne(sessCtx, filter, update)
+
+		if err != nil || result.MatchedCount < 1 {
+			return result, err
+		}
+
+		_, err = revisionsCol.InsertOne(sessCtx, getTweetRevisionModel(currentModel))
+
+		// The hashtags of the previous message stop counting in the trends
+		if err == nil {
+			err = db.updateHashtagCounts(sessCtx, helpers.GetHashtags(current.Message), current.Date, -1)
+		}
+
+		if err == nil {
+			err = db.updateHashtagCounts(sessCtx, hashtags, current.Date, 1)
+		}
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return false, err
+	}
+
+	return res.(*mongo.UpdateResult).MatchedCount > 0, nil
+}
+
+/* GetTweetRevisions gets the previous messages of an edited tweet, the oldest first */
+func (db *DbNoSqlV2) GetTweetRevisions(id string) ([]*mr.TweetRevision, error) {
+	var results []*mr.TweetRevision
+	var revisionsDbResults []*m.TweetRevision
+
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return results, err
+	}
+
+	col := getCollection(db, "twitton", "tweetRevisions")
+	opts := options.Find().SetSort(bson.M{"date": 1})
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, bson.M{"tweetId": objId}, opts)
+
+	if err != nil {
+		return results, err
+	}
+
+	ctxCursor := context.TODO()
+
+	defer cursor.Close(ctxCursor)
+
+	err = cursor.All(ctxCursor, &revisionsDbResults)
+
+	if err != nil {
+		return results, err
+	}
+
+	for _, revisionModel := range revisionsDbResults {
+		revisionRequest := getTweetRevisionRequest(*revisionModel)
+		results = append(results, &revisionRequest)
+	}
+
+	return results, nil
+}
+
+/* GetHashtagTweets gets the tweets with a hashtag, the newest first */
+func (db *DbNoSqlV2) GetHashtagTweets(tag string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	condition := bson.M{
+		"tweets.hashtags": tag,
+		"tweets.active":   true,
+	}
+
+	return db.findTweets(condition, page, limit, true)
+}
+
+/* GetMentionTweets gets the tweets that mention an user, the newest first */
+func (db *DbNoSqlV2) GetMentionTweets(userId string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	condition := bson.M{
+		"tweets.mentions": objUserId,
+		"tweets.active":   true,
+	}
+
+	return db.findTweets(condition, page, limit, true)
+}
+
+/* SearchTweets gets the tweets with any of the terms as whole words, by relevance or the newest first. Unlike the other DBs, the words are not stemmed */
+func (db *DbNoSqlV2) SearchTweets(terms []string, isNewestFirst bool, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	var matches []any
+
+	// The text index finds the users with matching tweets, the score of each tweet is the number of terms it contains as whole words.
+	// The index covers all the embedded tweets of an user, so each tweet is checked again without stemming: "running" does not match "runs"
+	for _, term := range terms {
+		regex := `(?<![\p{L}\p{Nd}])` + regexp.QuoteMeta(term) + `(?![\p{L}\p{Nd}])`
+
+		matches = append(matches, bson.M{"$cond": bson.A{
+			bson.M{"$regexMatch": bson.M{"input": "$tweets.message", "regex": regex, "options": "i"}},
+			1,
+			0}})
+	}
+
+	matchUsers := bson.M{"$match": bson.M{
+		"$text":         bson.M{"$search": strings.Join(terms, " ")},
+		"tweets.active": true}}
+	unwindTweets := bson.M{"$unwind": bson.M{
+		"path":                       "$tweets",
+		"preserveNullAndEmptyArrays": false}}
+	setScore := bson.M{"$addFields": bson.M{"score": bson.M{"$add": matches}}}
+	matchTweets := bson.M{"$match": bson.M{
+		"tweets.active": true,
+		"score":         bson.M{"$gt": 0}}}
+	sort := bson.D{{Key: "tweets.date", Value: -1}}
+
+	if !isNewestFirst {
+		sort = append(bson.D{{Key: "score", Value: -1}}, sort...)
+	}
+
+	basePipeline := []bson.M{matchUsers, unwindTweets, setScore, matchTweets}
+
+	return db.findSortedTweets(basePipeline, page, limit, sort)
+}
+
+/* GetTrends gets the most used hashtags since a date, the counts are kept by time bucket when the tweets are written */
+func (db *DbNoSqlV2) GetTrends(since time.Time, limit int64) ([]*mr.Trend, error) {
+	var results []*mr.Trend
+	var countsDbResults []*m.HashtagCount
+
+	col := getCollection(db, "twitton", "hashtagCounts")
+	pipeline := []bson.M{
+		{"$match": bson.M{"bucket": bson.M{"$gte": helpers.GetTrendsBucket(since)}}},
+		{"$group": bson.M{"_id": "$tag", "count": bson.M{"$sum": "$count"}}},
+		{"$match": bson.M{"count": bson.M{"$gt": 0}}},
+		{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		{"$limit": limit},
+		{"$project": bson.M{"_id": 0, "tag": "$_id", "count": 1}},
+	}
+	ctxAgg, cancelAgg := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelAgg()
+
+	cursor, err := col.Aggregate(ctxAgg, pipeline)
+
+	if err != nil {
+		return results, err
+	}
+
+	ctxCursor := context.TODO()
+
+	defer cursor.Close(ctxCursor)
+
+	err = cursor.All(ctxCursor, &countsDbResults)
+
+	if err != nil {
+		return results, err
+	}
+
+	for _, countModel := range countsDbResults {
+		trendRequest := getTrendRequest(*countModel)
+		results = append(results, &trendRequest)
+	}
+
+	return results, nil
+}
+
+// endregion
+
+// region "Likes"
+
+/* InsertLike adds an user's like to a tweet, it returns false if the user had already liked it */
+func (db *DbNoSqlV2) InsertLike(like mr.Like) (bool, error) {
+	likeModel, err := getLikeModel(like)
+
+	if err != nil {
+		return false, err
+	}
+
+	likesCol := getCollection(db, "twitton", "likes")
+	usersCol := getCollection(db, "twitton", "users")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := likesCol.InsertOne(sessCtx, likeModel)
+
+		if err != nil {
+			return result, err
+		}
+
+		// The count is kept in the embedded tweet, so it is not counted each time the tweet is read
+		_, err = usersCol.UpdateOne(sessCtx,
+			bson.M{"tweets._id": likeModel.TweetId},
+			bson.M{"$inc": bson.M{"tweets.$.likeCount": 1}})
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	// The unique index only allows a like of a tweet by user
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+/* DeleteLike removes an user's like from a tweet, it returns false if the user had not liked it */
+func (db *DbNoSqlV2) DeleteLike(userId string, tweetId string) (bool, error) {
+	likeModel, err := getLikeModel(mr.Like{UserId: userId, TweetId: tweetId})
+
+	if err != nil {
+		return false, err
+	}
+
+	likesCol := getCollection(db, "twitton", "likes")
+	usersCol := getCollection(db, "twitton", "users")
+	filter := bson.M{
+		"userId":  likeModel.UserId,
+		"tweetId": likeModel.TweetId,
+	}
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := likesCol.DeleteOne(sessCtx, filter)
+
+		if err != nil || result.DeletedCount < 1 {
+			return result, err
+		}
+
+		_, err = usersCol.UpdateOne(sessCtx,
+			bson.M{"tweets._id": likeModel.TweetId},
+			bson.M{"$inc": bson.M{"tweets.$.likeCount": -1}})
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return false, err
+	}
+
+	return res.(*mongo.DeleteResult).DeletedCount > 0, nil
+}
+
+/* GetLikedTweetIds returns which of the tweets are liked by an user */
+func (db *DbNoSqlV2) GetLikedTweetIds(userId string, tweetIds []string) ([]string, error) {
+	var results []string
+	var likesDbResults []*m.Like
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return results, err
+	}
+
+	objTweetIds := make([]primitive.ObjectID, 0, len(tweetIds))
+
+	for _, tweetId := range tweetIds {
+		objTweetId, err := getObjectId(tweetId)
+
+		if err != nil {
+			return results, err
+		}
+
+		objTweetIds = append(objTweetIds, objTweetId)
+	}
+
+	col := getCollection(db, "twitton", "likes")
+	condition := bson.M{
+		"userId":  objUserId,
+		"tweetId": bson.M{"$in": objTweetIds},
+	}
+	opts := options.Find().SetProjection(bson.M{"tweetId": 1})
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, condition, opts)
+
+	if err != nil {
+		return results, err
+	}
+
+	ctxCursor := context.TODO()
+
+	defer cursor.Close(ctxCursor)
+
+	err = cursor.All(ctxCursor, &likesDbResults)
+
+	if err != nil {
+		return results, err
+	}
+
+	for _, likeModel := range likesDbResults {
+		results = append(results, likeModel.TweetId.Hex())
+	}
+
+	return results, nil
+}
+
+/* GetLikedTweets gets the tweets liked by an user, the last liked first */
+func (db *DbNoSqlV2) GetLikedTweets(userId string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	var results []*mr.Tweet
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return results, 0, err
+	}
+
+	// region Pipeline
+
+	// The tweets are embedded, so the author is looked up and its tweets are filtered by the liked one
+	matchUser := bson.M{"$match": bson.M{"userId": objUserId}}
+	lookupUsers := bson.M{"$lookup": bson.M{
+		"from":         "users",
+		"localField":   "tweetId",
+		"foreignField": "tweets._id",
+		"as":           "user"}}
+	unwindUsers := bson.M{"$unwind": bson.M{
+		"path":                       "$user",
+		"preserveNullAndEmptyArrays": false}}
+	unwindTweets := bson.M{"$unwind": bson.M{
+		"path":                       "$user.tweets",
+		"preserveNullAndEmptyArrays": false}}
+	matchTweets := bson.M{"$match": bson.M{
+		"$expr":              bson.M{"$eq": bson.A{"$user.tweets._id", "$tweetId"}},
+		"user.tweets.active": true}}
+
+	count := bson.M{"$count": "total"}
+
+	sort := bson.M{"$sort": bson.M{"date": -1}}
+	skip := bson.M{"$skip": (page - 1) * limit}
+	agLimit := bson.M{"$limit": limit}
+	projectResult := bson.M{"$project": bson.M{
+		"_id":            "$user.tweets._id",
+		"userId":         "$user._id",
+		"message":        "$user.tweets.message",
+		"date":           "$user.tweets.date",
+		"active":         "$user.tweets.active",
+		"inReplyTo":      "$user.tweets.inReplyTo",
+		"conversationId": "$user.tweets.conversationId",
+		"replyCount":     "$user.tweets.replyCount",
+		"retweetOf":      "$user.tweets.retweetOf",
+		"quotedTweetId":  "$user.tweets.quotedTweetId",
+		"retweetCount":   "$user.tweets.retweetCount",
+		"quoteCount":     "$user.tweets.quoteCount",
+		"likeCount":      "$user.tweets.likeCount",
+		"editedAt":       "$user.tweets.editedAt",
+		"media":          "$user.tweets.media"}}
+
+	basePipeline := []bson.M{matchUser, lookupUsers, unwindUsers, unwindTweets, matchTweets}
+	countPipeline := append(basePipeline, count)
+	aggPipeline := append(basePipeline, sort, skip, agLimit, projectResult)
+
+	// endregion
+
+	dbResults, total, err := getResults[m.Tweet](db, "likes", countPipeline, aggPipeline)
+
+	if err == nil {
+		for _, tweetModel := range dbResults {
+			tweetRequest := getTweetRequest(*tweetModel)
+			results = append(results, &tweetRequest)
+		}
+	}
+
+	return results, total, err
+}
+
+// endregion
+
+// region "Bookmarks"
+
+/* InsertBookmark saves a tweet in the user's bookmarks, it returns false if the user had already bookmarked it */
+func (db *DbNoSqlV2) InsertBookmark(bookmark mr.Bookmark) (bool, error) {
+	bookmarkModel, err := getBookmarkModel(bookmark)
+
+	if err != nil {
+		return false, err
+	}
+
+	col := getCollection(db, "twitton", "bookmarks")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.InsertOne(sessCtx, bookmarkModel)
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	// The unique index only allows a bookmark of a tweet by user
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+/* DeleteBookmark removes a tweet from the user's bookmarks, it returns false if the user had not bookmarked it */
+func (db *DbNoSqlV2) DeleteBookmark(userId string, tweetId string) (bool, error) {
+	bookmarkModel, err := getBookmarkModel(mr.Bookmark{UserId: userId, TweetId: tweetId})
+
+	if err != nil {
+		return false, err
+	}
+
+	col := getCollection(db, "twitton", "bookmarks")
+	filter := bson.M{
+		"userId":  bookmarkModel.UserId,
+		"tweetId": bookmarkModel.TweetId,
+	}
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.DeleteOne(sessCtx, filter)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return false, err
+	}
+
+	return res.(*mongo.DeleteResult).DeletedCount > 0, nil
+}
+
+/* GetBookmarkedTweets gets the tweets bookmarked by an user, the last bookmarked first. The tweets deleted after being bookmarked are returned as inactive */
+func (db *DbNoSqlV2) GetBookmarkedTweets(userId string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	var results []*mr.Tweet
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return results, 0, err
+	}
+
+	// region Pipeline
+
+	matchUser := bson.M{"$match": bson.M{"userId": objUserId}}
+
+	count := bson.M{"$count": "total"}
+
+	sort := bson.M{"$sort": bson.M{"date": -1}}
+	skip := bson.M{"$skip": (page - 1) * limit}
+	agLimit := bson.M{"$limit": limit}
+	// The tweets are embedded, so the author is looked up and its tweets are filtered by the bookmarked one
+	lookupUsers := bson.M{"$lookup": bson.M{
+		"from":         "users",
+		"localField":   "tweetId",
+		"foreignField": "tweets._id",
+		"as":           "user"}}
+	unwindUsers := bson.M{"$unwind": bson.M{
+		"path":                       "$user",
+		"preserveNullAndEmptyArrays": true}}
+	unwindTweets := bson.M{"$unwind": bson.M{
+		"path":                       "$user.tweets",
+		"preserveNullAndEmptyArrays": true}}
+	// The tweets removed with their author are kept as inactive ones, so they are not silently lost
+	matchTweets := bson.M{"$match": bson.M{"$expr": bson.M{"$or": bson.A{
+		bson.M{"$eq": bson.A{bson.M{"$type": "$user"}, "missing"}},
+		bson.M{"$eq": bson.A{"$user.tweets._id", "$tweetId"}}}}}}
+	projectResult := bson.M{"$project": bson.M{
+		"_id":            "$tweetId",
+		"userId":         "$user._id",
+		"message":        "$user.tweets.message",
+		"date":           "$user.tweets.date",
+		"active":         "$user.tweets.active",
+		"inReplyTo":      "$user.tweets.inReplyTo",
+		"conversationId": "$user.tweets.conversationId",
+		"replyCount":     "$user.tweets.replyCount",
+		"retweetOf":      "$user.tweets.retweetOf",
+		"quotedTweetId":  "$user.tweets.quotedTweetId",
+		"retweetCount":   "$user.tweets.retweetCount",
+		"quoteCount":     "$user.tweets.quoteCount",
+		"likeCount":      "$user.tweets.likeCount",
+		"editedAt":       "$user.tweets.editedAt",
+		"media":          "$user.tweets.media"}}
+
+	countPipeline := []bson.M{matchUser, count}
+	aggPipeline := []bson.M{matchUser, sort, skip, agLimit, lookupUsers, unwindUsers, unwindTweets, matchTweets, projectResult}
+
+	// endregion
+
+	dbResults, total, err := getResults[m.Tweet](db, "bookmarks", countPipeline, aggPipeline)
+
+	if err == nil {
+		for _, tweetModel := range dbResults {
+			tweetRequest := getTweetRequest(*tweetModel)
+			results = append(results, &tweetRequest)
+		}
+	}
+
+	return results, total, err
+}
+
+// endregion
+
+// region "Scheduled tweets"
+
+/* InsertScheduledTweet inserts a tweet to be published later in the DB */
+func (db *DbNoSqlV2) InsertScheduledTweet(tweet mr.ScheduledTweet) (string, error) {
+	scheduledModel, err := getScheduledTweetModel(tweet)
+
+	if err != nil {
+		return "", err
+	}
+
+	col := getCollection(db, "twitton", "scheduledTweets")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.InsertOne(sessCtx, scheduledModel)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return "", err
+	}
+
+	result := res.(*mongo.InsertOneResult)
+	objID, _ := result.InsertedID.(primitive.ObjectID)
+
+	return objID.Hex(), nil
+}
+
+/* GetScheduledTweets gets the tweets scheduled by an user, the next to be published first */
+func (db *DbNoSqlV2) GetScheduledTweets(userId string, page int64, limit int64) ([]*mr.ScheduledTweet, int64, error) {
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	condition := bson.M{"userId": objUserId}
+
+	return db.findScheduledTweets(condition, page, limit)
+}
+
+/* GetDueScheduledTweets gets the scheduled tweets whose publication date has been reached, the oldest first */
+func (db *DbNoSqlV2) GetDueScheduledTweets(before time.Time, limit int64) ([]*mr.ScheduledTweet, error) {
+	condition := bson.M{"publishAt": bson.M{"$lte": before}}
+	results, _, err := db.findScheduledTweets(condition, 1, limit)
+
+	return results, err
+}
+
+/* DeleteScheduledTweet deletes a tweet scheduled by an user, it returns the deleted tweet */
+func (db *DbNoSqlV2) DeleteScheduledTweet(id string, userId string) (mr.ScheduledTweet, bool, error) {
+	var scheduledModel m.ScheduledTweet
+	var scheduledRequest mr.ScheduledTweet
+
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return scheduledRequest, false, err
+	}
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return scheduledRequest, false, err
+	}
+
+	col := getCollection(db, "twitton", "scheduledTweets")
+	filter := bson.M{
+		"_id":    objId,
+		"userId": objUserId,
+	}
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err = col.FindOneAndDelete(ctx, filter).Decode(&scheduledModel)
+
+	if err == mongo.ErrNoDocuments {
+		return scheduledRequest, false, nil
+	} else if err != nil {
+		return scheduledRequest, false, err
+	}
+
+	scheduledRequest = getScheduledTweetRequest(scheduledModel)
+
+	return scheduledRequest, true, nil
+}
+
+/* PublishScheduledTweet inserts the tweet of a scheduled one and deletes it, it returns false if it was already published or cancelled */
+func (db *DbNoSqlV2) PublishScheduledTweet(id string, tweet mr.Tweet) (string, bool, error) {
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	tweetModel, err := getTweetModel(tweet)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	col := getCollection(db, "twitton", "scheduledTweets")
+	claim := func(sessCtx mongo.SessionContext) (bool, error) {
+		result, err := col.DeleteOne(sessCtx, bson.M{"_id": objId})
+
+		if err != nil {
+			return false, err
+		}
+
+		return result.DeletedCount > 0, nil
+	}
+
+	return db.insertTweet(tweetModel, bson.M{"_id": tweetModel.UserId}, claim)
+}
+
+// endregion
+
+// region "Drafts"
+
+/* InsertDraft inserts a draft of a tweet in the DB */
+func (db *DbNoSqlV2) InsertDraft(draft mr.Draft) (string, error) {
+	draftModel, err := getDraftModel(draft)
+
+	if err != nil {
+		return "", err
+	}
+
+	col := getCollection(db, "twitton", "drafts")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.InsertOne(sessCtx, draftModel)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return "", err
+	}
+
+	result := res.(*mongo.InsertOneResult)
+	objID, _ := result.InsertedID.(primitive.ObjectID)
+
+	return objID.Hex(), nil
+}
+
+/* GetDrafts gets the drafts of an user, the last saved first */
+func (db *DbNoSqlV2) GetDrafts(userId string, page int64, limit int64) ([]*mr.Draft, int64, error) {
+	var results []*mr.Draft
+	var draftsDbResults []*m.Draft
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return results, 0, err
+	}
+
+	col := getCollection(db, "twitton", "drafts")
+	condition := bson.M{"userId": objUserId}
+	opts := options.Find()
+
+	opts.SetSort(bson.D{{Key: "date", Value: -1}})
+
+	ctxCount, cancelCount := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelCount()
+
+	total, err := col.CountDocuments(ctxCount, condition)
+
+	if err != nil {
+		return results, total, err
+	}
+
+	// Without a limit all the drafts are returned
+	if limit > 0 {
+		opts.SetSkip((page - 1) * limit)
+		opts.SetLimit(limit)
+	}
+
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, condition, opts)
+
+	if err != nil {
+		return results, total, err
+	}
+
+	ctxCursor := context.TODO()
+
+	defer cursor.Close(ctxCursor)
+
+	err = cursor.All(ctxCursor, &draftsDbResults)
+
+	if err != nil {
+		return results, total, err
+	}
+
+	for _, draftModel := range draftsDbResults {
+		draftRequest := getDraftRequest(*draftModel)
+		results = append(results, &draftRequest)
+	}
+
+	return results, total, nil
+}
+
+/* GetDraft gets a draft of an user */
+func (db *DbNoSqlV2) GetDraft(id string, userId string) (mr.Draft, bool, error) {
+	var draftModel m.Draft
+	var draftRequest mr.Draft
+
+	filter, err := getDraftFilter(id, userId)
+
+	if err != nil {
+		return draftRequest, false, err
+	}
+
+	col := getCollection(db, "twitton", "drafts")
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err = col.FindOne(ctx, filter).Decode(&draftModel)
+
+	if err == mongo.ErrNoDocuments {
+		return draftRequest, false, nil
+	} else if err != nil {
+		return draftRequest, false, err
+	}
+
+	draftRequest = getDraftRequest(draftModel)
+
+	return draftRequest, true, nil
+}
+
+/* ModifyDraft replaces the content of a draft of an user, it returns false if the draft does not exist */
+func (db *DbNoSqlV2) ModifyDraft(draft mr.Draft) (bool, error) {
+	draftModel, err := getDraftModel(draft)
+
+	if err != nil {
+		return false, err
+	}
+
+	col := getCollection(db, "twitton", "drafts")
+	filter := bson.M{
+		"_id":    draftModel.Id,
+		"userId": draftModel.UserId,
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.ReplaceOne(sessCtx, filter, draftModel)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return false, err
+	}
+
+	result := res.(*mongo.UpdateResult)
+
+	return result.MatchedCount > 0, nil
+}
+
+/* DeleteDraft deletes a draft of an user, it returns the deleted draft */
+func (db *DbNoSqlV2) DeleteDraft(id string, userId string) (mr.Draft, bool, error) {
+	var draftModel m.Draft
+	var draftRequest mr.Draft
+
+	filter, err := getDraftFilter(id, userId)
+
+	if err != nil {
+		return draftRequest, false, err
+	}
+
+	col := getCollection(db, "twitton", "drafts")
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err = col.FindOneAndDelete(ctx, filter).Decode(&draftModel)
+
+	if err == mongo.ErrNoDocuments {
+		return draftRequest, false, nil
+	} else if err != nil {
+		return draftRequest, false, err
+	}
+
+	draftRequest = getDraftRequest(draftModel)
+
+	return draftRequest, true, nil
+}
+
+/* PublishDraft inserts the tweet of a draft and deletes it, it returns false if the draft does not exist anymore */
+func (db *DbNoSqlV2) PublishDraft(id string, userId string, tweet mr.Tweet) (string, bool, error) {
+	filter, err := getDraftFilter(id, userId)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	tweetModel, err := getTweetModel(tweet)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	col := getCollection(db, "twitton", "drafts")
+	claim := func(sessCtx mongo.SessionContext) (bool, error) {
+		result, err := col.DeleteOne(sessCtx, filter)
+
+		if err != nil {
+			return false, err
+		}
+
+		return result.DeletedCount > 0, nil
+	}
+
+	return db.insertTweet(tweetModel, bson.M{"_id": tweetModel.UserId}, claim)
+}
+
+// endregion
+
+// region "Relations"
+
+/* IsRelation obtains a relation from the DB if exist */
+func (db *DbNoSqlV2) IsRelation(relation mr.Relation) (bool, mr.Relation, error) {
+	objId, _ := getObjectId(relation.UserId)
+	objUserFollowingId, err := getObjectId(relation.UserRelationId)
+
+	if err != nil {
+		return false, relation, err
+	}
+
+	col := getCollection(db, "twitton", "users")
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	filter := bson.M{
+		"_id": objId,
+		"following": bson.M{
+			"$in": [1]primitive.ObjectID{
+				objUserFollowingId,
+			}},
+	}
+
+	err = col.FindOne(ctx, filter).Err()
+
+	if err != nil && err == mongo.ErrNoDocuments {
+		return false, relation, nil
+	} else if err != nil && err != mongo.ErrNoDocuments {
+		return false, relation, err
+	}
+
+	relation.Active = true
+
+	return true, relation, nil
+}
+
+/* InsertRelation creates a relation into the DB */
+func (db *DbNoSqlV2) InsertRelation(relation mr.Relation) error {
+	_, isFound, err := db.GetProfile(relation.UserRelationId)
+
+	if err != nil {
+		return err
+	}
+
+	if !isFound {
+		return errors.New("no registry found in the DB")
+	}
+
+	objId, _ := getObjectId(relation.UserId)
+	objUserFollowingId, _ := getObjectId(relation.UserRelationId)
+
+	update := bson.M{
+		"$addToSet": bson.M{
+			"following": objUserFollowingId,
+		},
+	}
+
+	col := getCollection(db, "twitton", "users")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateByID(sessCtx, objId, update)
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	return err
+}
+
+/* DeleteRelation deletes a relation in the DB */
+func (db *DbNoSqlV2) DeleteRelation(relation mr.Relation) error {
+	err := db.deleteRelationFisical(relation)
+
+	return err
+}
+
+/* GetUsers gets a list of users */
+func (db *DbNoSqlV2) GetUsers(id string, page int64, limit int64, search string, searchType string) ([]*mr.User, int64, error) {
+	var results []*mr.User
+	var total int64
+
+	col := getCollection(db, "twitton", "users")
+
+	query := bson.M{
+		"name": bson.M{"$regex": search, "$options": "im"},
+	}
+
+	findOpts := options.Find()
+
+	findOpts.SetSort(bson.D{{Key: "birthDate", Value: -1}})
+	findOpts.SetSkip((page - 1) * limit)
+	findOpts.SetLimit(limit)
+
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, query, findOpts)
+
+	if err != nil {
+		return results, total, err
+	}
+
+	ctxCursor := context.TODO()
+
+	defer cursor.Close(ctxCursor)
+
+	var include, isRelation bool
+
+	for cursor.Next(ctxCursor) {
+		var result m.User
+
+		isRelation = false
+		include = false
+
+		err := cursor.Decode(&result)
+
+		if err != nil {
+			return results, total, err
+		}
+
+		userRequest := getUserRequest(result)
+
+		relationRequest := mr.Relation{
+			UserId:         id,
+			UserRelationId: userRequest.Id,
+			Active:         true,
+		}
+
+		isFound, relationDb, err := db.IsRelation(relationRequest)
+
+		if err != nil {
+			return results, total, err
+		}
+
+		if isFound {
+			isRelation = relationDb.Active
+		}
+
+		if relationRequest.UserRelationId == id {
+			include = false
+		} else if (searchType == "new" && !isRelation) || (searchType == "follow" && isRelation) {
+			include = true
+		}
+
+		if include {
+			userRequest.Email = ""
+			userRequest.Password = ""
+			userRequest.Avatar = ""
+			userRequest.Banner = ""
+			userRequest.Biography = ""
+			userRequest.Location = ""
+			userRequest.WebSite = ""
+
+			results = append(results, &userRequest)
+		}
+	}
+
+	err = cursor.Err()
+
+	if err != nil {
+		fmt.Println(err.Error())
+
+		return results, total, err
+	}
+
+	// This total only reflects the total returned registries
+	total = int64(len(results))
+
+	return results, total, nil
+}
+
+/* GetFollowing gets an user's following list */
+func (db *DbNoSqlV2) GetFollowing(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
+	var results []*mr.User
+
+	objId, _ := getObjectId(id)
+
+	// region Pipeline
+
+	matchId := bson.M{"$match": bson.M{"_id": objId}}
+	lookupUsers := bson.M{"$lookup": bson.M{
+		"from":         "users",
+		"localField":   "following",
+		"foreignField": "_id",
+		"as":           "r"}}
+	projectResult := bson.M{"$project": bson.M{
+		"u":   "$r",
+		"_id": 0}}
+	unwind := bson.M{"$unwind": bson.M{
+		"path":                       "$u",
+		"preserveNullAndEmptyArrays": false}}
+	matchName := bson.M{"$match": bson.M{"u.name": bson.M{"$regex": search, "$options": "im"}}}
+
+	count := bson.M{"$count": "total"}
+
+	sort := bson.M{"$sort": bson.M{"u.birthDate": -1}}
+	skip := bson.M{"$skip": (page - 1) * limit}
+	agLimit := bson.M{"$limit": limit}
+	projectUser := bson.M{"$project": bson.M{
+		"_id":       "$u._id",
+		"name":      "$u.name",
+		"lastName":  "$u.lastName",
+		"birthDate": "$u.birthDate"}}
+
+	basePipeline := []bson.M{matchId, lookupUsers, projectResult, unwind, matchName}
+	countPipeline := append(basePipeline, count)
+	aggPipeline := append(basePipeline, sort, skip, agLimit, projectUser)
+
+	// endregion
+
+	dbResults, total, err := getResults[m.User](db, "users", countPipeline, aggPipeline)
+
+	if err == nil {
+		for _, userModel := range dbResults {
+			userRequest := getUserRequest(*userModel)
+			results = append(results, &userRequest)
+		}
+	}
+
+	return results, total, err
+}
+
+/* GetFollowers gets an user's followers list */
+func (db *DbNoSqlV2) GetFollowers(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
+	var results []*mr.User
+
+	objId, _ := getObjectId(id)
+
+	// region Pipeline
+
+	matchFollowing := bson.M{"$match": bson.M{"following": objId}}
+	matchName := bson.M{"$match": bson.M{"name": bson.M{"$regex": search, "$options": "im"}}}
+
+	count := bson.M{"$count": "total"}
+
+	sort := bson.M{"$sort": bson.M{"birthDate": -1}}
+	skip := bson.M{"$skip": (page - 1) * limit}
+	agLimit := bson.M{"$limit": limit}
+	projectUser := bson.M{"$project": bson.M{
+		"_id":       1,
+		"name":      1,
+		"lastName":  1,
+		"birthDate": 1}}
+
+	basePipeline := []bson.M{matchFollowing, matchName}
+	countPipeline := append(basePipeline, count)
+	aggPipeline := append(basePipeline, sort, skip, agLimit, projectUser)
+
+	// endregion
+
+	dbResults, total, err := getResults[m.User](db, "users", countPipeline, aggPipeline)
+
+	if err == nil {
+		for _, userModel := range dbResults {
+			userRequest := getUserRequest(*userModel)
+			results = append(results, &userRequest)
+		}
+	}
+
+	return results, total, err
+}
+
+/* GetNotFollowing gets an user's not following list */
+func (db *DbNoSqlV2) GetNotFollowing(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
+	var results []*mr.User
+
+	objId, _ := getObjectId(id)
+
+	// region Pipeline
+
+	matchId := bson.M{"$match": bson.M{"_id": objId}}
+	lookupUsers := bson.M{"$lookup": bson.M{
+		"from": "users",
+		"as":   "r",
+		"let":  bson.M{"userId": "$following"},
+		"pipeline": [1]bson.M{{
+			"$match": bson.M{
+				"$expr": bson.M{
+					"$not": bson.M{"$in": [2]string{
+						"$_id",
+						"$$userId",
+					}},
+				}},
+		}},
+	}}
+	projectArray := bson.M{"$project": bson.M{"u": "$r", "_id": 0}}
+	unwind := bson.M{"$unwind": bson.M{
+		"path":                       "$u",
+		"preserveNullAndEmptyArrays": false,
+	}}
+	matchName := bson.M{"$match": bson.M{
+		"u._id":  bson.M{"$ne": objId},
+		"u.name": bson.M{"$regex": search, "$options": "im"},
+	}}
+
+	count := bson.M{"$count": "total"}
+
+	sort := bson.M{"$sort": bson.M{"u.birthDate": -1}}
+	skip := bson.M{"$skip": (page - 1) * limit}
+	agLimit := bson.M{"$limit": limit}
+	projectUser := bson.M{"$project": bson.M{
+		"_id":       "$u._id",
+		"name":      "$u.name",
+		"lastName":  "$u.lastName",
+		"birthDate": "$u.birthDate"}}
+
+	basePipeline := []bson.M{matchId, lookupUsers, projectArray, unwind, matchName}
+	countPipeline := append(basePipeline, count)
+	aggPipeline := append(basePipeline, sort, skip, agLimit, projectUser)
+
+	// endregion
+
+	dbResults, total, err := getResults[m.User](db, "users", countPipeline, aggPipeline)
+
+	if err == nil {
+		for _, userModel := range dbResults {
+			userRequest := getUserRequest(*userModel)
+			results = append(results, &userRequest)
+		}
+	}
+
+	return results, total, err
+}
+
+/* GetFollowingTweets returns the following's tweets */
+func (db *DbNoSqlV2) GetFollowingTweets(id string, page int64, limit int64, isOnlyTweets bool) (any, int64, error) {
+	var results any
+	var total int64
+	var err error
+
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return results, 0, err
+	}
+
+	conditions := make([]bson.M, 0)
+	conditionsCount := make([]bson.M, 0)
+	conditionsAgg := make([]bson.M, 0)
+
+	skip := (page - 1) * limit
+
+	conditions = append(conditions, bson.M{"$match": bson.M{"_id": objId}})
+	conditions = append(conditions, bson.M{
+		"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "following",
+			"foreignField": "_id",
+			"as":           "r",
+		}})
+	conditions = append(conditions, bson.M{
+		"$unwind": bson.M{
+			"path":                       "$r",
+			"preserveNullAndEmptyArrays": false,
+		},
+	})
+	conditions = append(conditions, bson.M{
+		"$project": bson.M{
+			"_id":             0,
+			"userId":          "$_id",
+			"userFollowingId": "$r._id",
+			"tweet": bson.M{
+				"$filter": bson.M{
+					"input": "$r.tweets",
+					"as":    "tweet",
+					"cond":  bson.M{"$eq": [2]any{"$$tweet.active", true}},
+				},
+			},
+		},
+	})
+	conditions = append(conditions, bson.M{
+		"$unwind": bson.M{
+			"path":                       "$tweet",
+			"preserveNullAndEmptyArrays": false,
+		},
+	})
+
+	conditionsCount = append(conditionsCount, conditions...)
+	conditionsCount = append(conditionsCount, bson.M{"$count": "total"})
+
+	conditionsAgg = append(conditionsAgg, conditions...)
+	conditionsAgg = append(conditionsAgg, bson.M{"$sort": bson.M{"tweet.date": -1}})
+	conditionsAgg = append(conditionsAgg, bson.M{"$skip": skip})
+	conditionsAgg = append(conditionsAgg, bson.M{"$limit": limit})
+
+	if isOnlyTweets {
+		conditionsAgg = append(conditionsAgg, bson.M{
+			"$project": bson.M{
+				"_id":            "$tweet._id",
+				"userId":         "$userFollowingId",
+				"message":        "$tweet.message",
+				"date":           "$tweet.date",
+				"inReplyTo":      "$tweet.inReplyTo",
+				"conversationId": "$tweet.conversationId",
+				"replyCount":     "$tweet.replyCount",
+				"retweetOf":      "$tweet.retweetOf",
+				"quotedTweetId":  "$tweet.quotedTweetId",
+				"retweetCount":   "$tweet.retweetCount",
+				"quoteCount":     "$tweet.quoteCount",
+				"likeCount":      "$tweet.likeCount",
+				"editedAt":       "$tweet.editedAt",
+				"media":          "$tweet.media",
+			}})
+
+		var dbResults []*m.Tweet
+		var reqResults []*mr.Tweet
+
+		dbResults, total, err = getResults[m.Tweet](db, "users", conditionsCount, conditionsAgg)
+
+		if err == nil {
+			for _, tweetModel := range dbResults {
+				tweetRequest := getTweetRequest(*tweetModel)
+				reqResults = append(reqResults, &tweetRequest)
+			}
+
+			results = reqResults
+		}
+	} else {
+		var dbResults []*m.UserTweet
+		var reqResults []*mr.UserTweet
+
+		dbResults, total, err = getResults[m.UserTweet](db, "users", conditionsCount, conditionsAgg)
+
+		if err == nil {
+			for _, userTweetModel := range dbResults {
+				userTweetRequest := getUserTweetRequest(*userTweetModel)
+				reqResults = append(reqResults, &userTweetRequest)
+			}
+
+			results = reqResults
+		}
+	}
+
+	return results, total, err
+}
+
+// endregion
+
+// region "Tokens"
+
+/* RevokeToken adds a token to the revocation list in the DB, it returns false if the token had already been revoked */
+func (db *DbNoSqlV2) RevokeToken(token mr.RevokedToken) (bool, error) {
+	tokenModel, err := getRevokedTokenModel(token)
+
+	if err != nil {
+		return false, err
+	}
+
+	col := getCollection(db, "twitton", "revokedTokens")
+	filter := bson.M{"tokenId": tokenModel.TokenId}
+	update := bson.M{"$setOnInsert": tokenModel}
+	opts := options.Update().SetUpsert(true)
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, update, opts)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return false, err
+	}
+
+	// The token is only revoked by this call if it was not in the list yet
+	return res.(*mongo.UpdateResult).UpsertedCount > 0, nil
+}
+
+/* IsTokenRevoked checks if a token is in the revocation list */
+func (db *DbNoSqlV2) IsTokenRevoked(tokenId string) (bool, error) {
+	col := getCollection(db, "twitton", "revokedTokens")
+	condition := bson.M{"tokenId": tokenId}
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err := col.FindOne(ctx, condition).Err()
+
+	if err != nil && err == mongo.ErrNoDocuments {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* InsertActionToken inserts a single-use action token in the DB */
+func (db *DbNoSqlV2) InsertActionToken(token mr.ActionToken) error {
+	tokenModel, err := getActionTokenModel(token)
+
+	if err != nil {
+		return err
+	}
+
+	col := getCollection(db, "twitton", "actionTokens")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.InsertOne(sessCtx, tokenModel)
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	return err
+}
+
+/* ConsumeActionToken deletes an action token from the DB and returns it if it was not expired */
+func (db *DbNoSqlV2) ConsumeActionToken(hash string, action string) (mr.ActionToken, bool, error) {
+	var tokenModel m.ActionToken
+	var tokenRequest mr.ActionToken
+
+	col := getCollection(db, "twitton", "actionTokens")
+	condition := bson.M{
+		"hash":   hash,
+		"action": action,
+	}
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	// Deleting the token when it is found guarantees that it can be used only once
+	err := col.FindOneAndDelete(ctx, condition).Decode(&tokenModel)
+
+	if err != nil && err == mongo.ErrNoDocuments {
+		return tokenRequest, false, nil
+	} else if err != nil {
+		return tokenRequest, false, err
+	}
+
+	tokenRequest = getActionTokenRequest(tokenModel)
+
+	if !tokenRequest.ExpiresAt.IsZero() && tokenRequest.ExpiresAt.Before(time.Now()) {
+		return tokenRequest, false, nil
+	}
+
+	return tokenRequest, true, nil
+}
+
+/* DeleteActionTokens deletes all the action tokens of an user for an action */
+func (db *DbNoSqlV2) DeleteActionTokens(userId string, action string) error {
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return err
+	}
+
+	col := getCollection(db, "twitton", "actionTokens")
+	filter := bson.M{
+		"userId": objUserId,
+		"action": action,
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.DeleteMany(sessCtx, filter)
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	return err
+}
+
+// endregion
+
+// region "Access tokens"
+
+/* InsertAccessToken inserts a personal access token in the DB */
+func (db *DbNoSqlV2) InsertAccessToken(token mr.AccessToken) (string, error) {
+	tokenModel, err := getAccessTokenModel(token)
+
+	if err != nil {
+		return "", err
+	}
+
+	col := getCollection(db, "twitton", "accessTokens")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.InsertOne(sessCtx, tokenModel)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return "", err
+	}
+
+	result := res.(*mongo.InsertOneResult)
+	objID, _ := result.InsertedID.(primitive.ObjectID)
+
+	return objID.Hex(), nil
+}
+
+/* GetAccessTokens gets the personal access tokens of an user */
+func (db *DbNoSqlV2) GetAccessTokens(userId string) ([]*mr.AccessToken, error) {
+	var results []*mr.AccessToken
+	var tokensDbResults []*m.AccessToken
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return results, err
+	}
+
+	col := getCollection(db, "twitton", "accessTokens")
+	condition := bson.M{"userId": objUserId}
+	opts := options.Find()
+
+	opts.SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, condition, opts)
+
+	if err != nil {
+		return results, err
+	}
+
+	ctxCursor := context.TODO()
+
+	defer cursor.Close(ctxCursor)
+
+	err = cursor.All(ctxCursor, &tokensDbResults)
+
+	if err != nil {
+		return results, err
+	}
+
+	for _, tokenModel := range tokensDbResults {
+		tokenRequest := getAccessTokenRequest(*tokenModel)
+		results = append(results, &tokenRequest)
+	}
+
+	return results, nil
+}
+
+/* GetAccessToken gets a personal access token by its hash */
+func (db *DbNoSqlV2) GetAccessToken(hash string) (mr.AccessToken, bool, error) {
+	var tokenModel m.AccessToken
+	var tokenRequest mr.AccessToken
+
+	col := getCollection(db, "twitton", "accessTokens")
+	condition := bson.M{"hash": hash}
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err := col.FindOne(ctx, condition).Decode(&tokenModel)
+
+	if err != nil && err == mongo.ErrNoDocuments {
+		return tokenRequest, false, nil
+	} else if err != nil {
+		return tokenRequest, false, err
+	}
+
+	tokenRequest = getAccessTokenRequest(tokenModel)
+
+	return tokenRequest, true, nil
+}
+
+/* DeleteAccessToken deletes a personal access token of an user */
+func (db *DbNoSqlV2) DeleteAccessToken(id string, userId string) (bool, error) {
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return false, err
+	}
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return false, err
+	}
+
+	col := getCollection(db, "twitton", "accessTokens")
+	filter := bson.M{
+		"_id":    objId,
+		"userId": objUserId,
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.DeleteOne(sessCtx, filter)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return false, err
+	}
+
+	result := res.(*mongo.DeleteResult)
+
+	return result.DeletedCount > 0, nil
+}
+
+/* DeleteAccessTokens deletes all the personal access tokens of an user */
+func (db *DbNoSqlV2) DeleteAccessTokens(userId string) error {
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return err
+	}
+
+	col := getCollection(db, "twitton", "accessTokens")
+	filter := bson.M{"userId": objUserId}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.DeleteMany(sessCtx, filter)
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	return err
+}
+
+// endregion
+
+// region "Sessions"
+
+/* InsertSession inserts a session in the DB */
+func (db *DbNoSqlV2) InsertSession(session mr.Session) (string, error) {
+	sessionModel, err := getSessionModel(session)
+
+	if err != nil {
+		return "", err
+	}
+
+	col := getCollection(db, "twitton", "sessions")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.InsertOne(sessCtx, sessionModel)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return "", err
+	}
+
+	result := res.(*mongo.InsertOneResult)
+	objID, _ := result.InsertedID.(primitive.ObjectID)
+
+	return objID.Hex(), nil
+}
+
+/* GetSession gets a session by its id */
+func (db *DbNoSqlV2) GetSession(id string) (mr.Session, bool, error) {
+	var sessionModel m.Session
+	var sessionRequest mr.Session
+
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return sessionRequest, false, err
+	}
+
+	col := getCollection(db, "twitton", "sessions")
+	condition := bson.M{"_id": objId}
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err = col.FindOne(ctx, condition).Decode(&sessionModel)
+
+	if err != nil && err == mongo.ErrNoDocuments {
+		return sessionRequest, false, nil
+	} else if err != nil {
+		return sessionRequest, false, err
+	}
+
+	sessionRequest = getSessionRequest(sessionModel)
+
+	return sessionRequest, true, nil
+}
+
+/* GetSessions gets the active sessions of an user */
+func (db *DbNoSqlV2) GetSessions(userId string) ([]*mr.Session, error) {
+	var results []*mr.Session
+	var sessionsDbResults []*m.Session
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return results, err
+	}
+
+	col := getCollection(db, "twitton", "sessions")
+	condition := bson.M{
+		"userId":    objUserId,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+	opts := options.Find()
+
+	opts.SetSort(bson.D{{Key: "lastSeenAt", Value: -1}})
+
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, condition, opts)
+
+	if err != nil {
+		return results, err
+	}
+
+	ctxCursor := context.TODO()
+
+	defer cursor.Close(ctxCursor)
+
+	err = cursor.All(ctxCursor, &sessionsDbResults)
+
+	if err != nil {
+		return results, err
+	}
+
+	for _, sessionModel := range sessionsDbResults {
+		sessionRequest := getSessionRequest(*sessionModel)
+		results = append(results, &sessionRequest)
+	}
+
+	return results, nil
+}
+
+/* TouchSession updates the last time a session was seen and its expiration */
+func (db *DbNoSqlV2) TouchSession(id string, lastSeenAt time.Time, expiresAt time.Time) error {
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return err
+	}
+
+	col := getCollection(db, "twitton", "sessions")
+	filter := bson.M{"_id": objId}
+	update := bson.M{
+		"$set": bson.M{
+			"lastSeenAt": lastSeenAt,
+			"expiresAt":  expiresAt,
+		},
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, update)
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	return err
+}
+
+/* DeleteSession deletes a session of an user */
+func (db *DbNoSqlV2) DeleteSession(id string, userId string) (bool, error) {
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return false, err
+	}
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return false, err
+	}
+
+	col := getCollection(db, "twitton", "sessions")
+	filter := bson.M{
+		"_id":    objId,
+		"userId": objUserId,
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.DeleteOne(sessCtx, filter)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return false, err
+	}
+
+	result := res.(*mongo.DeleteResult)
+
+	return result.DeletedCount > 0, nil
+}
+
+/* DeleteSessions deletes all the sessions of an user except the one with the exceptId, if it is set */
+func (db *DbNoSqlV2) DeleteSessions(userId string, exceptId string) error {
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return err
+	}
+
+	col := getCollection(db, "twitton", "sessions")
+	filter := bson.M{"userId": objUserId}
+
+	if len(exceptId) > 0 {
+		objExceptId, err := getObjectId(exceptId)
+
+		if err != nil {
+			return err
+		}
+
+		filter["_id"] = bson.M{"$ne": objExceptId}
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.DeleteMany(sessCtx, filter)
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	return err
+}
+
+// endregion
+
+// region "Exports"
+
+/* InsertExport inserts an export in the DB */
+func (db *DbNoSqlV2) InsertExport(export mr.Export) (string, error) {
+	exportModel, err := getExportModel(export)
+
+	if err != nil {
+		return "", err
+	}
+
+	col := getCollection(db, "twitton", "exports")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.InsertOne(sessCtx, exportModel)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return "", err
+	}
+
+	result := res.(*mongo.InsertOneResult)
+	objID, _ := result.InsertedID.(primitive.ObjectID)
+
+	return objID.Hex(), nil
+}
+
+/* GetExport gets an export by its id */
+func (db *DbNoSqlV2) GetExport(id string) (mr.Export, bool, error) {
+	var exportModel m.Export
+	var exportRequest mr.Export
+
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return exportRequest, false, err
+	}
+
+	col := getCollection(db, "twitton", "exports")
+	condition := bson.M{"_id": objId}
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err = col.FindOne(ctx, condition).Decode(&exportModel)
+
+	if err != nil && err == mongo.ErrNoDocuments {
+		return exportRequest, false, nil
+	} else if err != nil {
+		return exportRequest, false, err
+	}
+
+	exportRequest = getExportRequest(exportModel)
+
+	return exportRequest, true, nil
+}
+
+/* GetExports gets the exports of an user that have not expired, the newest first */
+func (db *DbNoSqlV2) GetExports(userId string) ([]*mr.Export, error) {
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	condition := bson.M{
+		"userId":    objUserId,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+
+	return db.findExports(condition, bson.D{{Key: "createdAt", Value: -1}})
+}
+
+/* GetExpiredExports gets the exports that expired before a date */
+func (db *DbNoSqlV2) GetExpiredExports(before time.Time) ([]*mr.Export, error) {
+	condition := bson.M{"expiresAt": bson.M{"$lte": before}}
+
+	return db.findExports(condition, bson.D{{Key: "expiresAt", Value: 1}})
+}
+
+/* ModifyExport updates the status and the download token of an export, it returns false if it does not exist */
+func (db *DbNoSqlV2) ModifyExport(export mr.Export) (bool, error) {
+	objId, err := getObjectId(export.Id)
+
+	if err != nil {
+		return false, err
+	}
+
+	col := getCollection(db, "twitton", "exports")
+	filter := bson.M{"_id": objId}
+	update := bson.M{
+		"$set": bson.M{
+			"status":         export.Status,
+			"error":          export.Error,
+			"tokenHash":      export.TokenHash,
+			"tokenExpiresAt": export.TokenExpiresAt,
+		},
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.UpdateOne(sessCtx, filter, update)
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return false, err
+	}
+
+	return res.(*mongo.UpdateResult).MatchedCount > 0, nil
+}
+
+/* DeleteExport deletes an export */
+func (db *DbNoSqlV2) DeleteExport(id string) error {
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return err
+	}
+
+	col := getCollection(db, "twitton", "exports")
+	filter := bson.M{"_id": objId}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.DeleteOne(sessCtx, filter)
+
+		return result, err
+	}
+
+	_, err = db.executeTransaction(callback)
+
+	return err
+}
+
+// endregion
+
+// region "Login attempts"
+
+/* GetLoginAttempt gets the failed login attempts counted for a key */
+func (db *DbNoSqlV2) GetLoginAttempt(key string) (mr.LoginAttempt, bool, error) {
+	var attemptModel m.LoginAttempt
+	var attemptRequest mr.LoginAttempt
+
+	col := getCollection(db, "twitton", "loginAttempts")
+	condition := bson.M{
+		"key":       key,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err := col.FindOne(ctx, condition).Decode(&attemptModel)
+
+	if err != nil && err == mongo.ErrNoDocuments {
+		return attemptRequest, false, nil
+	} else if err != nil {
+		return attemptRequest, false, err
+	}
+
+	attemptRequest = getLoginAttemptRequest(attemptModel)
+
+	return attemptRequest, true, nil
+}
+
+/* IncrementLoginAttempt adds a failed login attempt to a key and returns the updated counter */
+func (db *DbNoSqlV2) IncrementLoginAttempt(key string, expiresAt time.Time) (mr.LoginAttempt, error) {
+	var attemptModel m.LoginAttempt
+
+	now := time.Now()
+	col := getCollection(db, "twitton", "loginAttempts")
+	filter := bson.M{"key": key}
+	update := bson.M{
+		"$inc": bson.M{"failures": 1},
+		"$set": bson.M{
+			"lastFailureAt": now,
+			"expiresAt":     expiresAt,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		// The TTL index does not remove the expired counters immediately
+		_, err := col.DeleteOne(sessCtx, bson.M{"key": key, "expiresAt": bson.M{"$lte": now}})
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = col.FindOneAndUpdate(sessCtx, filter, update, opts).Decode(&attemptModel)
+
+		return attemptModel, err
+	}
+
+	_, err := db.executeTransaction(callback)
+
+	return getLoginAttemptRequest(attemptModel), err
+}
+
+/* DeleteLoginAttempt resets the failed login attempts of a key */
+func (db *DbNoSqlV2) DeleteLoginAttempt(key string) error {
+	col := getCollection(db, "twitton", "loginAttempts")
+	filter := bson.M{"key": key}
+
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		result, err := col.DeleteOne(sessCtx, filter)
+
+		return result, err
+	}
+
+	_, err := db.executeTransaction(callback)
+
+	return err
+}
+
+// endregion
+
+// region "Helpers"
+
+func (db *DbNoSqlV2) insertTweet(tweetModel m.Tweet, filter bson.M, claim func(sessCtx mongo.SessionContext) (bool, error)) (string, bool, error) {
+	mentions, err := db.getMentionedIds(tweetModel.Message, tweetModel.UserId)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	tweetModel.Id = primitive.NewObjectID()
+	tweetModel.UserId = primitive.NilObjectID
+	tweetModel.Hashtags = helpers.GetHashtags(tweetModel.Message)
+	tweetModel.Mentions = mentions
+
+	update := bson.M{
+		"$push": bson.M{
+			"tweets": tweetModel,
+		},
+	}
+
+	col := getCollection(db, "twitton", "users")
+	callback := func(sessCtx mongo.SessionContext) (any, error) {
+		// The claim runs in the same transaction, so the tweet is only inserted by who gets it
+		if claim != nil {
+			isClaimed, err := claim(sessCtx)
+
+			if err != nil || !isClaimed {
+				return &mongo.UpdateResult{}, err
+			}
+		}
+
+		result, err := col.UpdateOne(sessCtx, filter, update)
+
+		if err != nil || result.MatchedCount < 1 {
+			return result, err
+		}
+
+		err = updateTweetCounters(sessCtx, col, tweetModel, 1)
+
+		if err == nil {
+			err = db.updateHashtagCounts(sessCtx, tweetModel.Hashtags, tweetModel.Date, 1)
+		}
+
+		return result, err
+	}
+
+	res, err := db.executeTransaction(callback)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	if res.(*mongo.UpdateResult).MatchedCount < 1 {
+		return "", false, nil
+	}
+
+	// The tweets are embedded, so the id is the one generated for the pushed tweet
+	return tweetModel.Id.Hex(), true, nil
+}
+
+/* updateTweetCounters updates the counters of the tweets replied, retweeted or quoted by a tweet */
+func updateTweetCounters(sessCtx mongo.SessionContext, col *mongo.Collection, tweetModel m.Tweet, delta int) error {
+	counters := map[string]primitive.ObjectID{
+		"tweets.$.replyCount":   tweetModel.InReplyTo,
+		"tweets.$.retweetCount": tweetModel.RetweetOf,
+		"tweets.$.quoteCount":   tweetModel.QuotedTweetId,
+	}
+
+	for counter, objId := range counters {
+		if objId.IsZero() {
+			continue
+		}
+
+		_, err := col.UpdateOne(sessCtx, bson.M{"tweets._id": objId}, bson.M{"$inc": bson.M{counter: delta}})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *DbNoSqlV2) findTweets(condition bson.M, page int64, limit int64, isNewestFirst bool) ([]*mr.Tweet, int64, error) {
+	// The first match discards the users without matching tweets, the second one the other tweets of the users
+	matchUsers := bson.M{"$match": condition}
+	unwindTweets := bson.M{"$unwind": bson.M{
+		"path":                       "$tweets",
+		"preserveNullAndEmptyArrays": false}}
+	matchTweets := bson.M{"$match": condition}
+	order := 1
+
+	if isNewestFirst {
+		order = -1
+	}
+
+	basePipeline := []bson.M{matchUsers, unwindTweets, matchTweets}
+
+	return db.findSortedTweets(basePipeline, page, limit, bson.D{{Key: "tweets.date", Value: order}})
+}
+
+/* findSortedTweets gets a page of the tweets unwound by a pipeline */
+func (db *DbNoSqlV2) findSortedTweets(basePipeline []bson.M, page int64, limit int64, sortFields bson.D) ([]*mr.Tweet, int64, error) {
+	var results []*mr.Tweet
+
+	// region Pipeline
+
+	count := bson.M{"$count": "total"}
+	sort := bson.M{"$sort": sortFields}
+	projectResult := bson.M{"$project": bson.M{
+		"_id":            "$tweets._id",
+		"userId":         "$_id",
+		"message":        "$tweets.message",
+		"date":           "$tweets.date",
+		"active":         "$tweets.active",
+		"inReplyTo":      "$tweets.inReplyTo",
+		"conversationId": "$tweets.conversationId",
+		"replyCount":     "$tweets.replyCount",
+		"retweetOf":      "$tweets.retweetOf",
+		"quotedTweetId":  "$tweets.quotedTweetId",
+		"retweetCount":   "$tweets.retweetCount",
+		"quoteCount":     "$tweets.quoteCount",
+		"likeCount":      "$tweets.likeCount",
+		"editedAt":       "$tweets.editedAt",
+		"media":          "$tweets.media"}}
+
+	countPipeline := append(basePipeline, count)
+	aggPipeline := append(basePipeline, sort)
+
+	// Without a limit all the tweets are returned
+	if limit > 0 {
+		aggPipeline = append(aggPipeline, bson.M{"$skip": (page - 1) * limit}, bson.M{"$limit": limit})
+	}
+
+	aggPipeline = append(aggPipeline, projectResult)
+
+	// endregion
+
+	dbResults, total, err := getResults[m.Tweet](db, "users", countPipeline, aggPipeline)
+
+	if err == nil {
+		for _, tweetModel := range dbResults {
+			tweetRequest := getTweetRequest(*tweetModel)
+			results = append(results, &tweetRequest)
+		}
+	}
+
+	return results, total, err
+}
+
+func getDraftFilter(id string, userId string) (bson.M, error) {
+	objId, err := getObjectId(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	objUserId, err := getObjectId(userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"_id":    objId,
+		"userId": objUserId,
+	}
+
+	return filter, nil
+}
+
+func (db *DbNoSqlV2) findScheduledTweets(condition bson.M, page int64, limit int64) ([]*mr.ScheduledTweet, int64, error) {
+	var results []*mr.ScheduledTweet
+	var scheduledDbResults []*m.ScheduledTweet
+
+	col := getCollection(db, "twitton", "scheduledTweets")
+	opts := options.Find()
+
+	opts.SetSort(bson.D{{Key: "publishAt", Value: 1}})
+
+	ctxCount, cancelCount := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelCount()
+
+	total, err := col.CountDocuments(ctxCount, condition)
+
+	if err != nil {
+		return results, total, err
+	}
+
+	// Without a limit all the tweets are returned
+	if limit > 0 {
+		opts.SetSkip((page - 1) * limit)
+		opts.SetLimit(limit)
+	}
+
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, condition, opts)
+
+	if err != nil {
+		return results, total, err
+	}
+
+	ctxCursor := context.TODO()
+
+	defer cursor.Close(ctxCursor)
+
+	err = cursor.All(ctxCursor, &scheduledDbResults)
+
+	if err != nil {
+		return results, total, err
+	}
+
+	for _, scheduledModel := range scheduledDbResults {
+		scheduledRequest := getScheduledTweetRequest(*scheduledModel)
+		results = append(results, &scheduledRequest)
 	}
 
-	conditions := make([]bson.M, 0)
-	conditionsCount := make([]bson.M, 0)
-	conditionsAgg := make([]bson.M, 0)
+	return results, total, nil
+}
 
-	skip := (page - 1) * limit
+func (db *DbNoSqlV2) findExports(condition bson.M, sort bson.D) ([]*mr.Export, error) {
+	var results []*mr.Export
+	var exportsDbResults []*m.Export
 
-	conditions = append(conditions, bson.M{"$match": bson.M{"_id": objId}})
-	conditions = append(conditions, bson.M{
-		"$lookup": bson.M{
-			"from":         "users",
-			"localField":   "following",
-			"foreignField": "_id",
-			"as":           "r",
-		}})
-	conditions = append(conditions, bson.M{
-		"$unwind": bson.M{
-			"path":                       "$r",
-			"preserveNullAndEmptyArrays": false,
-		},
-	})
-	conditions = append(conditions, bson.M{
-		"$project": bson.M{
-			"_id":             0,
-			"userId":          "$_id",
-			"userFollowingId": "$r._id",
-			"tweet": bson.M{
-				"$filter": bson.M{
-					"input": "$r.tweets",
-					"as":    "tweet",
-					"cond":  bson.M{"$eq": [2]any{"$$tweet.active", true}},
-				},
-			},
-		},
-	})
-	conditions = append(conditions, bson.M{
-		"$unwind": bson.M{
-			"path":                       "$tweet",
-			"preserveNullAndEmptyArrays": false,
-		},
-	})
+	col := getCollection(db, "twitton", "exports")
+	opts := options.Find()
 
-	conditionsCount = append(conditionsCount, conditions...)
-	conditionsCount = append(conditionsCount, bson.M{"$count": "total"})
+	opts.SetSort(sort)
 
-	conditionsAgg = append(conditionsAgg, conditions...)
-	conditionsAgg = append(conditionsAgg, bson.M{"$sort": bson.M{"tweet.date": -1}})
-	conditionsAgg = append(conditionsAgg, bson.M{"$skip": skip})
-	conditionsAgg = append(conditionsAgg, bson.M{"$limit": limit})
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
-	if isOnlyTweets {
-		conditionsAgg = append(conditionsAgg, bson.M{
-			"$project": bson.M{
-				"_id":     "$tweet._id",
-				"userId":  "$userFollowingId",
-				"message": "$tweet.message",
-				"date":    "$tweet.date",
-			}})
+	defer cancelFind()
 
-		var dbResults []*m.Tweet
-		var reqResults []*mr.Tweet
+	cursor, err := col.Find(ctxFind, condition, opts)
 
-		dbResults, total, err = getResults[m.Tweet](db, "users", conditionsCount, conditionsAgg)
+	if err != nil {
+		return results, err
+	}
 
-		if err == nil {
-			for _, tweetModel := range dbResults {
-				tweetRequest := getTweetRequest(*tweetModel)
-				reqResults = append(reqResults, &tweetRequest)
-			}
+	ctxCursor := context.TODO()
 
-			results = reqResults
-		}
-	} else {
-		var dbResults []*m.UserTweet
-		var reqResults []*mr.UserTweet
+	defer cursor.Close(ctxCursor)
 
-		dbResults, total, err = getResults[m.UserTweet](db, "users", conditionsCount, conditionsAgg)
+	err = cursor.All(ctxCursor, &exportsDbResults)
 
-		if err == nil {
-			for _, userTweetModel := range dbResults {
-				userTweetRequest := getUserTweetRequest(*userTweetModel)
-				reqResults = append(reqResults, &userTweetRequest)
-			}
+	if err != nil {
+		return results, err
+	}
 
-			results = reqResults
-		}
+	for _, exportModel := range exportsDbResults {
+		exportRequest := getExportRequest(*exportModel)
+		results = append(results, &exportRequest)
 	}
 
-	return results, total, err
+	return results, nil
 }
 
-// endregion
-
-// region "Helpers"
-
 func (db *DbNoSqlV2) deleteTweetLogical(id string, userId string) error {
 	objId, err := getObjectId(id)
 
@@ -734,10 +3126,28 @@ func (db *DbNoSqlV2) deleteTweetLogical(id string, userId string) error {
 		return err
 	}
 
+	tweet, isFound, err := db.GetTweet(id)
+
+	if err != nil {
+		return err
+	}
+
+	if !isFound {
+		return mongo.ErrNoDocuments
+	}
+
+	if tweet.UserId != userId {
+		return errors.New("invalid operation - cannot delete a non-owner tweet")
+	}
+
 	objUserId, _ := getObjectId(userId)
+	tweetModel, _ := getTweetModel(tweet)
+	// Only the request that deactivates the tweet changes the counters, so concurrent deletes do not decrement them twice
 	filter := bson.M{
-		"_id":        objUserId,
-		"tweets._id": objId,
+		"_id": objUserId,
+		"tweets": bson.M{
+			"$elemMatch": bson.M{"_id": objId, "active": true},
+		},
 	}
 	update := bson.M{
 		"$set": bson.M{
@@ -748,6 +3158,16 @@ func (db *DbNoSqlV2) deleteTweetLogical(id string, userId string) error {
 	col := getCollection(db, "twitton", "users")
 	callback := func(sessCtx mongo.SessionContext) (any, error) {
 		result, err := col.UpdateOne(sessCtx, filter, update)
+		isDeactivated := err == nil && result.ModifiedCount == 1
+
+		// The replies, retweets and quotes are kept, only the counters of the referenced tweets change
+		if isDeactivated {
+			err = updateTweetCounters(sessCtx, col, tweetModel, -1)
+		}
+
+		if err == nil && isDeactivated {
+			err = db.updateHashtagCounts(sessCtx, helpers.GetHashtags(tweet.Message), tweet.Date, -1)
+		}
 
 		return result, err
 	}
@@ -764,6 +3184,16 @@ func (db *DbNoSqlV2) deleteTweetFisical(id string, userId string) error {
 		return err
 	}
 
+	tweet, isFound, err := db.GetTweet(id)
+
+	if err != nil {
+		return err
+	}
+
+	if !isFound {
+		return mongo.ErrNoDocuments
+	}
+
 	objUserId, _ := getObjectId(userId)
 	update := bson.M{
 		"$pull": bson.M{
@@ -778,7 +3208,12 @@ func (db *DbNoSqlV2) deleteTweetFisical(id string, userId string) error {
 		return result, err
 	}
 
-	_, err = db.executeTransaction(callback)
+	res, err := db.executeTransaction(callback)
+
+	// The images are removed once the tweet is no longer in the DB, the tweets of other users are not pulled
+	if err == nil && res.(*mongo.UpdateResult).ModifiedCount > 0 {
+		media.Delete(tweet.Media)
+	}
 
 	return err
 }
@@ -809,17 +3244,302 @@ func (db *DbNoSqlV2) deleteRelationFisical(relation mr.Relation) error {
 	return err
 }
 
-func encryptPassword(password string) (string, error) {
-	// Minimum - cost: 6
-	// Common user - cost: 6
-	// Admin user - cost: 8
+/* getMentionedIds resolves the users mentioned in a message by their handles */
+func (db *DbNoSqlV2) getMentionedIds(message string, authorId primitive.ObjectID) ([]primitive.ObjectID, error) {
+	var results []primitive.ObjectID
+	var usersDbResults []*m.User
+
+	mentions := helpers.GetMentions(message)
+
+	if len(mentions) == 0 {
+		return results, nil
+	}
+
+	col := getCollection(db, "twitton", "users")
+	condition := bson.M{"handle": bson.M{"$in": mentions}}
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	cursor, err := col.Find(ctx, condition, opts)
+
+	if err == nil {
+		err = cursor.All(ctx, &usersDbResults)
+	}
+
+	if err != nil {
+		return results, err
+	}
+
+	// The unknown handles are left as plain text
+	for _, userModel := range usersDbResults {
+		if userModel.Id != authorId {
+			results = append(results, userModel.Id)
+		}
+	}
+
+	return results, nil
+}
+
+/* getAvailableHandle returns the base handle, or the first one with a numeric suffix, that no user has */
+func (db *DbNoSqlV2) getAvailableHandle(base string) (string, error) {
+	col := getCollection(db, "twitton", "users")
+	opts := options.Count().SetLimit(1)
+	handle := base
+
+	for i := 2; ; i++ {
+		ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+		count, err := col.CountDocuments(ctx, bson.M{"handle": handle}, opts)
+
+		cancel()
+
+		if err != nil {
+			return "", err
+		}
+
+		if count == 0 {
+			return handle, nil
+		}
+
+		handle = fmt.Sprintf("%s%d", base, i)
+	}
+}
+
+/* setMissingHandles sets the handles of the users registered before the handles were added, the oldest users first */
+func (db *DbNoSqlV2) setMissingHandles() error {
+	var usersDbResults []*m.User
+
+	col := getCollection(db, "twitton", "users")
+	condition := bson.M{"handle": bson.M{"$exists": false}}
+	opts := options.Find().SetProjection(bson.M{"_id": 1, "email": 1}).SetSort(bson.M{"_id": 1})
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	cursor, err := col.Find(ctxFind, condition, opts)
+
+	if err == nil {
+		err = cursor.All(ctxFind, &usersDbResults)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, userModel := range usersDbResults {
+		handle, err := db.getAvailableHandle(helpers.GetHandle(userModel.Email))
+
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+		_, err = col.UpdateByID(ctx, userModel.Id, bson.M{"$set": bson.M{"handle": handle}})
+
+		cancel()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/* updateHashtagCounts updates the counts of the trending hashtags in the time bucket of a tweet */
+func (db *DbNoSqlV2) updateHashtagCounts(sessCtx mongo.SessionContext, hashtags []string, date time.Time, delta int) error {
+	bucket := helpers.GetTrendsBucket(date)
+	expiresAt := helpers.GetTrendsBucketExpiration(bucket)
+
+	// The buckets out of the trends window are not read anymore
+	if len(hashtags) == 0 || expiresAt.Before(time.Now()) {
+		return nil
+	}
+
+	col := getCollection(db, "twitton", "hashtagCounts")
+	opts := options.Update().SetUpsert(true)
+
+	for _, hashtag := range hashtags {
+		_, err := col.UpdateOne(sessCtx,
+			bson.M{"tag": hashtag, "bucket": bucket},
+			bson.M{
+				"$inc":         bson.M{"count": delta},
+				"$setOnInsert": bson.M{"expiresAt": expiresAt},
+			},
+			opts)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/* deleteUserLikes deletes the likes given by an user and the likes of its tweets */
+func (db *DbNoSqlV2) deleteUserLikes(sessCtx mongo.SessionContext, userId primitive.ObjectID, tweetIds []any) error {
+	likesCol := getCollection(db, "twitton", "likes")
+	usersCol := getCollection(db, "twitton", "users")
+
+	likedIds, err := likesCol.Distinct(sessCtx, "tweetId", bson.M{"userId": userId})
+
+	if err != nil {
+		return err
+	}
+
+	// The counts of the tweets liked by the user are kept consistent
+	for _, likedId := range likedIds {
+		_, err = usersCol.UpdateOne(sessCtx,
+			bson.M{"tweets._id": likedId},
+			bson.M{"$inc": bson.M{"tweets.$.likeCount": -1}})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = likesCol.DeleteMany(sessCtx, bson.M{"userId": userId})
+
+	if err != nil {
+		return err
+	}
+
+	if len(tweetIds) == 0 {
+		return nil
+	}
+
+	_, err = likesCol.DeleteMany(sessCtx, bson.M{"tweetId": bson.M{"$in": tweetIds}})
+
+	return err
+}
+
+/* revertUserTweetCounters undoes the counters added by the active tweets of an user to other tweets and to the trends */
+func (db *DbNoSqlV2) revertUserTweetCounters(sessCtx mongo.SessionContext, userId primitive.ObjectID) error {
+	var userModel m.User
+
+	col := getCollection(db, "twitton", "users")
+	opts := options.FindOne().SetProjection(bson.M{"tweets": 1})
+	err := col.FindOne(sessCtx, bson.M{"_id": userId}, opts).Decode(&userModel)
+
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, tweetModel := range userModel.Tweets {
+		if !tweetModel.Active {
+			continue
+		}
+
+		err = updateTweetCounters(sessCtx, col, tweetModel, -1)
+
+		if err == nil {
+			err = db.updateHashtagCounts(sessCtx, helpers.GetHashtags(tweetModel.Message), tweetModel.Date, -1)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
-	cost := 8
+func encryptPassword(password string) (string, error) {
+	cost := helpers.GetBcryptCost()
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), cost)
 
 	return string(bytes), err
 }
 
+func (db *DbNoSqlV2) createIndexes() error {
+	indexes := map[string][]mongo.IndexModel{
+		"revokedTokens": {
+			{Keys: bson.D{{Key: "tokenId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"actionTokens": {
+			{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "action", Value: 1}}},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"accessTokens": {
+			{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "userId", Value: 1}}},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"sessions": {
+			{Keys: bson.D{{Key: "userId", Value: 1}}},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"loginAttempts": {
+			{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"likes": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "tweetId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "date", Value: -1}}},
+			{Keys: bson.D{{Key: "tweetId", Value: 1}}},
+		},
+		"drafts": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "date", Value: -1}}},
+		},
+		"exports": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}},
+		},
+		"bookmarks": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "tweetId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "date", Value: -1}}},
+		},
+		"scheduledTweets": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "publishAt", Value: 1}}},
+			{Keys: bson.D{{Key: "publishAt", Value: 1}}},
+		},
+		"tweetRevisions": {
+			{Keys: bson.D{{Key: "tweetId", Value: 1}, {Key: "date", Value: 1}}},
+		},
+		"hashtagCounts": {
+			{Keys: bson.D{{Key: "tag", Value: 1}, {Key: "bucket", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "bucket", Value: 1}}},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"users": {
+			{
+				Keys: bson.D{{Key: "handle", Value: 1}},
+				Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+					"handle": bson.M{"$type": "string"},
+				}),
+			},
+			{Keys: bson.D{{Key: "tweets._id", Value: 1}}},
+			{Keys: bson.D{{Key: "tweets.hashtags", Value: 1}}},
+			{Keys: bson.D{{Key: "tweets.mentions", Value: 1}}},
+			{Keys: bson.D{{Key: "tweets.inReplyTo", Value: 1}}},
+			{Keys: bson.D{{Key: "tweets.conversationId", Value: 1}}},
+			{Keys: bson.D{{Key: "tweets.message", Value: "text"}}, Options: options.Index().SetDefaultLanguage("english")},
+		},
+	}
+
+	for colName, models := range indexes {
+		col := getCollection(db, "twitton", colName)
+		ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+		_, err := col.Indexes().CreateMany(ctx, models)
+
+		cancel()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (db *DbNoSqlV2) executeTransaction(callback func(sessCtx mongo.SessionContext) (any, error)) (any, error) {
 	session, err := db.Connection.StartSession()
 
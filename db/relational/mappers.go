@@ -3,6 +3,7 @@ package relational
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	m "models/relational"
 	mr "models/request"
@@ -13,17 +14,36 @@ import (
 /* getUserRequest obtains the Request User model */
 func getUserRequest(userModel m.User) mr.User {
 	requestModel := mr.User{
-		Id:        strconv.FormatUint(userModel.Id, 10),
-		Name:      userModel.Name,
-		LastName:  userModel.LastName,
-		Email:     userModel.Email,
-		BirthDate: userModel.BirthDate,
-		Avatar:    userModel.Avatar,
-		Banner:    userModel.Banner,
-		Biography: userModel.Biography,
-		Location:  userModel.Location,
-		WebSite:   userModel.WebSite,
-		Password:  userModel.Password,
+		Id:              strconv.FormatUint(userModel.Id, 10),
+		Name:            userModel.Name,
+		LastName:        userModel.LastName,
+		Email:           userModel.Email,
+		BirthDate:       userModel.BirthDate,
+		Avatar:          userModel.Avatar,
+		Banner:          userModel.Banner,
+		Biography:       userModel.Biography,
+		Location:        userModel.Location,
+		WebSite:         userModel.WebSite,
+		Password:        userModel.Password,
+		Verified:        userModel.Verified,
+		TokensRevokedAt: userModel.TokensRevokedAt,
+		TotpSecret:      userModel.TotpSecret,
+		TotpEnabled:     userModel.TotpEnabled,
+		TotpLastCounter: userModel.TotpLastCounter,
+		Role:            userModel.Role,
+	}
+
+	if userModel.Handle != nil {
+		requestModel.Handle = *userModel.Handle
+	}
+
+	if userModel.DeleteAt != nil {
+		requestModel.DeleteAt = *userModel.DeleteAt
+	}
+
+	// The users registered before the roles were added have no role
+	if len(requestModel.Role) < 1 {
+		requestModel.Role = mr.RoleUser
 	}
 
 	return requestModel
@@ -40,17 +60,31 @@ func getUserModel(requestModel mr.User) (m.User, error) {
 	}
 
 	userModel = m.User{
-		Id:        id,
-		Name:      requestModel.Name,
-		LastName:  requestModel.LastName,
-		Email:     requestModel.Email,
-		BirthDate: requestModel.BirthDate,
-		Avatar:    requestModel.Avatar,
-		Banner:    requestModel.Banner,
-		Biography: requestModel.Biography,
-		Location:  requestModel.Location,
-		WebSite:   requestModel.WebSite,
-		Password:  requestModel.Password,
+		Id:              id,
+		Name:            requestModel.Name,
+		LastName:        requestModel.LastName,
+		Email:           requestModel.Email,
+		BirthDate:       requestModel.BirthDate,
+		Avatar:          requestModel.Avatar,
+		Banner:          requestModel.Banner,
+		Biography:       requestModel.Biography,
+		Location:        requestModel.Location,
+		WebSite:         requestModel.WebSite,
+		Password:        requestModel.Password,
+		Verified:        requestModel.Verified,
+		TokensRevokedAt: requestModel.TokensRevokedAt,
+		TotpSecret:      requestModel.TotpSecret,
+		TotpEnabled:     requestModel.TotpEnabled,
+		TotpLastCounter: requestModel.TotpLastCounter,
+		Role:            requestModel.Role,
+	}
+
+	if len(requestModel.Handle) > 0 {
+		userModel.Handle = &requestModel.Handle
+	}
+
+	if !requestModel.DeleteAt.IsZero() {
+		userModel.DeleteAt = &requestModel.DeleteAt
 	}
 
 	return userModel, nil
@@ -59,11 +93,22 @@ func getUserModel(requestModel mr.User) (m.User, error) {
 /* getTweetRequest obtains the Request Tweet model */
 func getTweetRequest(tweetModel m.Tweet) mr.Tweet {
 	requestModel := mr.Tweet{
-		Id:      strconv.FormatUint(tweetModel.Id, 10),
-		UserId:  strconv.FormatUint(tweetModel.UserId, 10),
-		Message: tweetModel.Message,
-		Date:    tweetModel.Date,
-		Active:  tweetModel.Active,
+		Id:             strconv.FormatUint(tweetModel.Id, 10),
+		UserId:         strconv.FormatUint(tweetModel.UserId, 10),
+		Message:        tweetModel.Message,
+		Date:           tweetModel.Date,
+		Active:         tweetModel.Active,
+		InReplyTo:      getStringId(tweetModel.InReplyTo),
+		ConversationId: getStringId(tweetModel.ConversationId),
+		ReplyCount:     tweetModel.ReplyCount,
+		RetweetOf:      getStringId(tweetModel.RetweetOf),
+		QuotedTweetId:  getStringId(tweetModel.QuotedTweetId),
+		RetweetCount:   tweetModel.RetweetCount,
+		QuoteCount:     tweetModel.QuoteCount,
+		LikeCount:      tweetModel.LikeCount,
+		Edited:         tweetModel.EditedAt != nil,
+		EditedAt:       tweetModel.EditedAt,
+		Media:          tweetModel.Media,
 	}
 
 	return requestModel
@@ -85,17 +130,211 @@ func getTweetModel(requestModel mr.Tweet) (m.Tweet, error) {
 		return tweetModel, err
 	}
 
+	inReplyTo, err := getUintIdPointer(requestModel.InReplyTo)
+
+	if err != nil {
+		return tweetModel, err
+	}
+
+	conversationId, err := getUintIdPointer(requestModel.ConversationId)
+
+	if err != nil {
+		return tweetModel, err
+	}
+
+	retweetOf, err := getUintIdPointer(requestModel.RetweetOf)
+
+	if err != nil {
+		return tweetModel, err
+	}
+
+	quotedTweetId, err := getUintIdPointer(requestModel.QuotedTweetId)
+
+	if err != nil {
+		return tweetModel, err
+	}
+
 	tweetModel = m.Tweet{
-		Id:      uintId,
-		Message: requestModel.Message,
-		Date:    requestModel.Date,
-		Active:  requestModel.Active,
-		UserId:  uintUserId,
+		Id:             uintId,
+		Message:        requestModel.Message,
+		Date:           requestModel.Date,
+		Active:         requestModel.Active,
+		UserId:         uintUserId,
+		InReplyTo:      inReplyTo,
+		ConversationId: conversationId,
+		ReplyCount:     requestModel.ReplyCount,
+		RetweetOf:      retweetOf,
+		QuotedTweetId:  quotedTweetId,
+		RetweetCount:   requestModel.RetweetCount,
+		QuoteCount:     requestModel.QuoteCount,
+		LikeCount:      requestModel.LikeCount,
+		EditedAt:       requestModel.EditedAt,
+		Media:          requestModel.Media,
 	}
 
 	return tweetModel, nil
 }
 
+/* getTrendRequest obtains the Request Trend model */
+func getTrendRequest(countModel m.HashtagCount) mr.Trend {
+	requestModel := mr.Trend{
+		Tag:   countModel.Tag,
+		Count: countModel.Count,
+	}
+
+	return requestModel
+}
+
+/* getTweetRevisionModel obtains the DB TweetRevision model with the current message of a tweet */
+func getTweetRevisionModel(tweetModel m.Tweet) m.TweetRevision {
+	revisionModel := m.TweetRevision{
+		TweetId: tweetModel.Id,
+		Message: tweetModel.Message,
+		Date:    tweetModel.Date,
+	}
+
+	// The date of a revision is the one when its message was written
+	if tweetModel.EditedAt != nil {
+		revisionModel.Date = *tweetModel.EditedAt
+	}
+
+	return revisionModel
+}
+
+/* getTweetRevisionRequest obtains the Request TweetRevision model */
+func getTweetRevisionRequest(revisionModel m.TweetRevision) mr.TweetRevision {
+	requestModel := mr.TweetRevision{
+		Id:      strconv.FormatUint(revisionModel.Id, 10),
+		TweetId: strconv.FormatUint(revisionModel.TweetId, 10),
+		Message: revisionModel.Message,
+		Date:    revisionModel.Date,
+	}
+
+	return requestModel
+}
+
+/* getScheduledTweetModel obtains the DB ScheduledTweet model */
+func getScheduledTweetModel(requestModel mr.ScheduledTweet) (m.ScheduledTweet, error) {
+	var scheduledModel m.ScheduledTweet
+
+	uintId, err := getUintId(requestModel.Id)
+
+	if err != nil {
+		return scheduledModel, err
+	}
+
+	uintUserId, err := getUintId(requestModel.UserId)
+
+	if err != nil {
+		return scheduledModel, err
+	}
+
+	inReplyTo, err := getUintIdPointer(requestModel.InReplyTo)
+
+	if err != nil {
+		return scheduledModel, err
+	}
+
+	conversationId, err := getUintIdPointer(requestModel.ConversationId)
+
+	if err != nil {
+		return scheduledModel, err
+	}
+
+	quotedTweetId, err := getUintIdPointer(requestModel.QuotedTweetId)
+
+	if err != nil {
+		return scheduledModel, err
+	}
+
+	scheduledModel = m.ScheduledTweet{
+		Id:             uintId,
+		UserId:         uintUserId,
+		Message:        requestModel.Message,
+		InReplyTo:      inReplyTo,
+		ConversationId: conversationId,
+		QuotedTweetId:  quotedTweetId,
+		Media:          requestModel.Media,
+		PublishAt:      requestModel.PublishAt,
+		Date:           requestModel.Date,
+	}
+
+	return scheduledModel, nil
+}
+
+/* getScheduledTweetRequest obtains the Request ScheduledTweet model */
+func getScheduledTweetRequest(scheduledModel m.ScheduledTweet) mr.ScheduledTweet {
+	requestModel := mr.ScheduledTweet{
+		Id:             strconv.FormatUint(scheduledModel.Id, 10),
+		UserId:         strconv.FormatUint(scheduledModel.UserId, 10),
+		Message:        scheduledModel.Message,
+		InReplyTo:      getStringId(scheduledModel.InReplyTo),
+		ConversationId: getStringId(scheduledModel.ConversationId),
+		QuotedTweetId:  getStringId(scheduledModel.QuotedTweetId),
+		Media:          scheduledModel.Media,
+		PublishAt:      scheduledModel.PublishAt,
+		Date:           scheduledModel.Date,
+	}
+
+	return requestModel
+}
+
+/* getDraftModel obtains the DB Draft model */
+func getDraftModel(requestModel mr.Draft) (m.Draft, error) {
+	var draftModel m.Draft
+
+	uintId, err := getUintId(requestModel.Id)
+
+	if err != nil {
+		return draftModel, err
+	}
+
+	uintUserId, err := getUintId(requestModel.UserId)
+
+	if err != nil {
+		return draftModel, err
+	}
+
+	inReplyTo, err := getUintIdPointer(requestModel.InReplyTo)
+
+	if err != nil {
+		return draftModel, err
+	}
+
+	quotedTweetId, err := getUintIdPointer(requestModel.QuotedTweetId)
+
+	if err != nil {
+		return draftModel, err
+	}
+
+	draftModel = m.Draft{
+		Id:            uintId,
+		UserId:        uintUserId,
+		Message:       requestModel.Message,
+		InReplyTo:     inReplyTo,
+		QuotedTweetId: quotedTweetId,
+		Media:         requestModel.Media,
+		Date:          requestModel.Date,
+	}
+
+	return draftModel, nil
+}
+
+/* getDraftRequest obtains the Request Draft model */
+func getDraftRequest(draftModel m.Draft) mr.Draft {
+	requestModel := mr.Draft{
+		Id:            strconv.FormatUint(draftModel.Id, 10),
+		UserId:        strconv.FormatUint(draftModel.UserId, 10),
+		Message:       draftModel.Message,
+		InReplyTo:     getStringId(draftModel.InReplyTo),
+		QuotedTweetId: getStringId(draftModel.QuotedTweetId),
+		Media:         draftModel.Media,
+		Date:          draftModel.Date,
+	}
+
+	return requestModel
+}
+
 /* getRelationRequest obtains the Request Relation model */
 func getRelationRequest(relationModel m.Relation) mr.Relation {
 	requestModel := mr.Relation{
@@ -105,10 +344,254 @@ func getRelationRequest(relationModel m.Relation) mr.Relation {
 	return requestModel
 }
 
+/* getRevokedTokenModel obtains the DB RevokedToken model */
+func getRevokedTokenModel(requestModel mr.RevokedToken) (m.RevokedToken, error) {
+	var tokenModel m.RevokedToken
+
+	uintUserId, err := getUintId(requestModel.UserId)
+
+	if err != nil {
+		return tokenModel, err
+	}
+
+	tokenModel = m.RevokedToken{
+		TokenId:   requestModel.TokenId,
+		UserId:    uintUserId,
+		ExpiresAt: requestModel.ExpiresAt,
+	}
+
+	return tokenModel, nil
+}
+
+/* getActionTokenModel obtains the DB ActionToken model */
+func getActionTokenModel(requestModel mr.ActionToken) (m.ActionToken, error) {
+	var tokenModel m.ActionToken
+
+	uintUserId, err := getUintId(requestModel.UserId)
+
+	if err != nil {
+		return tokenModel, err
+	}
+
+	tokenModel = m.ActionToken{
+		Hash:      requestModel.Hash,
+		UserId:    uintUserId,
+		Action:    requestModel.Action,
+		ExpiresAt: requestModel.ExpiresAt,
+	}
+
+	return tokenModel, nil
+}
+
+/* getActionTokenRequest obtains the Request ActionToken model */
+func getActionTokenRequest(tokenModel m.ActionToken) mr.ActionToken {
+	requestModel := mr.ActionToken{
+		Hash:      tokenModel.Hash,
+		UserId:    strconv.FormatUint(tokenModel.UserId, 10),
+		Action:    tokenModel.Action,
+		ExpiresAt: tokenModel.ExpiresAt,
+	}
+
+	return requestModel
+}
+
+/* getLoginAttemptRequest obtains the Request LoginAttempt model */
+func getLoginAttemptRequest(attemptModel m.LoginAttempt) mr.LoginAttempt {
+	requestModel := mr.LoginAttempt{
+		Key:           attemptModel.Key,
+		Failures:      attemptModel.Failures,
+		LastFailureAt: attemptModel.LastFailureAt,
+		ExpiresAt:     attemptModel.ExpiresAt,
+	}
+
+	return requestModel
+}
+
+/* getAccessTokenModel obtains the DB AccessToken model */
+func getAccessTokenModel(requestModel mr.AccessToken) (m.AccessToken, error) {
+	var tokenModel m.AccessToken
+
+	uintUserId, err := getUintId(requestModel.UserId)
+
+	if err != nil {
+		return tokenModel, err
+	}
+
+	tokenModel = m.AccessToken{
+		UserId:    uintUserId,
+		Name:      requestModel.Name,
+		Hash:      requestModel.Hash,
+		Scopes:    strings.Join(requestModel.Scopes, " "),
+		CreatedAt: requestModel.CreatedAt,
+		ExpiresAt: requestModel.ExpiresAt,
+	}
+
+	return tokenModel, nil
+}
+
+/* getAccessTokenRequest obtains the Request AccessToken model */
+func getAccessTokenRequest(tokenModel m.AccessToken) mr.AccessToken {
+	requestModel := mr.AccessToken{
+		Id:        strconv.FormatUint(tokenModel.Id, 10),
+		UserId:    strconv.FormatUint(tokenModel.UserId, 10),
+		Name:      tokenModel.Name,
+		Hash:      tokenModel.Hash,
+		Scopes:    strings.Fields(tokenModel.Scopes),
+		CreatedAt: tokenModel.CreatedAt,
+		ExpiresAt: tokenModel.ExpiresAt,
+	}
+
+	return requestModel
+}
+
+/* getLikeModel obtains the DB Like model */
+func getLikeModel(requestModel mr.Like) (m.Like, error) {
+	var likeModel m.Like
+
+	uintUserId, err := getUintId(requestModel.UserId)
+
+	if err != nil {
+		return likeModel, err
+	}
+
+	uintTweetId, err := getUintId(requestModel.TweetId)
+
+	if err != nil {
+		return likeModel, err
+	}
+
+	likeModel = m.Like{
+		UserId:  uintUserId,
+		TweetId: uintTweetId,
+		Date:    requestModel.Date,
+	}
+
+	return likeModel, nil
+}
+
+/* getBookmarkModel obtains the DB Bookmark model */
+func getBookmarkModel(requestModel mr.Bookmark) (m.Bookmark, error) {
+	var bookmarkModel m.Bookmark
+
+	uintUserId, err := getUintId(requestModel.UserId)
+
+	if err != nil {
+		return bookmarkModel, err
+	}
+
+	uintTweetId, err := getUintId(requestModel.TweetId)
+
+	if err != nil {
+		return bookmarkModel, err
+	}
+
+	bookmarkModel = m.Bookmark{
+		UserId:  uintUserId,
+		TweetId: uintTweetId,
+		Date:    requestModel.Date,
+	}
+
+	return bookmarkModel, nil
+}
+
+/* getSessionModel obtains the DB Session model */
+func getSessionModel(requestModel mr.Session) (m.Session, error) {
+	var sessionModel m.Session
+
+	uintUserId, err := getUintId(requestModel.UserId)
+
+	if err != nil {
+		return sessionModel, err
+	}
+
+	sessionModel = m.Session{
+		UserId:     uintUserId,
+		UserAgent:  requestModel.UserAgent,
+		Ip:         requestModel.Ip,
+		CreatedAt:  requestModel.CreatedAt,
+		LastSeenAt: requestModel.LastSeenAt,
+		ExpiresAt:  requestModel.ExpiresAt,
+	}
+
+	return sessionModel, nil
+}
+
+/* getSessionRequest obtains the Request Session model */
+func getSessionRequest(sessionModel m.Session) mr.Session {
+	requestModel := mr.Session{
+		Id:         strconv.FormatUint(sessionModel.Id, 10),
+		UserId:     strconv.FormatUint(sessionModel.UserId, 10),
+		UserAgent:  sessionModel.UserAgent,
+		Ip:         sessionModel.Ip,
+		CreatedAt:  sessionModel.CreatedAt,
+		LastSeenAt: sessionModel.LastSeenAt,
+		ExpiresAt:  sessionModel.ExpiresAt,
+	}
+
+	return requestModel
+}
+
+/* getExportModel obtains the DB Export model */
+func getExportModel(requestModel mr.Export) (m.Export, error) {
+	var exportModel m.Export
+
+	uintUserId, err := getUintId(requestModel.UserId)
+
+	if err != nil {
+		return exportModel, err
+	}
+
+	exportModel = m.Export{
+		UserId:         uintUserId,
+		Status:         requestModel.Status,
+		Error:          requestModel.Error,
+		TokenHash:      requestModel.TokenHash,
+		TokenExpiresAt: requestModel.TokenExpiresAt,
+		CreatedAt:      requestModel.CreatedAt,
+		ExpiresAt:      requestModel.ExpiresAt,
+	}
+
+	return exportModel, nil
+}
+
+/* getExportRequest obtains the Request Export model */
+func getExportRequest(exportModel m.Export) mr.Export {
+	requestModel := mr.Export{
+		Id:             strconv.FormatUint(exportModel.Id, 10),
+		UserId:         strconv.FormatUint(exportModel.UserId, 10),
+		Status:         exportModel.Status,
+		Error:          exportModel.Error,
+		TokenHash:      exportModel.TokenHash,
+		TokenExpiresAt: exportModel.TokenExpiresAt,
+		CreatedAt:      exportModel.CreatedAt,
+		ExpiresAt:      exportModel.ExpiresAt,
+	}
+
+	return requestModel
+}
+
 // endregion
 
 // region "Helpers"
 
+func getUintIdPointer(id string) (*uint64, error) {
+	if len(id) < 1 {
+		return nil, nil
+	}
+
+	uintId, err := getUintId(id)
+
+	return &uintId, err
+}
+
+func getStringId(id *uint64) string {
+	if id == nil {
+		return ""
+	}
+
+	return strconv.FormatUint(*id, 10)
+}
+
 func getUintId(id string) (uint64, error) {
 	var uintId uint64
 	var err error
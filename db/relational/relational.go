@@ -8,14 +8,17 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"helpers"
+	"media"
 	m "models/relational"
 	mr "models/request"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/schema"
 )
 
@@ -23,6 +26,9 @@ type DbSql struct {
 	Connection *gorm.DB
 }
 
+/* tweetSearchVector is the expression of the full-text index of the tweets, the queries must use the same one */
+const tweetSearchVector = "to_tsvector('english', message)"
+
 // region "Connection"
 
 /* Connect connects to the database */
@@ -51,8 +57,26 @@ func (db *DbSql) Connect() error {
 	client.AutoMigrate(&m.User{})
 	client.AutoMigrate(&m.Relation{})
 	client.AutoMigrate(&m.Tweet{})
-
-	return nil
+	client.AutoMigrate(&m.RevokedToken{})
+	client.AutoMigrate(&m.ActionToken{})
+	client.AutoMigrate(&m.AccessToken{})
+	client.AutoMigrate(&m.Session{})
+	client.AutoMigrate(&m.LoginAttempt{})
+	client.AutoMigrate(&m.Like{})
+	client.AutoMigrate(&m.TweetRevision{})
+	client.AutoMigrate(&m.TweetHashtag{})
+	client.AutoMigrate(&m.TweetMention{})
+	client.AutoMigrate(&m.HashtagCount{})
+	client.AutoMigrate(&m.ScheduledTweet{})
+	client.AutoMigrate(&m.Draft{})
+	client.AutoMigrate(&m.Bookmark{})
+	client.AutoMigrate(&m.Export{})
+
+	// The full-text index is built on an expression, which the gorm tags cannot declare
+	client.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_tweets_search ON %s USING GIN (%s)", client.NamingStrategy.TableName("Tweet"), tweetSearchVector))
+
+	// The users registered before the handles were added can not be mentioned until they have one
+	return setMissingHandles(client)
 }
 
 /* IsConnection makes a ping to the Database */
@@ -114,6 +138,13 @@ func (db *DbSql) GetProfile(id string) (mr.User, bool, error) {
 /* InsertUser inserts an user into de DB */
 func (db *DbSql) InsertUser(user mr.User) (string, error) {
 	user.Password, _ = encryptPassword(user.Password)
+	handle, err := getAvailableHandle(db.Connection, helpers.GetHandle(user.Email))
+
+	if err != nil {
+		return "", err
+	}
+
+	user.Handle = handle
 	userModel, err := getUserModel(user)
 
 	if err != nil {
@@ -218,98 +249,98 @@ func (db *DbSql) ModifyRegistry(id string, user mr.User) error {
 	return nil
 }
 
-/* TryLogin makes the login to the DB */
-func (db *DbSql) TryLogin(email string, password string) (mr.User, bool) {
-	var requestModel mr.User
-
-	isFound, requestModel, err := db.IsUser(email)
+/* ModifyPassword encrypts and modifies the password of an user in the DB */
+func (db *DbSql) ModifyPassword(id string, password string) error {
+	userId, err := getUintId(id)
 
-	if err != nil || !isFound {
-		return requestModel, false
+	if err != nil {
+		return err
 	}
 
-	passwordBytes := []byte(password)
-	passwordDB := []byte(requestModel.Password)
-	err = bcrypt.CompareHashAndPassword(passwordDB, passwordBytes)
+	encryptedPassword, err := encryptPassword(password)
 
 	if err != nil {
-		return requestModel, false
+		return err
 	}
 
-	return requestModel, true
-}
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
-// endregion
+	defer cancel()
 
-// region "Tweets"
+	result := tx.WithContext(ctx).Model(&m.User{Id: userId}).Update("password", encryptedPassword)
+	err = result.Error
 
-/* DeleteTweet deletes a tweet in the DB */
-func (db *DbSql) DeleteTweet(id string, userId string) error {
-	err := db.deleteTweetLogical(id, userId)
+	if err != nil {
+		tx.Rollback()
+	} else {
+		tx.Commit()
+	}
 
 	return err
 }
 
-/* Get gets an user's tweets from the DB */
-func (db *DbSql) GetTweets(id string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
-	var results []*mr.Tweet
-	var tweetsDbResults []m.Tweet
-	var total int64
-
-	uintUserId, err := getUintId(id)
+/* VerifyUser marks the email address of an user as verified in the DB */
+func (db *DbSql) VerifyUser(id string) error {
+	userId, err := getUintId(id)
 
 	if err != nil {
-		return results, 0, err
+		return err
 	}
 
-	filter := &m.Tweet{UserId: uintUserId, Active: true}
-
-	ctxCount, cancelCount := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
-	defer cancelCount()
+	defer cancel()
 
-	result := db.Connection.WithContext(ctxCount).
-		Model(&m.Tweet{}).
-		Where(filter).
-		Count(&total)
+	result := tx.WithContext(ctx).Model(&m.User{Id: userId}).Update("verified", true)
 	err = result.Error
 
 	if err != nil {
-		return results, 0, err
+		tx.Rollback()
+	} else {
+		tx.Commit()
 	}
 
-	offset := int((page - 1) * limit)
-	limitInt := int(limit)
+	return err
+}
+
+/* RevokeUserTokens invalidates all the tokens issued to an user until now */
+func (db *DbSql) RevokeUserTokens(id string) error {
+	userId, err := getUintId(id)
+
+	if err != nil {
+		return err
+	}
+
+	tx := db.Connection.Begin()
 	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
 	defer cancel()
 
-	result = db.Connection.WithContext(ctx).
-		Where(filter).
-		Order("date desc").
-		Offset(offset).
-		Limit(limitInt).
-		Find(&tweetsDbResults)
+	result := tx.WithContext(ctx).Model(&m.User{Id: userId}).Update("tokens_revoked_at", time.Now())
 	err = result.Error
 
 	if err != nil {
-		return results, 0, err
-	}
-
-	for _, tweetModel := range tweetsDbResults {
-		tweetRequest := getTweetRequest(tweetModel)
-		results = append(results, &tweetRequest)
+		tx.Rollback()
+	} else {
+		tx.Commit()
 	}
 
-	return results, total, nil
+	return err
 }
 
-/* InsertTweet inserts a tweet in the DB */
-func (db *DbSql) InsertTweet(tweet mr.Tweet) (string, error) {
-	tweetModel, err := getTweetModel(tweet)
+/* SetTotp sets the two-factor authentication secret of an user in the DB */
+func (db *DbSql) SetTotp(id string, secret string, isEnabled bool) error {
+	userId, err := getUintId(id)
 
 	if err != nil {
-		return "", err
+		return err
+	}
+
+	registry := map[string]any{
+		"totp_secret":  secret,
+		"totp_enabled": isEnabled,
 	}
 
 	tx := db.Connection.Begin()
@@ -317,478 +348,2972 @@ func (db *DbSql) InsertTweet(tweet mr.Tweet) (string, error) {
 
 	defer cancel()
 
-	err = tx.WithContext(ctx).Model(&m.User{Id: tweetModel.UserId}).Association("Tweets").Append(&tweetModel)
+	result := tx.WithContext(ctx).Model(&m.User{Id: userId}).Updates(registry)
+	err = result.Error
 
 	if err != nil {
 		tx.Rollback()
-
-		return "", err
+	} else {
+		tx.Commit()
 	}
 
-	tx.Commit()
-
-	return strconv.FormatUint(tweetModel.Id, 10), nil
+	return err
 }
 
-// endregion
-
-// region "Relations"
+/* UseTotpCounter saves the counter of the last two-factor code accepted, it returns false if a code of that counter or a later one had already been used */
+func (db *DbSql) UseTotpCounter(id string, counter int64) (bool, error) {
+	userId, err := getUintId(id)
 
-/* IsRelation verifies if exist a relation in the DB */
-func (db *DbSql) IsRelation(relation mr.Relation) (bool, mr.Relation, error) {
-	var result mr.Relation
-	var relationModel m.Relation
+	if err != nil {
+		return false, err
+	}
 
+	tx := db.Connection.Begin()
 	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
 	defer cancel()
 
-	results := db.Connection.WithContext(ctx).
-		Where(map[string]string{"user_id": relation.UserId, "following_id": relation.UserRelationId}).
-		First(&relationModel)
-	err := results.Error
+	result := tx.WithContext(ctx).Model(&m.User{Id: userId}).
+		Where("totp_last_counter < ?", counter).
+		Update("totp_last_counter", counter)
 
-	if err != nil && err == gorm.ErrRecordNotFound {
-		return false, result, nil
+	if result.Error != nil {
+		tx.Rollback()
+
+		return false, result.Error
 	}
 
-	result = getRelationRequest(relationModel)
+	tx.Commit()
 
-	return true, result, err
+	return result.RowsAffected > 0, nil
 }
 
-/* InsertRelation creates a relation into the DB */
-func (db *DbSql) InsertRelation(relation mr.Relation) error {
-	_, isFound, err := db.GetProfile(relation.UserRelationId)
+/* SetRole sets the role of an user */
+func (db *DbSql) SetRole(id string, role string) error {
+	userId, err := getUintId(id)
 
 	if err != nil {
 		return err
 	}
 
-	if !isFound {
-		return errors.New("no registry found in the DB")
-	}
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
-	isFound, relationDb, err := db.IsRelation(relation)
+	defer cancel()
+
+	result := tx.WithContext(ctx).Model(&m.User{Id: userId}).Update("role", role)
+	err = result.Error
 
 	if err != nil {
-		return err
+		tx.Rollback()
+	} else {
+		tx.Commit()
 	}
 
-	if !isFound {
-		userId, _ := getUintId(relation.UserId)
-		userRelationId, err := getUintId(relation.UserRelationId)
-
-		if err != nil {
-			return err
-		}
-
-		tx := db.Connection.Begin()
-		ctxInsert, cancelInsert := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
-
-		defer cancelInsert()
+	return err
+}
 
-		err = tx.WithContext(ctxInsert).
-			Model(&m.User{Id: userId}).
-			Association("Following").
-			Append(&m.User{Id: userRelationId})
+/* ScheduleUserDeletion sets when an user will be deleted, a zero deleteAt cancels the deletion */
+func (db *DbSql) ScheduleUserDeletion(id string, deleteAt time.Time) error {
+	var value any
 
-		if err != nil {
-			tx.Rollback()
-		} else {
-			tx.Commit()
-		}
+	userId, err := getUintId(id)
 
+	if err != nil {
 		return err
 	}
 
-	if relationDb.Active {
-		return fmt.Errorf("the relation with the user id = %s already exists", relation.UserRelationId)
+	if !deleteAt.IsZero() {
+		value = deleteAt
 	}
 
-	err = db.updateRelation(relation, true)
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
-	return err
-}
+	defer cancel()
 
-/* DeleteRelation deletes a relation in the DB */
-func (db *DbSql) DeleteRelation(relation mr.Relation) error {
-	err := db.updateRelation(relation, false)
+	result := tx.WithContext(ctx).Model(&m.User{Id: userId}).Update("delete_at", value)
+	err = result.Error
+
+	if err != nil {
+		tx.Rollback()
+	} else {
+		tx.Commit()
+	}
 
 	return err
 }
 
-/* GetUsers gets an user's following or not following list */
-func (db *DbSql) GetUsers(id string, page int64, limit int64, search string, searchType string) ([]*mr.User, int64, error) {
+/* GetUsersToDelete gets the users with a deletion scheduled before a time */
+func (db *DbSql) GetUsersToDelete(before time.Time) ([]*mr.User, error) {
 	var results []*mr.User
-	var users []m.User
-	var total int64
+	var usersDbResults []*m.User
 
-	offset := int((page - 1) * limit)
-	limitInt := int(limit)
-	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
-	defer cancelFind()
+	defer cancel()
 
-	result := db.Connection.WithContext(ctxFind).
-		Where("id <> ? AND lower(name) LIKE ?", id, "%"+strings.ToLower(search)+"%").
-		Order("birth_date desc").Offset(offset).
-		Limit(limitInt).
-		Find(&users)
-	err := result.Error
+	result := db.Connection.WithContext(ctx).
+		Where("delete_at IS NOT NULL AND delete_at <= ?", before).
+		Find(&usersDbResults)
 
-	if err != nil {
-		return results, total, err
+	if result.Error != nil {
+		return results, result.Error
 	}
 
-	var include, isRelation bool
+	for _, userModel := range usersDbResults {
+		userRequest := getUserRequest(*userModel)
+		results = append(results, &userRequest)
+	}
 
-	for _, user := range users {
-		isRelation = false
-		include = false
+	return results, nil
+}
 
-		userRequest := getUserRequest(user)
+/* DeleteUser deletes an user with all its data */
+func (db *DbSql) DeleteUser(id string) error {
+	userId, err := getUintId(id)
 
-		relationRequest := mr.Relation{
-			UserId:         id,
-			UserRelationId: userRequest.Id,
-			Active:         true,
-		}
+	if err != nil {
+		return err
+	}
 
-		isFound, relationDb, err := db.IsRelation(relationRequest)
+	if userId == 0 {
+		return errors.New("invalid id param")
+	}
 
-		if err != nil {
-			return results, total, err
-		}
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
-		if isFound {
-			isRelation = relationDb.Active
-		}
+	defer cancel()
 
-		if relationRequest.UserRelationId == id {
-			include = false
-		} else if (searchType == "new" && !isRelation) || (searchType == "follow" && isRelation) {
-			include = true
-		}
+	err = revertUserTweetCounters(tx.WithContext(ctx), userId)
 
-		if include {
-			userRequest.Email = ""
-			userRequest.Password = ""
-			userRequest.Avatar = ""
-			userRequest.Banner = ""
-			userRequest.Biography = ""
-			userRequest.Location = ""
-			userRequest.WebSite = ""
+	if err != nil {
+		tx.Rollback()
 
-			results = append(results, &userRequest)
-		}
+		return err
 	}
 
-	// This total only reflects the total returned registries
-	total = int64(len(results))
+	// The counts of the tweets liked by the user are kept consistent
+	err = tx.WithContext(ctx).
+		Model(&m.Tweet{}).
+		Where("id IN (?)", tx.Model(&m.Like{}).Select("tweet_id").Where("user_id = ?", userId)).
+		Update("like_count", gorm.Expr("like_count - ?", 1)).Error
 
-	return results, total, nil
-}
+	if err != nil {
+		tx.Rollback()
 
-/* GetFollowing gets an user's following list */
-func (db *DbSql) GetFollowing(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
-	var results []*mr.User
-	var user m.User
+		return err
+	}
+
+	// The mentions of the user in other tweets are left as plain text
+	err = tx.WithContext(ctx).Where("user_id = ?", userId).Delete(&m.TweetMention{}).Error
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	// The revisions, hashtags and mentions of the tweets are deleted with them
+	for _, model := range []any{&m.TweetRevision{}, &m.TweetHashtag{}, &m.TweetMention{}} {
+		err = tx.WithContext(ctx).
+			Where("tweet_id IN (?)", tx.Model(&m.Tweet{}).Select("id").Where("user_id = ?", userId)).
+			Delete(model).Error
+
+		if err != nil {
+			tx.Rollback()
+
+			return err
+		}
+	}
+
+	// The likes given by the user and the ones of its tweets are deleted with it
+	err = tx.WithContext(ctx).
+		Where("user_id = ? OR tweet_id IN (?)", userId, tx.Model(&m.Tweet{}).Select("id").Where("user_id = ?", userId)).
+		Delete(&m.Like{}).Error
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	// The relations in both directions are deleted with the user
+	err = tx.WithContext(ctx).
+		Where("user_id = ? OR following_id = ?", userId, userId).
+		Delete(&m.Relation{}).Error
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	for _, model := range []any{&m.Tweet{}, &m.RevokedToken{}, &m.ActionToken{}, &m.AccessToken{}, &m.Session{}, &m.ScheduledTweet{}, &m.Draft{}, &m.Bookmark{}} {
+		err = tx.WithContext(ctx).Where("user_id = ?", userId).Delete(model).Error
+
+		if err != nil {
+			tx.Rollback()
+
+			return err
+		}
+	}
+
+	err = tx.WithContext(ctx).Delete(&m.User{}, userId).Error
+
+	if err != nil {
+		tx.Rollback()
+	} else {
+		tx.Commit()
+	}
+
+	return err
+}
+
+/* TryLogin makes the login to the DB */
+func (db *DbSql) TryLogin(email string, password string) (mr.User, bool) {
+	var requestModel mr.User
+
+	isFound, requestModel, err := db.IsUser(email)
+
+	if err != nil || !isFound {
+		return requestModel, false
+	}
+
+	passwordBytes := []byte(password)
+	passwordDB := []byte(requestModel.Password)
+	err = bcrypt.CompareHashAndPassword(passwordDB, passwordBytes)
+
+	if err != nil {
+		return requestModel, false
+	}
+
+	// The password is encrypted again when the configured cost has been increased
+	cost, err := bcrypt.Cost(passwordDB)
+
+	if err == nil && cost < helpers.GetBcryptCost() {
+		err = db.ModifyPassword(requestModel.Id, password)
+
+		if err != nil {
+			log.Println("Error encrypting the password again: " + err.Error())
+		}
+	}
+
+	return requestModel, true
+}
+
+// endregion
+
+// region "Tweets"
+
+/* DeleteTweet deletes a tweet in the DB */
+func (db *DbSql) DeleteTweet(id string, userId string) error {
+	err := db.deleteTweetLogical(id, userId)
+
+	return err
+}
+
+/* Get gets an user's tweets from the DB */
+func (db *DbSql) GetTweets(id string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	var results []*mr.Tweet
+	var tweetsDbResults []m.Tweet
 	var total int64
 
+	uintUserId, err := getUintId(id)
+
+	if err != nil {
+		return results, 0, err
+	}
+
+	filter := &m.Tweet{UserId: uintUserId, Active: true}
+
 	ctxCount, cancelCount := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
 	defer cancelCount()
 
-	filter := "lower(name) LIKE ?"
-	condition := "%" + strings.ToLower(search) + "%"
 	result := db.Connection.WithContext(ctxCount).
-		Preload("Following", filter, condition).
-		First(&user, id)
-	err := result.Error
+		Model(&m.Tweet{}).
+		Where(filter).
+		Count(&total)
+	err = result.Error
 
 	if err != nil {
-		return results, total, err
+		return results, 0, err
 	}
 
-	total = int64(len(user.Following))
-
 	offset := int((page - 1) * limit)
 	limitInt := int(limit)
-	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
-	defer cancelFind()
+	defer cancel()
 
-	result = db.Connection.WithContext(ctxFind).
-		Preload("Following", func(db *gorm.DB) *gorm.DB {
-			return db.Where(filter, condition).
-				Order("birth_date desc").
-				Offset(offset).
-				Limit(limitInt).
-				Select("id", "name", "last_name", "birth_date")
-		}).First(&user, id)
+	result = db.Connection.WithContext(ctx).
+		Where(filter).
+		Order("date desc").
+		Offset(offset).
+		Limit(limitInt).
+		Find(&tweetsDbResults)
 	err = result.Error
 
-	if err == nil {
-		for _, userModel := range user.Following {
-			userRequest := getUserRequest(userModel)
-			results = append(results, &userRequest)
+	if err != nil {
+		return results, 0, err
+	}
+
+	for _, tweetModel := range tweetsDbResults {
+		tweetRequest := getTweetRequest(tweetModel)
+		results = append(results, &tweetRequest)
+	}
+
+	return results, total, nil
+}
+
+/* GetUserTweets gets all the user's tweets, including the deleted ones */
+func (db *DbSql) GetUserTweets(id string) ([]*mr.Tweet, error) {
+	var results []*mr.Tweet
+	var tweetsDbResults []m.Tweet
+
+	uintUserId, err := getUintId(id)
+
+	if err != nil {
+		return results, err
+	}
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := db.Connection.WithContext(ctx).
+		Where("user_id = ?", uintUserId).
+		Order("date desc").
+		Find(&tweetsDbResults)
+
+	if result.Error != nil {
+		return results, result.Error
+	}
+
+	for _, tweetModel := range tweetsDbResults {
+		tweetRequest := getTweetRequest(tweetModel)
+		results = append(results, &tweetRequest)
+	}
+
+	return results, nil
+}
+
+/* InsertTweet inserts a tweet in the DB */
+func (db *DbSql) InsertTweet(tweet mr.Tweet) (string, error) {
+	tweetModel, err := getTweetModel(tweet)
+
+	if err != nil {
+		return "", err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err = insertTweet(tx.WithContext(ctx), &tweetModel)
+
+	if err != nil {
+		tx.Rollback()
+
+		return "", err
+	}
+
+	tx.Commit()
+
+	return strconv.FormatUint(tweetModel.Id, 10), nil
+}
+
+/* InsertRetweet inserts a retweet in the DB, it returns false if the user had already retweeted the tweet */
+func (db *DbSql) InsertRetweet(tweet mr.Tweet) (string, bool, error) {
+	var total int64
+
+	tweetModel, err := getTweetModel(tweet)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	// The unique index rejects the concurrent retweets that pass this check
+	err = tx.WithContext(ctx).
+		Model(&m.Tweet{}).
+		Where("user_id = ? AND retweet_of = ? AND active = ?", tweetModel.UserId, tweetModel.RetweetOf, true).
+		Count(&total).Error
+
+	if err == nil && total < 1 {
+		err = insertTweet(tx.WithContext(ctx), &tweetModel)
+	}
+
+	if err != nil || total > 0 {
+		tx.Rollback()
+
+		return "", false, err
+	}
+
+	tx.Commit()
+
+	return strconv.FormatUint(tweetModel.Id, 10), true, nil
+}
+
+/* DeleteRetweet deletes the user's retweet of a tweet, it returns false if there was not any */
+func (db *DbSql) DeleteRetweet(userId string, tweetId string) (bool, error) {
+	var tweetModel m.Tweet
+
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return false, err
+	}
+
+	uintTweetId, err := getUintId(tweetId)
+
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := db.Connection.WithContext(ctx).
+		Where("user_id = ? AND retweet_of = ? AND active = ?", uintUserId, uintTweetId, true).
+		Limit(1).
+		Find(&tweetModel)
+
+	if result.Error != nil || result.RowsAffected < 1 {
+		return false, result.Error
+	}
+
+	err = db.deleteTweetLogical(strconv.FormatUint(tweetModel.Id, 10), userId)
+
+	return err == nil, err
+}
+
+/* GetTweet gets a tweet, including a deleted one */
+func (db *DbSql) GetTweet(id string) (mr.Tweet, bool, error) {
+	var tweetRequest mr.Tweet
+	var tweetModel m.Tweet
+
+	uintId, err := getUintId(id)
+
+	if err != nil {
+		return tweetRequest, false, err
+	}
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := db.Connection.WithContext(ctx).Where("id = ?", uintId).Limit(1).Find(&tweetModel)
+
+	if result.Error != nil || result.RowsAffected < 1 {
+		return tweetRequest, false, result.Error
+	}
+
+	tweetRequest = getTweetRequest(tweetModel)
+
+	return tweetRequest, true, nil
+}
+
+/* GetReplies gets the direct replies to a tweet */
+func (db *DbSql) GetReplies(id string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	uintId, err := getUintId(id)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return db.findTweets(page, limit, false, "in_reply_to = ? AND active = ?", uintId, true)
+}
+
+/* GetConversation gets all the tweets of a conversation, including the deleted ones, ordered by date */
+func (db *DbSql) GetConversation(id string) ([]*mr.Tweet, error) {
+	uintId, err := getUintId(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	results, _, err := db.findTweets(0, 0, false, "id = ? OR conversation_id = ?", uintId, uintId)
+
+	return results, err
+}
+
+/* ModifyTweet changes the message of an user's tweet keeping the previous one as a revision, it returns false if the tweet is not found */
+func (db *DbSql) ModifyTweet(tweet mr.Tweet) (bool, error) {
+	var tweetModel m.Tweet
+
+	uintId, err := getUintId(tweet.Id)
+
+	if err != nil {
+		return false, err
+	}
+
+	uintUserId, err := getUintId(tweet.UserId)
+
+	if err != nil {
+		return false, err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	// The row is locked, so a concurrent edit cannot lose a revision
+	result := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ? AND active = ?", uintId, uintUserId, true).
+		Limit(1).
+		Find(&tweetModel)
+
+	if result.Error != nil || result.RowsAffected < 1 {
+		tx.Rollback()
+
+		return false, result.Error
+	}
+
+	revisionModel := getTweetRevisionModel(tweetModel)
+	hashtags := helpers.GetHashtags(tweet.Message)
+	err = tx.WithContext(ctx).Create(&revisionModel).Error
+
+	// The hashtags of the previous message stop counting in the trends
+	if err == nil {
+		err = updateHashtagCounts(tx.WithContext(ctx), helpers.GetHashtags(tweetModel.Message), tweetModel.Date, -1)
+	}
+
+	if err == nil {
+		err = updateHashtagCounts(tx.WithContext(ctx), hashtags, tweetModel.Date, 1)
+	}
+
+	if err == nil {
+		err = setTweetHashtags(tx.WithContext(ctx), tweetModel, hashtags)
+	}
+
+	if err == nil {
+		err = tx.WithContext(ctx).
+			Model(&tweetModel).
+			Updates(map[string]any{"message": tweet.Message, "edited_at": tweet.EditedAt}).Error
+	}
+
+	if err == nil {
+		tweetModel.Message = tweet.Message
+		err = setTweetMentions(tx.WithContext(ctx), tweetModel)
+	}
+
+	if err != nil {
+		tx.Rollback()
+
+		return false, err
+	}
+
+	tx.Commit()
+
+	return true, nil
+}
+
+/* GetTweetRevisions gets the previous messages of an edited tweet, the oldest first */
+func (db *DbSql) GetTweetRevisions(id string) ([]*mr.TweetRevision, error) {
+	var results []*mr.TweetRevision
+	var revisionsDbResults []m.TweetRevision
+
+	uintId, err := getUintId(id)
+
+	if err != nil {
+		return results, err
+	}
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err = db.Connection.WithContext(ctx).
+		Where("tweet_id = ?", uintId).
+		Order("date asc").
+		Find(&revisionsDbResults).Error
+
+	if err != nil {
+		return results, err
+	}
+
+	for _, revisionModel := range revisionsDbResults {
+		revisionRequest := getTweetRevisionRequest(revisionModel)
+		results = append(results, &revisionRequest)
+	}
+
+	return results, nil
+}
+
+/* GetHashtagTweets gets the tweets with a hashtag, the newest first */
+func (db *DbSql) GetHashtagTweets(tag string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	tweetIds := db.Connection.Model(&m.TweetHashtag{}).Select("tweet_id").Where("tag = ?", tag)
+
+	return db.findTweets(page, limit, true, "id IN (?) AND active = ?", tweetIds, true)
+}
+
+/* GetMentionTweets gets the tweets that mention an user, the newest first */
+func (db *DbSql) GetMentionTweets(userId string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tweetIds := db.Connection.Model(&m.TweetMention{}).Select("tweet_id").Where("user_id = ?", uintUserId)
+
+	return db.findTweets(page, limit, true, "id IN (?) AND active = ?", tweetIds, true)
+}
+
+/* SearchTweets gets the tweets with any of the terms, by relevance or the newest first */
+func (db *DbSql) SearchTweets(terms []string, isNewestFirst bool, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	// The terms only have letters and digits, so they can be joined as the alternatives of a tsquery
+	tsQuery := strings.Join(terms, " | ")
+	condition := fmt.Sprintf("active = ? AND %s @@ to_tsquery('english', ?)", tweetSearchVector)
+	order := clause.OrderBy{
+		Columns: []clause.OrderByColumn{{Column: clause.Column{Name: "date"}, Desc: true}},
+	}
+
+	if !isNewestFirst {
+		order = clause.OrderBy{Expression: clause.Expr{
+			SQL:                fmt.Sprintf("ts_rank(%s, to_tsquery('english', ?)) desc, date desc", tweetSearchVector),
+			Vars:               []any{tsQuery},
+			WithoutParentheses: true,
+		}}
+	}
+
+	return db.findSortedTweets(page, limit, order, condition, true, tsQuery)
+}
+
+/* GetTrends gets the most used hashtags since a date, the counts are kept by time bucket when the tweets are written */
+func (db *DbSql) GetTrends(since time.Time, limit int64) ([]*mr.Trend, error) {
+	var results []*mr.Trend
+	var countsDbResults []m.HashtagCount
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err := db.Connection.WithContext(ctx).
+		Model(&m.HashtagCount{}).
+		Select("tag", "SUM(count) AS count").
+		Where("bucket >= ?", helpers.GetTrendsBucket(since)).
+		Group("tag").
+		Having("SUM(count) > 0").
+		Order("count desc, tag asc").
+		Limit(int(limit)).
+		Find(&countsDbResults).Error
+
+	if err != nil {
+		return results, err
+	}
+
+	for _, countModel := range countsDbResults {
+		trendRequest := getTrendRequest(countModel)
+		results = append(results, &trendRequest)
+	}
+
+	return results, nil
+}
+
+// endregion
+
+// region "Likes"
+
+/* InsertLike adds an user's like to a tweet, it returns false if the user had already liked it */
+func (db *DbSql) InsertLike(like mr.Like) (bool, error) {
+	likeModel, err := getLikeModel(like)
+
+	if err != nil {
+		return false, err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	// The unique index only allows a like of a tweet by user
+	result := tx.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&likeModel)
+
+	if result.Error != nil || result.RowsAffected < 1 {
+		tx.Rollback()
+
+		return false, result.Error
+	}
+
+	// The count is kept in the tweet, so it is not counted each time the tweet is read
+	err = tx.WithContext(ctx).
+		Model(&m.Tweet{Id: likeModel.TweetId}).
+		Update("like_count", gorm.Expr("like_count + ?", 1)).Error
+
+	if err != nil {
+		tx.Rollback()
+
+		return false, err
+	}
+
+	tx.Commit()
+
+	return true, nil
+}
+
+/* DeleteLike removes an user's like from a tweet, it returns false if the user had not liked it */
+func (db *DbSql) DeleteLike(userId string, tweetId string) (bool, error) {
+	likeModel, err := getLikeModel(mr.Like{UserId: userId, TweetId: tweetId})
+
+	if err != nil {
+		return false, err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := tx.WithContext(ctx).
+		Where("user_id = ? AND tweet_id = ?", likeModel.UserId, likeModel.TweetId).
+		Delete(&m.Like{})
+
+	if result.Error != nil || result.RowsAffected < 1 {
+		tx.Rollback()
+
+		return false, result.Error
+	}
+
+	err = tx.WithContext(ctx).
+		Model(&m.Tweet{Id: likeModel.TweetId}).
+		Update("like_count", gorm.Expr("like_count - ?", 1)).Error
+
+	if err != nil {
+		tx.Rollback()
+
+		return false, err
+	}
+
+	tx.Commit()
+
+	return true, nil
+}
+
+/* GetLikedTweetIds returns which of the tweets are liked by an user */
+func (db *DbSql) GetLikedTweetIds(userId string, tweetIds []string) ([]string, error) {
+	var results []string
+	var likedIds []uint64
+
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return results, err
+	}
+
+	uintTweetIds := make([]uint64, 0, len(tweetIds))
+
+	for _, tweetId := range tweetIds {
+		uintTweetId, err := getUintId(tweetId)
+
+		if err != nil {
+			return results, err
+		}
+
+		uintTweetIds = append(uintTweetIds, uintTweetId)
+	}
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err = db.Connection.WithContext(ctx).
+		Model(&m.Like{}).
+		Where("user_id = ? AND tweet_id IN ?", uintUserId, uintTweetIds).
+		Pluck("tweet_id", &likedIds).Error
+
+	if err != nil {
+		return results, err
+	}
+
+	for _, likedId := range likedIds {
+		results = append(results, strconv.FormatUint(likedId, 10))
+	}
+
+	return results, nil
+}
+
+/* GetLikedTweets gets the tweets liked by an user, the last liked first */
+func (db *DbSql) GetLikedTweets(userId string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	var results []*mr.Tweet
+	var tweetsDbResults []m.Tweet
+	var total int64
+
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return results, total, err
+	}
+
+	query := func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&m.Tweet{}).
+			Joins("JOIN likes ON likes.tweet_id = tweets.id").
+			Where("likes.user_id = ? AND tweets.active = ?", uintUserId, true)
+	}
+
+	ctxCount, cancelCount := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelCount()
+
+	result := query(db.Connection.WithContext(ctxCount)).Count(&total)
+
+	if result.Error != nil {
+		return results, total, result.Error
+	}
+
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	result = query(db.Connection.WithContext(ctxFind)).
+		Select("tweets.*").
+		Order("likes.date desc").
+		Offset(int((page - 1) * limit)).
+		Limit(int(limit)).
+		Find(&tweetsDbResults)
+
+	if result.Error != nil {
+		return results, total, result.Error
+	}
+
+	for _, tweetModel := range tweetsDbResults {
+		tweetRequest := getTweetRequest(tweetModel)
+		results = append(results, &tweetRequest)
+	}
+
+	return results, total, nil
+}
+
+// endregion
+
+// region "Bookmarks"
+
+/* InsertBookmark saves a tweet in the user's bookmarks, it returns false if the user had already bookmarked it */
+func (db *DbSql) InsertBookmark(bookmark mr.Bookmark) (bool, error) {
+	bookmarkModel, err := getBookmarkModel(bookmark)
+
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	// The unique index only allows a bookmark of a tweet by user
+	result := db.Connection.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&bookmarkModel)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+/* DeleteBookmark removes a tweet from the user's bookmarks, it returns false if the user had not bookmarked it */
+func (db *DbSql) DeleteBookmark(userId string, tweetId string) (bool, error) {
+	bookmarkModel, err := getBookmarkModel(mr.Bookmark{UserId: userId, TweetId: tweetId})
+
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := db.Connection.WithContext(ctx).
+		Where("user_id = ? AND tweet_id = ?", bookmarkModel.UserId, bookmarkModel.TweetId).
+		Delete(&m.Bookmark{})
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+/* GetBookmarkedTweets gets the tweets bookmarked by an user, the last bookmarked first. The tweets deleted after being bookmarked are returned as inactive */
+func (db *DbSql) GetBookmarkedTweets(userId string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	var results []*mr.Tweet
+	var bookmarksDbResults []m.Bookmark
+	var tweetsDbResults []m.Tweet
+	var total int64
+
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return results, total, err
+	}
+
+	query := func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&m.Bookmark{}).Where("user_id = ?", uintUserId)
+	}
+
+	ctxCount, cancelCount := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelCount()
+
+	result := query(db.Connection.WithContext(ctxCount)).Count(&total)
+
+	if result.Error != nil {
+		return results, total, result.Error
+	}
+
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	result = query(db.Connection.WithContext(ctxFind)).
+		Order("date desc").
+		Offset(int((page - 1) * limit)).
+		Limit(int(limit)).
+		Find(&bookmarksDbResults)
+
+	if result.Error != nil || len(bookmarksDbResults) < 1 {
+		return results, total, result.Error
+	}
+
+	tweetIds := make([]uint64, 0, len(bookmarksDbResults))
+
+	for _, bookmarkModel := range bookmarksDbResults {
+		tweetIds = append(tweetIds, bookmarkModel.TweetId)
+	}
+
+	ctxTweets, cancelTweets := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelTweets()
+
+	result = db.Connection.WithContext(ctxTweets).Where("id IN ?", tweetIds).Find(&tweetsDbResults)
+
+	if result.Error != nil {
+		return results, total, result.Error
+	}
+
+	tweets := make(map[uint64]m.Tweet, len(tweetsDbResults))
+
+	for _, tweetModel := range tweetsDbResults {
+		tweets[tweetModel.Id] = tweetModel
+	}
+
+	// The tweets removed with their author are kept as inactive ones, so they are not silently lost
+	for _, bookmarkModel := range bookmarksDbResults {
+		tweetModel, isFound := tweets[bookmarkModel.TweetId]
+
+		if !isFound {
+			tweetModel = m.Tweet{Id: bookmarkModel.TweetId}
+		}
+
+		tweetRequest := getTweetRequest(tweetModel)
+		results = append(results, &tweetRequest)
+	}
+
+	return results, total, nil
+}
+
+// endregion
+
+// region "Scheduled tweets"
+
+/* InsertScheduledTweet inserts a tweet to be published later in the DB */
+func (db *DbSql) InsertScheduledTweet(tweet mr.ScheduledTweet) (string, error) {
+	scheduledModel, err := getScheduledTweetModel(tweet)
+
+	if err != nil {
+		return "", err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := tx.WithContext(ctx).Create(&scheduledModel)
+	err = result.Error
+
+	if err != nil {
+		tx.Rollback()
+
+		return "", err
+	}
+
+	tx.Commit()
+
+	return strconv.FormatUint(scheduledModel.Id, 10), nil
+}
+
+/* GetScheduledTweets gets the tweets scheduled by an user, the next to be published first */
+func (db *DbSql) GetScheduledTweets(userId string, page int64, limit int64) ([]*mr.ScheduledTweet, int64, error) {
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return db.findScheduledTweets(page, limit, "user_id = ?", uintUserId)
+}
+
+/* GetDueScheduledTweets gets the scheduled tweets whose publication date has been reached, the oldest first */
+func (db *DbSql) GetDueScheduledTweets(before time.Time, limit int64) ([]*mr.ScheduledTweet, error) {
+	results, _, err := db.findScheduledTweets(1, limit, "publish_at <= ?", before)
+
+	return results, err
+}
+
+/* DeleteScheduledTweet deletes a tweet scheduled by an user, it returns the deleted tweet */
+func (db *DbSql) DeleteScheduledTweet(id string, userId string) (mr.ScheduledTweet, bool, error) {
+	var scheduledModel m.ScheduledTweet
+	var scheduledRequest mr.ScheduledTweet
+
+	uintId, err := getUintId(id)
+
+	if err != nil {
+		return scheduledRequest, false, err
+	}
+
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return scheduledRequest, false, err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := tx.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND user_id = ?", uintId, uintUserId).
+		Delete(&scheduledModel)
+
+	if result.Error != nil {
+		tx.Rollback()
+
+		return scheduledRequest, false, result.Error
+	}
+
+	tx.Commit()
+
+	if result.RowsAffected < 1 {
+		return scheduledRequest, false, nil
+	}
+
+	scheduledRequest = getScheduledTweetRequest(scheduledModel)
+
+	return scheduledRequest, true, nil
+}
+
+/* PublishScheduledTweet inserts the tweet of a scheduled one and deletes it, it returns false if it was already published or cancelled */
+func (db *DbSql) PublishScheduledTweet(id string, tweet mr.Tweet) (string, bool, error) {
+	uintId, err := getUintId(id)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	tweetModel, err := getTweetModel(tweet)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	// The row lock of the delete makes the other publishers wait, and then they find nothing to publish
+	result := tx.WithContext(ctx).Delete(&m.ScheduledTweet{}, uintId)
+
+	if result.Error != nil || result.RowsAffected < 1 {
+		tx.Rollback()
+
+		return "", false, result.Error
+	}
+
+	err = insertTweet(tx.WithContext(ctx), &tweetModel)
+
+	if err != nil {
+		tx.Rollback()
+
+		return "", false, err
+	}
+
+	tx.Commit()
+
+	return strconv.FormatUint(tweetModel.Id, 10), true, nil
+}
+
+// endregion
+
+// region "Drafts"
+
+/* InsertDraft inserts a draft of a tweet in the DB */
+func (db *DbSql) InsertDraft(draft mr.Draft) (string, error) {
+	draftModel, err := getDraftModel(draft)
+
+	if err != nil {
+		return "", err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := tx.WithContext(ctx).Create(&draftModel)
+	err = result.Error
+
+	if err != nil {
+		tx.Rollback()
+
+		return "", err
+	}
+
+	tx.Commit()
+
+	return strconv.FormatUint(draftModel.Id, 10), nil
+}
+
+/* GetDrafts gets the drafts of an user, the last saved first */
+func (db *DbSql) GetDrafts(userId string, page int64, limit int64) ([]*mr.Draft, int64, error) {
+	var results []*mr.Draft
+	var draftsDbResults []m.Draft
+	var total int64
+
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return results, total, err
+	}
+
+	ctxCount, cancelCount := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelCount()
+
+	result := db.Connection.WithContext(ctxCount).
+		Model(&m.Draft{}).
+		Where("user_id = ?", uintUserId).
+		Count(&total)
+
+	if result.Error != nil {
+		return results, total, result.Error
+	}
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	query := db.Connection.WithContext(ctx).
+		Where("user_id = ?", uintUserId).
+		Order("date desc")
+
+	// Without a limit all the drafts are returned
+	if limit > 0 {
+		query = query.Offset(int((page - 1) * limit)).Limit(int(limit))
+	}
+
+	result = query.Find(&draftsDbResults)
+
+	if result.Error != nil {
+		return results, total, result.Error
+	}
+
+	for _, draftModel := range draftsDbResults {
+		draftRequest := getDraftRequest(draftModel)
+		results = append(results, &draftRequest)
+	}
+
+	return results, total, nil
+}
+
+/* GetDraft gets a draft of an user */
+func (db *DbSql) GetDraft(id string, userId string) (mr.Draft, bool, error) {
+	var draftModel m.Draft
+	var draftRequest mr.Draft
+
+	uintId, err := getUintId(id)
+
+	if err != nil {
+		return draftRequest, false, err
+	}
+
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return draftRequest, false, err
+	}
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := db.Connection.WithContext(ctx).
+		Where("id = ? AND user_id = ?", uintId, uintUserId).
+		Limit(1).
+		Find(&draftModel)
+
+	if result.Error != nil {
+		return draftRequest, false, result.Error
+	}
+
+	if result.RowsAffected < 1 {
+		return draftRequest, false, nil
+	}
+
+	draftRequest = getDraftRequest(draftModel)
+
+	return draftRequest, true, nil
+}
+
+/* ModifyDraft replaces the content of a draft of an user, it returns false if the draft does not exist */
+func (db *DbSql) ModifyDraft(draft mr.Draft) (bool, error) {
+	draftModel, err := getDraftModel(draft)
+
+	if err != nil {
+		return false, err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	// The selected columns are also updated when they are empty, so a reference can be removed
+	result := tx.WithContext(ctx).
+		Model(&m.Draft{}).
+		Where("id = ? AND user_id = ?", draftModel.Id, draftModel.UserId).
+		Select("message", "in_reply_to", "quoted_tweet_id", "media", "date").
+		Updates(&draftModel)
+
+	if result.Error != nil {
+		tx.Rollback()
+
+		return false, result.Error
+	}
+
+	tx.Commit()
+
+	return result.RowsAffected > 0, nil
+}
+
+/* DeleteDraft deletes a draft of an user, it returns the deleted draft */
+func (db *DbSql) DeleteDraft(id string, userId string) (mr.Draft, bool, error) {
+	var draftModel m.Draft
+	var draftRequest mr.Draft
+
+	uintId, err := getUintId(id)
+
+	if err != nil {
+		return draftRequest, false, err
+	}
+
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return draftRequest, false, err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := tx.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND user_id = ?", uintId, uintUserId).
+		Delete(&draftModel)
+
+	if result.Error != nil {
+		tx.Rollback()
+
+		return draftRequest, false, result.Error
+	}
+
+	tx.Commit()
+
+	if result.RowsAffected < 1 {
+		return draftRequest, false, nil
+	}
+
+	draftRequest = getDraftRequest(draftModel)
+
+	return draftRequest, true, nil
+}
+
+/* PublishDraft inserts the tweet of a draft and deletes it, it returns false if the draft does not exist anymore */
+func (db *DbSql) PublishDraft(id string, userId string, tweet mr.Tweet) (string, bool, error) {
+	uintId, err := getUintId(id)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	tweetModel, err := getTweetModel(tweet)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	// The row lock of the delete makes a concurrent publication wait, and then it finds nothing to publish
+	result := tx.WithContext(ctx).
+		Where("id = ? AND user_id = ?", uintId, uintUserId).
+		Delete(&m.Draft{})
+
+	if result.Error != nil || result.RowsAffected < 1 {
+		tx.Rollback()
+
+		return "", false, result.Error
+	}
+
+	err = insertTweet(tx.WithContext(ctx), &tweetModel)
+
+	if err != nil {
+		tx.Rollback()
+
+		return "", false, err
+	}
+
+	tx.Commit()
+
+	return strconv.FormatUint(tweetModel.Id, 10), true, nil
+}
+
+// endregion
+
+// region "Relations"
+
+/* IsRelation verifies if exist a relation in the DB */
+func (db *DbSql) IsRelation(relation mr.Relation) (bool, mr.Relation, error) {
+	var result mr.Relation
+	var relationModel m.Relation
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	results := db.Connection.WithContext(ctx).
+		Where(map[string]string{"user_id": relation.UserId, "following_id": relation.UserRelationId}).
+		First(&relationModel)
+	err := results.Error
+
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return false, result, nil
+	}
+
+	result = getRelationRequest(relationModel)
+
+	return true, result, err
+}
+
+/* InsertRelation creates a relation into the DB */
+func (db *DbSql) InsertRelation(relation mr.Relation) error {
+	_, isFound, err := db.GetProfile(relation.UserRelationId)
+
+	if err != nil {
+		return err
+	}
+
+	if !isFound {
+		return errors.New("no registry found in the DB")
+	}
+
+	isFound, relationDb, err := db.IsRelation(relation)
+
+	if err != nil {
+		return err
+	}
+
+	if !isFound {
+		userId, _ := getUintId(relation.UserId)
+		userRelationId, err := getUintId(relation.UserRelationId)
+
+		if err != nil {
+			return err
+		}
+
+		tx := db.Connection.Begin()
+		ctxInsert, cancelInsert := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+		defer cancelInsert()
+
+		err = tx.WithContext(ctxInsert).
+			Model(&m.User{Id: userId}).
+			Association("Following").
+			Append(&m.User{Id: userRelationId})
+
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+
+		return err
+	}
+
+	if relationDb.Active {
+		return fmt.Errorf("the relation with the user id = %s already exists", relation.UserRelationId)
+	}
+
+	err = db.updateRelation(relation, true)
+
+	return err
+}
+
+/* DeleteRelation deletes a relation in the DB */
+func (db *DbSql) DeleteRelation(relation mr.Relation) error {
+	err := db.updateRelation(relation, false)
+
+	return err
+}
+
+/* GetUsers gets an user's following or not following list */
+func (db *DbSql) GetUsers(id string, page int64, limit int64, search string, searchType string) ([]*mr.User, int64, error) {
+	var results []*mr.User
+	var users []m.User
+	var total int64
+
+	offset := int((page - 1) * limit)
+	limitInt := int(limit)
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	result := db.Connection.WithContext(ctxFind).
+		Where("id <> ? AND lower(name) LIKE ?", id, "%"+strings.ToLower(search)+"%").
+		Order("birth_date desc").Offset(offset).
+		Limit(limitInt).
+		Find(&users)
+	err := result.Error
+
+	if err != nil {
+		return results, total, err
+	}
+
+	var include, isRelation bool
+
+	for _, user := range users {
+		isRelation = false
+		include = false
+
+		userRequest := getUserRequest(user)
+
+		relationRequest := mr.Relation{
+			UserId:         id,
+			UserRelationId: userRequest.Id,
+			Active:         true,
+		}
+
+		isFound, relationDb, err := db.IsRelation(relationRequest)
+
+		if err != nil {
+			return results, total, err
+		}
+
+		if isFound {
+			isRelation = relationDb.Active
+		}
+
+		if relationRequest.UserRelationId == id {
+			include = false
+		} else if (searchType == "new" && !isRelation) || (searchType == "follow" && isRelation) {
+			include = true
+		}
+
+		if include {
+			userRequest.Email = ""
+			userRequest.Password = ""
+			userRequest.Avatar = ""
+			userRequest.Banner = ""
+			userRequest.Biography = ""
+			userRequest.Location = ""
+			userRequest.WebSite = ""
+
+			results = append(results, &userRequest)
+		}
+	}
+
+	// This total only reflects the total returned registries
+	total = int64(len(results))
+
+	return results, total, nil
+}
+
+/* GetFollowing gets an user's following list */
+func (db *DbSql) GetFollowing(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
+	var results []*mr.User
+	var user m.User
+	var total int64
+
+	ctxCount, cancelCount := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelCount()
+
+	filter := "lower(name) LIKE ?"
+	condition := "%" + strings.ToLower(search) + "%"
+	result := db.Connection.WithContext(ctxCount).
+		Preload("Following", filter, condition).
+		First(&user, id)
+	err := result.Error
+
+	if err != nil {
+		return results, total, err
+	}
+
+	total = int64(len(user.Following))
+
+	offset := int((page - 1) * limit)
+	limitInt := int(limit)
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	result = db.Connection.WithContext(ctxFind).
+		Preload("Following", func(db *gorm.DB) *gorm.DB {
+			return db.Where(filter, condition).
+				Order("birth_date desc").
+				Offset(offset).
+				Limit(limitInt).
+				Select("id", "name", "last_name", "birth_date")
+		}).First(&user, id)
+	err = result.Error
+
+	if err == nil {
+		for _, userModel := range user.Following {
+			userRequest := getUserRequest(userModel)
+			results = append(results, &userRequest)
+		}
+	}
+
+	return results, total, err
+}
+
+/* GetFollowers gets an user's followers list */
+func (db *DbSql) GetFollowers(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
+	var results []*mr.User
+	var usersDbResults []m.User
+	var total int64
+
+	uintId, err := getUintId(id)
+
+	if err != nil {
+		return results, total, err
+	}
+
+	query := func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&m.User{}).
+			Joins("JOIN relations ON relations.user_id = users.id").
+			Where("relations.following_id = ? AND relations.active = ?", uintId, true).
+			Where("lower(users.name) LIKE ?", "%"+strings.ToLower(search)+"%")
+	}
+
+	ctxCount, cancelCount := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelCount()
+
+	result := query(db.Connection.WithContext(ctxCount)).Count(&total)
+
+	if result.Error != nil {
+		return results, total, result.Error
+	}
+
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	result = query(db.Connection.WithContext(ctxFind)).
+		Select("users.id", "users.name", "users.last_name", "users.birth_date").
+		Order("users.birth_date desc").
+		Offset(int((page - 1) * limit)).
+		Limit(int(limit)).
+		Find(&usersDbResults)
+	err = result.Error
+
+	if err == nil {
+		for _, userModel := range usersDbResults {
+			userRequest := getUserRequest(userModel)
+			results = append(results, &userRequest)
+		}
+	}
+
+	return results, total, err
+}
+
+/* GetNotFollowing gets an user's not following list */
+func (db *DbSql) GetNotFollowing(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
+	var results []*mr.User
+	var users []m.User
+	var total int64
+
+	uintId, _ := getUintId(id)
+
+	ctxNewUsers, cancelNewUsers := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelNewUsers()
+
+	err := db.Connection.WithContext(ctxNewUsers).
+		Model(&m.User{Id: uintId}).
+		Select("id").
+		Association("Following").
+		Find(&users)
+
+	if err != nil {
+		return results, total, err
+	}
+
+	notIds := []uint64{uintId}
+
+	for _, user := range users {
+		notIds = append(notIds, user.Id)
+	}
+
+	ctxCount, cancelCount := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelCount()
+
+	filter := "lower(name) LIKE ?"
+	condition := "%" + strings.ToLower(search) + "%"
+	result := db.Connection.WithContext(ctxCount).
+		Model(&m.User{}).
+		Not(notIds).
+		Where(filter, condition).
+		Count(&total)
+	err = result.Error
+
+	if err != nil {
+		return results, total, err
+	}
+
+	offset := int((page - 1) * limit)
+	limitInt := int(limit)
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	result = db.Connection.WithContext(ctxFind).
+		Model(&m.User{}).
+		Not(notIds).
+		Where(filter, condition).
+		Order("birth_date desc").
+		Offset(offset).
+		Limit(limitInt).
+		Select("id", "name", "last_name", "birth_date").
+		Find(&users)
+	err = result.Error
+
+	if err == nil {
+		for _, userModel := range users {
+			userRequest := getUserRequest(userModel)
+			results = append(results, &userRequest)
+		}
+	}
+
+	return results, total, err
+}
+
+/* GetFollowingTweets returns the following's tweets */
+func (db *DbSql) GetFollowingTweets(id string, page int64, limit int64, isOnlyTweets bool) (any, int64, error) {
+	var results any
+	var user m.User
+	var total int64
+
+	filter := "active = ?"
+	condition := "true"
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := db.Connection.WithContext(ctx).
+		Preload("Following.Tweets", filter, condition).
+		First(&user, id)
+	err := result.Error
+
+	if err != nil {
+		return results, total, err
+	}
+
+	for _, user := range user.Following {
+		total += int64(len(user.Tweets))
+	}
+
+	offset := int((page - 1) * limit)
+	limitInt := int(limit)
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	result = db.Connection.WithContext(ctxFind).
+		Preload("Following.Tweets", func(db *gorm.DB) *gorm.DB {
+			return db.Where(filter, condition).
+				Order("date desc").
+				Offset(offset).
+				Limit(limitInt)
+		}).First(&user, id)
+	err = result.Error
+
+	if err != nil {
+		return results, total, err
+	}
+
+	if isOnlyTweets {
+		var reqResults []*mr.Tweet
+		var tweets []m.Tweet
+
+		for _, u := range user.Following {
+			tweets = append(tweets, u.Tweets...)
+		}
+
+		for _, tweetModel := range tweets {
+			tweetRequest := getTweetRequest(tweetModel)
+			reqResults = append(reqResults, &tweetRequest)
+		}
+
+		sort.Slice(reqResults, func(i, j int) bool {
+			return reqResults[i].Date.After(reqResults[j].Date)
+		})
+
+		results = reqResults
+	} else {
+		var reqResults []*mr.UserTweet
+
+		for _, u := range user.Following {
+			for _, t := range u.Tweets {
+				userTweetRequest := mr.UserTweet{
+					UserId:         strconv.FormatUint(user.Id, 10),
+					UserRelationId: strconv.FormatUint(u.Id, 10),
+				}
+
+				userTweetRequest.Tweet.Id = strconv.FormatUint(t.Id, 10)
+				userTweetRequest.Tweet.Message = t.Message
+				userTweetRequest.Tweet.Date = t.Date
+				userTweetRequest.Tweet.InReplyTo = getStringId(t.InReplyTo)
+				userTweetRequest.Tweet.ConversationId = getStringId(t.ConversationId)
+				userTweetRequest.Tweet.ReplyCount = t.ReplyCount
+				userTweetRequest.Tweet.RetweetOf = getStringId(t.RetweetOf)
+				userTweetRequest.Tweet.QuotedTweetId = getStringId(t.QuotedTweetId)
+				userTweetRequest.Tweet.RetweetCount = t.RetweetCount
+				userTweetRequest.Tweet.QuoteCount = t.QuoteCount
+				userTweetRequest.Tweet.LikeCount = t.LikeCount
+				userTweetRequest.Tweet.Edited = t.EditedAt != nil
+				userTweetRequest.Tweet.EditedAt = t.EditedAt
+				userTweetRequest.Tweet.Media = t.Media
+				userTweetRequest.Tweet.Active = t.Active
+
+				reqResults = append(reqResults, &userTweetRequest)
+			}
+		}
+
+		sort.Slice(reqResults, func(i, j int) bool {
+			return reqResults[i].Tweet.Date.After(reqResults[j].Tweet.Date)
+		})
+
+		results = reqResults
+	}
+
+	return results, total, err
+}
+
+// endregion
+
+// region "Tokens"
+
+/* RevokeToken adds a token to the revocation list in the DB, it returns false if the token had already been revoked */
+func (db *DbSql) RevokeToken(token mr.RevokedToken) (bool, error) {
+	tokenModel, err := getRevokedTokenModel(token)
+
+	if err != nil {
+		return false, err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	// The expired tokens are useless in the revocation list
+	result := tx.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&m.RevokedToken{})
+	err = result.Error
+
+	if err == nil {
+		result = tx.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&tokenModel)
+		err = result.Error
+	}
+
+	if err != nil {
+		tx.Rollback()
+
+		return false, err
+	}
+
+	tx.Commit()
+
+	// The token is only revoked by this call if it was not in the list yet
+	return result.RowsAffected > 0, nil
+}
+
+/* IsTokenRevoked checks if a token is in the revocation list */
+func (db *DbSql) IsTokenRevoked(tokenId string) (bool, error) {
+	var total int64
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := db.Connection.WithContext(ctx).
+		Model(&m.RevokedToken{}).
+		Where(&m.RevokedToken{TokenId: tokenId}).
+		Count(&total)
+
+	return total > 0, result.Error
+}
+
+/* InsertActionToken inserts a single-use action token in the DB */
+func (db *DbSql) InsertActionToken(token mr.ActionToken) error {
+	tokenModel, err := getActionTokenModel(token)
+
+	if err != nil {
+		return err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := tx.WithContext(ctx).Create(&tokenModel)
+	err = result.Error
+
+	if err != nil {
+		tx.Rollback()
+	} else {
+		tx.Commit()
+	}
+
+	return err
+}
+
+/* ConsumeActionToken deletes an action token from the DB and returns it if it was not expired */
+func (db *DbSql) ConsumeActionToken(hash string, action string) (mr.ActionToken, bool, error) {
+	var tokenModel m.ActionToken
+	var tokenRequest mr.ActionToken
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	// Deleting the token when it is found guarantees that it can be used only once
+	result := db.Connection.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where(&m.ActionToken{Hash: hash, Action: action}).
+		Delete(&tokenModel)
+	err := result.Error
+
+	if err != nil {
+		return tokenRequest, false, err
+	}
+
+	if result.RowsAffected < 1 {
+		return tokenRequest, false, nil
+	}
+
+	tokenRequest = getActionTokenRequest(tokenModel)
+
+	if !tokenRequest.ExpiresAt.IsZero() && tokenRequest.ExpiresAt.Before(time.Now()) {
+		return tokenRequest, false, nil
+	}
+
+	return tokenRequest, true, nil
+}
+
+/* DeleteActionTokens deletes all the action tokens of an user for an action */
+func (db *DbSql) DeleteActionTokens(userId string, action string) error {
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := tx.WithContext(ctx).
+		Where(&m.ActionToken{UserId: uintUserId, Action: action}).
+		Delete(&m.ActionToken{})
+	err = result.Error
+
+	if err != nil {
+		tx.Rollback()
+	} else {
+		tx.Commit()
+	}
+
+	return err
+}
+
+// endregion
+
+// region "Access tokens"
+
+/* InsertAccessToken inserts a personal access token in the DB */
+func (db *DbSql) InsertAccessToken(token mr.AccessToken) (string, error) {
+	tokenModel, err := getAccessTokenModel(token)
+
+	if err != nil {
+		return "", err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := tx.WithContext(ctx).Create(&tokenModel)
+	err = result.Error
+
+	if err != nil {
+		tx.Rollback()
+
+		return "", err
+	}
+
+	tx.Commit()
+
+	return strconv.FormatUint(tokenModel.Id, 10), nil
+}
+
+/* GetAccessTokens gets the personal access tokens of an user */
+func (db *DbSql) GetAccessTokens(userId string) ([]*mr.AccessToken, error) {
+	var results []*mr.AccessToken
+	var tokensDbResults []*m.AccessToken
+
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return results, err
+	}
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := db.Connection.WithContext(ctx).
+		Where("user_id = ?", uintUserId).
+		Order("created_at desc").
+		Find(&tokensDbResults)
+
+	if result.Error != nil {
+		return results, result.Error
+	}
+
+	for _, tokenModel := range tokensDbResults {
+		tokenRequest := getAccessTokenRequest(*tokenModel)
+		results = append(results, &tokenRequest)
+	}
+
+	return results, nil
+}
+
+/* GetAccessToken gets a personal access token by its hash */
+func (db *DbSql) GetAccessToken(hash string) (mr.AccessToken, bool, error) {
+	var tokenModel m.AccessToken
+	var tokenRequest mr.AccessToken
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := db.Connection.WithContext(ctx).
+		Where("hash = ?", hash).
+		Limit(1).
+		Find(&tokenModel)
+
+	if result.Error != nil {
+		return tokenRequest, false, result.Error
+	}
+
+	if result.RowsAffected < 1 {
+		return tokenRequest, false, nil
+	}
+
+	tokenRequest = getAccessTokenRequest(tokenModel)
+
+	return tokenRequest, true, nil
+}
+
+/* DeleteAccessToken deletes a personal access token of an user */
+func (db *DbSql) DeleteAccessToken(id string, userId string) (bool, error) {
+	uintId, err := getUintId(id)
+
+	if err != nil {
+		return false, err
+	}
+
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return false, err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := tx.WithContext(ctx).
+		Where("id = ? AND user_id = ?", uintId, uintUserId).
+		Delete(&m.AccessToken{})
+	err = result.Error
+
+	if err != nil {
+		tx.Rollback()
+
+		return false, err
+	}
+
+	tx.Commit()
+
+	return result.RowsAffected > 0, nil
+}
+
+/* DeleteAccessTokens deletes all the personal access tokens of an user */
+func (db *DbSql) DeleteAccessTokens(userId string) error {
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err = tx.WithContext(ctx).Where("user_id = ?", uintUserId).Delete(&m.AccessToken{}).Error
+
+	if err != nil {
+		tx.Rollback()
+	} else {
+		tx.Commit()
+	}
+
+	return err
+}
+
+// endregion
+
+// region "Sessions"
+
+/* InsertSession inserts a session in the DB */
+func (db *DbSql) InsertSession(session mr.Session) (string, error) {
+	sessionModel, err := getSessionModel(session)
+
+	if err != nil {
+		return "", err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	// The expired sessions are not shown anymore
+	result := tx.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&m.Session{})
+	err = result.Error
+
+	if err == nil {
+		result = tx.WithContext(ctx).Create(&sessionModel)
+		err = result.Error
+	}
+
+	if err != nil {
+		tx.Rollback()
+
+		return "", err
+	}
+
+	tx.Commit()
+
+	return strconv.FormatUint(sessionModel.Id, 10), nil
+}
+
+/* GetSession gets a session by its id */
+func (db *DbSql) GetSession(id string) (mr.Session, bool, error) {
+	var sessionModel m.Session
+	var sessionRequest mr.Session
+
+	uintId, err := getUintId(id)
+
+	if err != nil {
+		return sessionRequest, false, err
+	}
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := db.Connection.WithContext(ctx).
+		Where("id = ?", uintId).
+		Limit(1).
+		Find(&sessionModel)
+
+	if result.Error != nil {
+		return sessionRequest, false, result.Error
+	}
+
+	if result.RowsAffected < 1 {
+		return sessionRequest, false, nil
+	}
+
+	sessionRequest = getSessionRequest(sessionModel)
+
+	return sessionRequest, true, nil
+}
+
+/* GetSessions gets the active sessions of an user */
+func (db *DbSql) GetSessions(userId string) ([]*mr.Session, error) {
+	var results []*mr.Session
+	var sessionsDbResults []*m.Session
+
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return results, err
+	}
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := db.Connection.WithContext(ctx).
+		Where("user_id = ? AND expires_at > ?", uintUserId, time.Now()).
+		Order("last_seen_at desc").
+		Find(&sessionsDbResults)
+
+	if result.Error != nil {
+		return results, result.Error
+	}
+
+	for _, sessionModel := range sessionsDbResults {
+		sessionRequest := getSessionRequest(*sessionModel)
+		results = append(results, &sessionRequest)
+	}
+
+	return results, nil
+}
+
+/* TouchSession updates the last time a session was seen and its expiration */
+func (db *DbSql) TouchSession(id string, lastSeenAt time.Time, expiresAt time.Time) error {
+	uintId, err := getUintId(id)
+
+	if err != nil {
+		return err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := tx.WithContext(ctx).
+		Model(&m.Session{}).
+		Where("id = ?", uintId).
+		Updates(map[string]any{
+			"last_seen_at": lastSeenAt,
+			"expires_at":   expiresAt,
+		})
+	err = result.Error
+
+	if err != nil {
+		tx.Rollback()
+	} else {
+		tx.Commit()
+	}
+
+	return err
+}
+
+/* DeleteSession deletes a session of an user */
+func (db *DbSql) DeleteSession(id string, userId string) (bool, error) {
+	uintId, err := getUintId(id)
+
+	if err != nil {
+		return false, err
+	}
+
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return false, err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := tx.WithContext(ctx).
+		Where("id = ? AND user_id = ?", uintId, uintUserId).
+		Delete(&m.Session{})
+	err = result.Error
+
+	if err != nil {
+		tx.Rollback()
+
+		return false, err
+	}
+
+	tx.Commit()
+
+	return result.RowsAffected > 0, nil
+}
+
+/* DeleteSessions deletes all the sessions of an user except the one with the exceptId, if it is set */
+func (db *DbSql) DeleteSessions(userId string, exceptId string) error {
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return err
+	}
+
+	uintExceptId, err := getUintId(exceptId)
+
+	if err != nil {
+		return err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := tx.WithContext(ctx).
+		Where("user_id = ? AND id <> ?", uintUserId, uintExceptId).
+		Delete(&m.Session{})
+	err = result.Error
+
+	if err != nil {
+		tx.Rollback()
+	} else {
+		tx.Commit()
+	}
+
+	return err
+}
+
+// endregion
+
+// region "Exports"
+
+/* InsertExport inserts an export in the DB */
+func (db *DbSql) InsertExport(export mr.Export) (string, error) {
+	exportModel, err := getExportModel(export)
+
+	if err != nil {
+		return "", err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err = tx.WithContext(ctx).Create(&exportModel).Error
+
+	if err != nil {
+		tx.Rollback()
+
+		return "", err
+	}
+
+	tx.Commit()
+
+	return strconv.FormatUint(exportModel.Id, 10), nil
+}
+
+/* GetExport gets an export by its id */
+func (db *DbSql) GetExport(id string) (mr.Export, bool, error) {
+	var exportModel m.Export
+	var exportRequest mr.Export
+
+	uintId, err := getUintId(id)
+
+	if err != nil {
+		return exportRequest, false, err
+	}
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := db.Connection.WithContext(ctx).
+		Where("id = ?", uintId).
+		Limit(1).
+		Find(&exportModel)
+
+	if result.Error != nil {
+		return exportRequest, false, result.Error
+	}
+
+	if result.RowsAffected < 1 {
+		return exportRequest, false, nil
+	}
+
+	exportRequest = getExportRequest(exportModel)
+
+	return exportRequest, true, nil
+}
+
+/* GetExports gets the exports of an user that have not expired, the newest first */
+func (db *DbSql) GetExports(userId string) ([]*mr.Export, error) {
+	uintUserId, err := getUintId(userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return db.findExports("created_at desc", "user_id = ? AND expires_at > ?", uintUserId, time.Now())
+}
+
+/* GetExpiredExports gets the exports that expired before a date */
+func (db *DbSql) GetExpiredExports(before time.Time) ([]*mr.Export, error) {
+	return db.findExports("expires_at asc", "expires_at <= ?", before)
+}
+
+/* ModifyExport updates the status and the download token of an export, it returns false if it does not exist */
+func (db *DbSql) ModifyExport(export mr.Export) (bool, error) {
+	uintId, err := getUintId(export.Id)
+
+	if err != nil {
+		return false, err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := tx.WithContext(ctx).
+		Model(&m.Export{}).
+		Where("id = ?", uintId).
+		Updates(map[string]any{
+			"status":           export.Status,
+			"error":            export.Error,
+			"token_hash":       export.TokenHash,
+			"token_expires_at": export.TokenExpiresAt,
+		})
+
+	if result.Error != nil {
+		tx.Rollback()
+
+		return false, result.Error
+	}
+
+	tx.Commit()
+
+	return result.RowsAffected > 0, nil
+}
+
+/* DeleteExport deletes an export */
+func (db *DbSql) DeleteExport(id string) error {
+	uintId, err := getUintId(id)
+
+	if err != nil {
+		return err
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	err = tx.WithContext(ctx).Delete(&m.Export{}, uintId).Error
+
+	if err != nil {
+		tx.Rollback()
+	} else {
+		tx.Commit()
+	}
+
+	return err
+}
+
+// endregion
+
+// region "Login attempts"
+
+/* GetLoginAttempt gets the failed login attempts counted for a key */
+func (db *DbSql) GetLoginAttempt(key string) (mr.LoginAttempt, bool, error) {
+	var attemptModel m.LoginAttempt
+	var attemptRequest mr.LoginAttempt
+
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := db.Connection.WithContext(ctx).
+		Where("key = ? AND expires_at > ?", key, time.Now()).
+		Limit(1).
+		Find(&attemptModel)
+
+	if result.Error != nil {
+		return attemptRequest, false, result.Error
+	}
+
+	if result.RowsAffected < 1 {
+		return attemptRequest, false, nil
+	}
+
+	attemptRequest = getLoginAttemptRequest(attemptModel)
+
+	return attemptRequest, true, nil
+}
+
+/* IncrementLoginAttempt adds a failed login attempt to a key and returns the updated counter */
+func (db *DbSql) IncrementLoginAttempt(key string, expiresAt time.Time) (mr.LoginAttempt, error) {
+	now := time.Now()
+	attemptModel := m.LoginAttempt{
+		Key:           key,
+		Failures:      1,
+		LastFailureAt: now,
+		ExpiresAt:     expiresAt,
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	// The expired counters start again from zero
+	result := tx.WithContext(ctx).Where("expires_at <= ?", now).Delete(&m.LoginAttempt{})
+	err := result.Error
+
+	if err == nil {
+		// The counter is incremented by the DB, so the concurrent attempts are not lost
+		result = tx.WithContext(ctx).
+			Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "key"}},
+				DoUpdates: clause.Assignments(map[string]any{
+					"failures":        gorm.Expr("? + 1", clause.Column{Table: clause.CurrentTable, Name: "failures"}),
+					"last_failure_at": now,
+					"expires_at":      expiresAt,
+				}),
+			}, clause.Returning{}).
+			Create(&attemptModel)
+		err = result.Error
+	}
+
+	if err != nil {
+		tx.Rollback()
+	} else {
+		tx.Commit()
+	}
+
+	return getLoginAttemptRequest(attemptModel), err
+}
+
+/* DeleteLoginAttempt resets the failed login attempts of a key */
+func (db *DbSql) DeleteLoginAttempt(key string) error {
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result := tx.WithContext(ctx).Where(&m.LoginAttempt{Key: key}).Delete(&m.LoginAttempt{})
+	err := result.Error
+
+	if err != nil {
+		tx.Rollback()
+	} else {
+		tx.Commit()
+	}
+
+	return err
+}
+
+// endregion
+
+// region "Helpers"
+
+func (db *DbSql) deleteTweetFisical(id string, userId string) error {
+	var tweetModel m.Tweet
+
+	uintId, err := getUintId(id)
+
+	if err != nil {
+		return err
+	}
+
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	result := db.Connection.WithContext(ctxFind).First(&tweetModel, uintId)
+	err = result.Error
+
+	if err != nil {
+		return err
+	}
+
+	uintUserId, _ := getUintId(userId)
+
+	if uintUserId != tweetModel.UserId {
+		return errors.New("invalid operation - cannot delete a non-owner tweet")
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	result = tx.WithContext(ctx).Delete(&m.Tweet{}, uintId)
+	err = result.Error
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	err = tx.Commit().Error
+
+	// The images are removed once the tweet is no longer in the DB
+	if err == nil && result.RowsAffected > 0 {
+		media.Delete(tweetModel.Media)
+	}
+
+	return err
+}
+
+func (db *DbSql) deleteTweetLogical(id string, userId string) error {
+	var tweetModel m.Tweet
+
+	uintId, err := getUintId(id)
+
+	if err != nil {
+		return err
+	}
+
+	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelFind()
+
+	result := db.Connection.WithContext(ctxFind).First(&tweetModel, uintId)
+	err = result.Error
+
+	if err != nil {
+		return err
+	}
+
+	uintUserId, _ := getUintId(userId)
+
+	if uintUserId != tweetModel.UserId {
+		return errors.New("invalid operation - cannot delete a non-owner tweet")
+	}
+
+	tx := db.Connection.Begin()
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancel()
+
+	// Only the request that deactivates the tweet changes the counters, so concurrent deletes do not decrement them twice
+	result = tx.WithContext(ctx).Model(&tweetModel).Where("active = ?", true).Update("active", false)
+	err = result.Error
+	isDeactivated := result.RowsAffected == 1
+
+	// The replies, retweets and quotes are kept, only the counters of the referenced tweets change
+	if err == nil && isDeactivated {
+		err = updateTweetCounters(tx.WithContext(ctx), tweetModel, -1)
+	}
+
+	if err == nil && isDeactivated {
+		err = updateHashtagCounts(tx.WithContext(ctx), helpers.GetHashtags(tweetModel.Message), tweetModel.Date, -1)
+	}
+
+	if err != nil {
+		tx.Rollback()
+	} else {
+		tx.Commit()
+	}
+
+	return err
+}
+
+/* revertUserTweetCounters undoes the counters added by the active tweets of an user to other tweets and to the trends */
+func revertUserTweetCounters(tx *gorm.DB, userId uint64) error {
+	var tweetsDbResults []m.Tweet
+
+	err := tx.Where("user_id = ? AND active = ?", userId, true).Find(&tweetsDbResults).Error
+
+	if err != nil {
+		return err
+	}
+
+	for _, tweetModel := range tweetsDbResults {
+		err = updateTweetCounters(tx, tweetModel, -1)
+
+		if err == nil {
+			err = updateHashtagCounts(tx, helpers.GetHashtags(tweetModel.Message), tweetModel.Date, -1)
+		}
+
+		if err != nil {
+			return err
 		}
 	}
 
-	return results, total, err
+	return nil
 }
 
-/* GetNotFollowing gets an user's not following list */
-func (db *DbSql) GetNotFollowing(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
-	var results []*mr.User
-	var users []m.User
-	var total int64
+func insertTweet(tx *gorm.DB, tweetModel *m.Tweet) error {
+	err := tx.Model(&m.User{Id: tweetModel.UserId}).Association("Tweets").Append(tweetModel)
 
-	uintId, _ := getUintId(id)
+	if err == nil {
+		err = updateTweetCounters(tx, *tweetModel, 1)
+	}
 
-	ctxNewUsers, cancelNewUsers := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+	hashtags := helpers.GetHashtags(tweetModel.Message)
 
-	defer cancelNewUsers()
+	if err == nil {
+		err = setTweetHashtags(tx, *tweetModel, hashtags)
+	}
 
-	err := db.Connection.WithContext(ctxNewUsers).
-		Model(&m.User{Id: uintId}).
-		Select("id").
-		Association("Following").
-		Find(&users)
+	if err == nil {
+		err = setTweetMentions(tx, *tweetModel)
+	}
 
 	if err != nil {
-		return results, total, err
+		return err
 	}
 
-	notIds := []uint64{uintId}
+	return updateHashtagCounts(tx, hashtags, tweetModel.Date, 1)
+}
 
-	for _, user := range users {
-		notIds = append(notIds, user.Id)
+/* setTweetHashtags replaces the hashtags indexed for a tweet */
+func setTweetHashtags(tx *gorm.DB, tweetModel m.Tweet, hashtags []string) error {
+	var hashtagModels []m.TweetHashtag
+
+	err := tx.Where("tweet_id = ?", tweetModel.Id).Delete(&m.TweetHashtag{}).Error
+
+	if err != nil || len(hashtags) == 0 {
+		return err
 	}
 
-	ctxCount, cancelCount := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+	for _, hashtag := range hashtags {
+		hashtagModels = append(hashtagModels, m.TweetHashtag{
+			TweetId: tweetModel.Id,
+			Tag:     hashtag,
+			Date:    tweetModel.Date,
+		})
+	}
 
-	defer cancelCount()
+	return tx.Create(&hashtagModels).Error
+}
 
-	filter := "lower(name) LIKE ?"
-	condition := "%" + strings.ToLower(search) + "%"
-	result := db.Connection.WithContext(ctxCount).
-		Model(&m.User{}).
-		Not(notIds).
-		Where(filter, condition).
-		Count(&total)
-	err = result.Error
+/* setTweetMentions replaces the users mentioned in a tweet, they are resolved by their handles */
+func setTweetMentions(tx *gorm.DB, tweetModel m.Tweet) error {
+	var mentionModels []m.TweetMention
+	var userIds []uint64
+
+	err := tx.Where("tweet_id = ?", tweetModel.Id).Delete(&m.TweetMention{}).Error
 
 	if err != nil {
-		return results, total, err
+		return err
 	}
 
-	offset := int((page - 1) * limit)
-	limitInt := int(limit)
-	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+	mentions := helpers.GetMentions(tweetModel.Message)
 
-	defer cancelFind()
+	if len(mentions) == 0 {
+		return nil
+	}
 
-	result = db.Connection.WithContext(ctxFind).
-		Model(&m.User{}).
-		Not(notIds).
-		Where(filter, condition).
-		Order("birth_date desc").
-		Offset(offset).
-		Limit(limitInt).
-		Select("id", "name", "last_name", "birth_date").
-		Find(&users)
-	err = result.Error
+	err = tx.Model(&m.User{}).Where("handle IN ?", mentions).Pluck("id", &userIds).Error
 
-	if err == nil {
-		for _, userModel := range users {
-			userRequest := getUserRequest(userModel)
-			results = append(results, &userRequest)
+	if err != nil {
+		return err
+	}
+
+	// The unknown handles are left as plain text
+	for _, userId := range userIds {
+		if userId != tweetModel.UserId {
+			mentionModels = append(mentionModels, m.TweetMention{
+				TweetId: tweetModel.Id,
+				UserId:  userId,
+				Date:    tweetModel.Date,
+			})
 		}
 	}
 
-	return results, total, err
+	if len(mentionModels) == 0 {
+		return nil
+	}
+
+	return tx.Create(&mentionModels).Error
 }
 
-/* GetFollowingTweets returns the following's tweets */
-func (db *DbSql) GetFollowingTweets(id string, page int64, limit int64, isOnlyTweets bool) (any, int64, error) {
-	var results any
-	var user m.User
-	var total int64
+/* getAvailableHandle returns the base handle, or the first one with a numeric suffix, that no user has */
+func getAvailableHandle(tx *gorm.DB, base string) (string, error) {
+	handle := base
 
-	filter := "active = ?"
-	condition := "true"
-	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+	for i := 2; ; i++ {
+		var count int64
 
-	defer cancel()
+		ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+		err := tx.WithContext(ctx).Model(&m.User{}).Where("handle = ?", handle).Limit(1).Count(&count).Error
 
-	result := db.Connection.WithContext(ctx).
-		Preload("Following.Tweets", filter, condition).
-		First(&user, id)
-	err := result.Error
+		cancel()
 
-	if err != nil {
-		return results, total, err
-	}
+		if err != nil {
+			return "", err
+		}
 
-	for _, user := range user.Following {
-		total += int64(len(user.Tweets))
+		if count == 0 {
+			return handle, nil
+		}
+
+		handle = fmt.Sprintf("%s%d", base, i)
 	}
+}
+
+/* setMissingHandles sets the handles of the users registered before the handles were added, the oldest users first */
+func setMissingHandles(tx *gorm.DB) error {
+	var userModels []m.User
 
-	offset := int((page - 1) * limit)
-	limitInt := int(limit)
 	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
 	defer cancelFind()
 
-	result = db.Connection.WithContext(ctxFind).
-		Preload("Following.Tweets", func(db *gorm.DB) *gorm.DB {
-			return db.Where(filter, condition).
-				Order("date desc").
-				Offset(offset).
-				Limit(limitInt)
-		}).First(&user, id)
-	err = result.Error
+	err := tx.WithContext(ctxFind).Select("id", "email").Where("handle IS NULL").Order("id").Find(&userModels).Error
 
 	if err != nil {
-		return results, total, err
+		return err
 	}
 
-	if isOnlyTweets {
-		var reqResults []*mr.Tweet
-		var tweets []m.Tweet
+	for _, userModel := range userModels {
+		handle, err := getAvailableHandle(tx, helpers.GetHandle(userModel.Email))
 
-		for _, u := range user.Following {
-			tweets = append(tweets, u.Tweets...)
+		if err != nil {
+			return err
 		}
 
-		for _, tweetModel := range tweets {
-			tweetRequest := getTweetRequest(tweetModel)
-			reqResults = append(reqResults, &tweetRequest)
+		ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+		err = tx.WithContext(ctx).Model(&m.User{Id: userModel.Id}).Update("handle", handle).Error
+
+		cancel()
+
+		if err != nil {
+			return err
 		}
+	}
 
-		sort.Slice(reqResults, func(i, j int) bool {
-			return reqResults[i].Date.After(reqResults[j].Date)
-		})
+	return nil
+}
 
-		results = reqResults
-	} else {
-		var reqResults []*mr.UserTweet
+/* updateHashtagCounts updates the counts of the trending hashtags in the time bucket of a tweet */
+func updateHashtagCounts(tx *gorm.DB, hashtags []string, date time.Time, delta int) error {
+	bucket := helpers.GetTrendsBucket(date)
+	expiresAt := helpers.GetTrendsBucketExpiration(bucket)
 
-		for _, u := range user.Following {
-			for _, t := range u.Tweets {
-				userTweetRequest := mr.UserTweet{
-					UserId:         strconv.FormatUint(user.Id, 10),
-					UserRelationId: strconv.FormatUint(u.Id, 10),
-				}
+	// The buckets out of the trends window are not read anymore
+	if len(hashtags) == 0 || expiresAt.Before(time.Now()) {
+		return nil
+	}
 
-				userTweetRequest.Tweet.Id = strconv.FormatUint(t.Id, 10)
-				userTweetRequest.Tweet.Message = t.Message
-				userTweetRequest.Tweet.Date = t.Date
+	err := tx.Where("expires_at < ?", time.Now()).Delete(&m.HashtagCount{}).Error
 
-				reqResults = append(reqResults, &userTweetRequest)
-			}
+	if err != nil {
+		return err
+	}
+
+	for _, hashtag := range hashtags {
+		countModel := m.HashtagCount{
+			Tag:       hashtag,
+			Bucket:    bucket,
+			Count:     int64(delta),
+			ExpiresAt: expiresAt,
 		}
 
-		sort.Slice(reqResults, func(i, j int) bool {
-			return reqResults[i].Tweet.Date.After(reqResults[j].Tweet.Date)
-		})
+		err = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tag"}, {Name: "bucket"}},
+			DoUpdates: clause.Assignments(map[string]any{"count": gorm.Expr("hashtag_counts.count + ?", delta)}),
+		}).Create(&countModel).Error
 
-		results = reqResults
+		if err != nil {
+			return err
+		}
 	}
 
-	return results, total, err
+	return nil
 }
 
-// endregion
-
-// region "Helpers"
+/* updateTweetCounters updates the counters of the tweets replied, retweeted or quoted by a tweet */
+func updateTweetCounters(tx *gorm.DB, tweetModel m.Tweet, delta int) error {
+	counters := map[string]*uint64{
+		"reply_count":   tweetModel.InReplyTo,
+		"retweet_count": tweetModel.RetweetOf,
+		"quote_count":   tweetModel.QuotedTweetId,
+	}
 
-func (db *DbSql) deleteTweetFisical(id string, userId string) error {
-	var tweetModel m.Tweet
+	for counter, id := range counters {
+		if id == nil {
+			continue
+		}
 
-	uintId, err := getUintId(id)
+		err := tx.Model(&m.Tweet{Id: *id}).Update(counter, gorm.Expr(counter+" + ?", delta)).Error
 
-	if err != nil {
-		return err
+		if err != nil {
+			return err
+		}
 	}
 
-	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+	return nil
+}
 
-	defer cancelFind()
+func (db *DbSql) findScheduledTweets(page int64, limit int64, condition string, args ...any) ([]*mr.ScheduledTweet, int64, error) {
+	var results []*mr.ScheduledTweet
+	var scheduledDbResults []m.ScheduledTweet
+	var total int64
 
-	result := db.Connection.WithContext(ctxFind).First(&tweetModel, uintId)
-	err = result.Error
+	ctxCount, cancelCount := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
-	if err != nil {
-		return err
-	}
+	defer cancelCount()
 
-	uintUserId, _ := getUintId(userId)
+	result := db.Connection.WithContext(ctxCount).
+		Model(&m.ScheduledTweet{}).
+		Where(condition, args...).
+		Count(&total)
 
-	if uintUserId != tweetModel.UserId {
-		return errors.New("invalid operation - cannot delete a non-owner tweet")
+	if result.Error != nil {
+		return results, total, result.Error
 	}
 
-	tx := db.Connection.Begin()
 	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
 	defer cancel()
 
-	result = tx.WithContext(ctx).Delete(&m.Tweet{}, uintId)
-	err = result.Error
+	query := db.Connection.WithContext(ctx).
+		Where(condition, args...).
+		Order("publish_at asc")
 
-	if err != nil {
-		tx.Rollback()
-	} else {
-		tx.Commit()
+	// Without a limit all the tweets are returned
+	if limit > 0 {
+		query = query.Offset(int((page - 1) * limit)).Limit(int(limit))
 	}
 
-	return err
+	result = query.Find(&scheduledDbResults)
+
+	if result.Error != nil {
+		return results, total, result.Error
+	}
+
+	for _, scheduledModel := range scheduledDbResults {
+		scheduledRequest := getScheduledTweetRequest(scheduledModel)
+		results = append(results, &scheduledRequest)
+	}
+
+	return results, total, nil
 }
 
-func (db *DbSql) deleteTweetLogical(id string, userId string) error {
-	var tweetModel m.Tweet
+func (db *DbSql) findTweets(page int64, limit int64, isNewestFirst bool, condition string, args ...any) ([]*mr.Tweet, int64, error) {
+	order := clause.OrderBy{
+		Columns: []clause.OrderByColumn{{Column: clause.Column{Name: "date"}, Desc: isNewestFirst}},
+	}
 
-	uintId, err := getUintId(id)
+	return db.findSortedTweets(page, limit, order, condition, args...)
+}
 
-	if err != nil {
-		return err
+func (db *DbSql) findSortedTweets(page int64, limit int64, order clause.OrderBy, condition string, args ...any) ([]*mr.Tweet, int64, error) {
+	var results []*mr.Tweet
+	var tweetsDbResults []m.Tweet
+	var total int64
+
+	ctxCount, cancelCount := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+
+	defer cancelCount()
+
+	result := db.Connection.WithContext(ctxCount).
+		Model(&m.Tweet{}).
+		Where(condition, args...).
+		Count(&total)
+
+	if result.Error != nil {
+		return results, total, result.Error
 	}
 
-	ctxFind, cancelFind := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
+	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
-	defer cancelFind()
+	defer cancel()
 
-	result := db.Connection.WithContext(ctxFind).First(&tweetModel, uintId)
-	err = result.Error
+	query := db.Connection.WithContext(ctx).
+		Where(condition, args...).
+		Clauses(order)
 
-	if err != nil {
-		return err
+	// Without a limit all the tweets are returned
+	if limit > 0 {
+		query = query.Offset(int((page - 1) * limit)).Limit(int(limit))
 	}
 
-	uintUserId, _ := getUintId(userId)
+	result = query.Find(&tweetsDbResults)
 
-	if uintUserId != tweetModel.UserId {
-		return errors.New("invalid operation - cannot delete a non-owner tweet")
+	if result.Error != nil {
+		return results, total, result.Error
 	}
 
-	tx := db.Connection.Begin()
+	for _, tweetModel := range tweetsDbResults {
+		tweetRequest := getTweetRequest(tweetModel)
+		results = append(results, &tweetRequest)
+	}
+
+	return results, total, nil
+}
+
+func (db *DbSql) findExports(order string, condition string, args ...any) ([]*mr.Export, error) {
+	var results []*mr.Export
+	var exportsDbResults []m.Export
+
 	ctx, cancel := helpers.GetTimeoutCtx(os.Getenv("CTX_TIMEOUT"))
 
 	defer cancel()
 
-	result = tx.WithContext(ctx).Model(&tweetModel).Update("active", false)
-	err = result.Error
+	result := db.Connection.WithContext(ctx).
+		Where(condition, args...).
+		Order(order).
+		Find(&exportsDbResults)
 
-	if err != nil {
-		tx.Rollback()
-	} else {
-		tx.Commit()
+	if result.Error != nil {
+		return results, result.Error
 	}
 
-	return err
+	for _, exportModel := range exportsDbResults {
+		exportRequest := getExportRequest(exportModel)
+		results = append(results, &exportRequest)
+	}
+
+	return results, nil
 }
 
 func (db *DbSql) deleteRelationFisical(relation mr.Relation) error {
@@ -833,11 +3358,7 @@ func (db *DbSql) updateRelation(relation mr.Relation, value bool) error {
 }
 
 func encryptPassword(password string) (string, error) {
-	// Minimum - cost: 6
-	// Common user - cost: 6
-	// Admin user - cost: 8
-
-	cost := 8
+	cost := helpers.GetBcryptCost()
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), cost)
 
 	return string(bytes), err
@@ -0,0 +1,156 @@
+package embeds
+
+import (
+	"db"
+	mr "models/request"
+)
+
+/* originals caches the referenced tweets, so a tweet retweeted several times in a page is read once */
+type originals map[string]*mr.Tweet
+
+/* SetOriginals embeds the retweeted or quoted tweets with their author in the tweets */
+func SetOriginals(tweets []*mr.Tweet) error {
+	cache := make(originals)
+
+	for _, tweet := range tweets {
+		original, err := cache.get(getOriginalId(tweet.RetweetOf, tweet.QuotedTweetId))
+
+		if err != nil {
+			return err
+		}
+
+		tweet.Original = original
+	}
+
+	return nil
+}
+
+/* SetUserTweetOriginals embeds the retweeted or quoted tweets with their author in the following's tweets */
+func SetUserTweetOriginals(userTweets []*mr.UserTweet) error {
+	cache := make(originals)
+
+	for _, userTweet := range userTweets {
+		original, err := cache.get(getOriginalId(userTweet.Tweet.RetweetOf, userTweet.Tweet.QuotedTweetId))
+
+		if err != nil {
+			return err
+		}
+
+		userTweet.Tweet.Original = original
+	}
+
+	return nil
+}
+
+/* SetLikedByMe flags the tweets liked by the user */
+func SetLikedByMe(userId string, tweets []*mr.Tweet) error {
+	var tweetIds []string
+
+	for _, tweet := range tweets {
+		tweetIds = append(tweetIds, tweet.Id)
+	}
+
+	isLiked, err := getLikedTweets(userId, tweetIds)
+
+	if err != nil {
+		return err
+	}
+
+	for _, tweet := range tweets {
+		tweet.LikedByMe = isLiked[tweet.Id]
+	}
+
+	return nil
+}
+
+/* SetUserTweetLikedByMe flags the following's tweets liked by the user */
+func SetUserTweetLikedByMe(userId string, userTweets []*mr.UserTweet) error {
+	var tweetIds []string
+
+	for _, userTweet := range userTweets {
+		tweetIds = append(tweetIds, userTweet.Tweet.Id)
+	}
+
+	isLiked, err := getLikedTweets(userId, tweetIds)
+
+	if err != nil {
+		return err
+	}
+
+	for _, userTweet := range userTweets {
+		userTweet.Tweet.LikedByMe = isLiked[userTweet.Tweet.Id]
+	}
+
+	return nil
+}
+
+// region "Helpers"
+
+/* getLikedTweets reads all the likes of a page at once */
+func getLikedTweets(userId string, tweetIds []string) (map[string]bool, error) {
+	isLiked := make(map[string]bool)
+
+	if len(tweetIds) < 1 {
+		return isLiked, nil
+	}
+
+	likedIds, err := db.DbConn.GetLikedTweetIds(userId, tweetIds)
+
+	for _, likedId := range likedIds {
+		isLiked[likedId] = true
+	}
+
+	return isLiked, err
+}
+
+func getOriginalId(retweetOf string, quotedTweetId string) string {
+	if len(retweetOf) > 0 {
+		return retweetOf
+	}
+
+	return quotedTweetId
+}
+
+func (cache originals) get(id string) (*mr.Tweet, error) {
+	if len(id) < 1 {
+		return nil, nil
+	}
+
+	if original, isFound := cache[id]; isFound {
+		return original, nil
+	}
+
+	tweet, isFound, err := db.DbConn.GetTweet(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The deleted tweets are shown as unavailable without their content
+	original := &mr.Tweet{Id: id, Unavailable: true}
+
+	if isFound && tweet.Active {
+		original = &tweet
+
+		profile, isFound, err := db.DbConn.GetProfile(tweet.UserId)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if isFound {
+			original.Author = &mr.User{
+				Id:       profile.Id,
+				Name:     profile.Name,
+				LastName: profile.LastName,
+				Avatar:   profile.Avatar,
+			}
+		}
+	}
+
+	cache[id] = original
+
+	return original, nil
+}
+
+// endregion
@@ -0,0 +1,465 @@
+package exports
+
+import (
+	"archive/zip"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"db"
+	"helpers"
+	mr "models/request"
+)
+
+const (
+	/* StatusPending is the status of an export that is being generated */
+	StatusPending = "pending"
+
+	/* StatusReady is the status of an export that can be downloaded */
+	StatusReady = "ready"
+
+	/* StatusFailed is the status of an export whose generation has failed */
+	StatusFailed = "failed"
+
+	exportsPath = "uploads/exports"
+	pageSize    = 100
+)
+
+/* exportTweet is the tweet saved in the archive, it tells if the tweet was deleted */
+type exportTweet struct {
+	Id            string              `json:"id"`
+	Message       string              `json:"message"`
+	Date          time.Time           `json:"date"`
+	InReplyTo     string              `json:"inReplyTo,omitempty"`
+	RetweetOf     string              `json:"retweetOf,omitempty"`
+	QuotedTweetId string              `json:"quotedTweetId,omitempty"`
+	EditedAt      *time.Time          `json:"editedAt,omitempty"`
+	Revisions     []*mr.TweetRevision `json:"revisions,omitempty"`
+	Media         []string            `json:"media,omitempty"`
+	Deleted       bool                `json:"deleted"`
+}
+
+/* Start starts the generation of an user's export, it returns the export in progress or ready if there is one */
+func Start(user mr.User) (mr.Export, error) {
+	current, err := db.DbConn.GetExports(user.Id)
+
+	if err != nil {
+		return mr.Export{}, err
+	}
+
+	for _, export := range current {
+		err = checkInterrupted(export)
+
+		if err != nil {
+			return mr.Export{}, err
+		}
+
+		if export.Status != StatusFailed {
+			return *export, nil
+		}
+	}
+
+	now := time.Now()
+	export := mr.Export{
+		UserId:    user.Id,
+		Status:    StatusPending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(helpers.GetDurationEnv("EXPORT_TTL", 24*time.Hour)),
+	}
+
+	export.Id, err = db.DbConn.InsertExport(export)
+
+	if err != nil {
+		return mr.Export{}, err
+	}
+
+	go generate(export, user)
+
+	return export, nil
+}
+
+/* Get gets an user's export */
+func Get(id string, userId string) (mr.Export, bool, error) {
+	export, isFound, err := db.DbConn.GetExport(id)
+
+	if err != nil || !isFound || export.UserId != userId || !export.ExpiresAt.After(time.Now()) {
+		return mr.Export{}, false, err
+	}
+
+	err = checkInterrupted(&export)
+
+	return export, err == nil, err
+}
+
+/* NewDownloadToken issues the token of the download link of a ready export, replacing the previous one */
+func NewDownloadToken(export mr.Export) (string, time.Time, error) {
+	if export.Status != StatusReady {
+		return "", time.Time{}, fmt.Errorf("the export %s is not ready", export.Id)
+	}
+
+	// The download link is used without the authorization header, so it carries its own token
+	token, err := helpers.GenerateToken(32)
+
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiresAt := time.Now().Add(helpers.GetDurationEnv("EXPORT_LINK_TTL", 15*time.Minute))
+
+	if expiresAt.After(export.ExpiresAt) {
+		expiresAt = export.ExpiresAt
+	}
+
+	export.TokenHash = helpers.HashToken(token)
+	export.TokenExpiresAt = expiresAt
+
+	isFound, err := db.DbConn.ModifyExport(export)
+
+	if err == nil && !isFound {
+		err = fmt.Errorf("the export %s does not exist", export.Id)
+	}
+
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+/* GetFile returns the path of the archive of an export if the download token is valid */
+func GetFile(id string, token string) (string, bool, error) {
+	export, isFound, err := db.DbConn.GetExport(id)
+
+	if err != nil || !isFound || export.Status != StatusReady || !export.TokenExpiresAt.After(time.Now()) {
+		return "", false, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(export.TokenHash), []byte(helpers.HashToken(token))) != 1 {
+		return "", false, nil
+	}
+
+	return getFilePath(id), true, nil
+}
+
+/* DeleteUserExports deletes the exports of an user with their archives */
+func DeleteUserExports(userId string) {
+	current, err := db.DbConn.GetExports(userId)
+
+	if err != nil {
+		log.Printf("Error getting the exports of the user %s: %s", userId, err.Error())
+
+		return
+	}
+
+	for _, export := range current {
+		deleteExport(export.Id)
+	}
+}
+
+/* StartCleanupJob deletes periodically the expired exports with their archives */
+func StartCleanupJob() {
+	go func() {
+		for range time.Tick(time.Minute) {
+			expired, err := db.DbConn.GetExpiredExports(time.Now())
+
+			if err != nil {
+				log.Println("Error getting the expired exports: " + err.Error())
+
+				continue
+			}
+
+			for _, export := range expired {
+				deleteExport(export.Id)
+			}
+		}
+	}()
+}
+
+// region "Helpers"
+
+func generate(export mr.Export, user mr.User) {
+	err := writeArchive(export.Id, user)
+	export.Status = StatusReady
+
+	if err != nil {
+		log.Printf("Error generating the export %s: %s", export.Id, err.Error())
+
+		os.Remove(getFilePath(export.Id))
+
+		export.Status = StatusFailed
+		export.Error = err.Error()
+	}
+
+	isFound, err := db.DbConn.ModifyExport(export)
+
+	if err != nil {
+		log.Printf("Error saving the export %s: %s", export.Id, err.Error())
+	}
+
+	// The export could have been deleted with the user while it was generated
+	if err != nil || !isFound {
+		os.Remove(getFilePath(export.Id))
+	}
+}
+
+func checkInterrupted(export *mr.Export) error {
+	// The generation is lost if the server is restarted, so the export would be pending forever
+	timeout := helpers.GetDurationEnv("EXPORT_TIMEOUT", time.Hour)
+
+	if export.Status != StatusPending || export.CreatedAt.Add(timeout).After(time.Now()) {
+		return nil
+	}
+
+	export.Status = StatusFailed
+	export.Error = "the generation of the export was interrupted"
+
+	_, err := db.DbConn.ModifyExport(*export)
+
+	return err
+}
+
+func writeArchive(id string, user mr.User) error {
+	err := os.MkdirAll(exportsPath, 0755)
+
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(getFilePath(id))
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	archive := zip.NewWriter(f)
+
+	profile, isFound, err := db.DbConn.GetProfile(user.Id)
+
+	if err != nil {
+		return err
+	}
+
+	if !isFound {
+		return fmt.Errorf("the user %s does not exist", user.Id)
+	}
+
+	profile.Password = ""
+
+	err = writeJson(archive, "profile.json", profile)
+
+	if err != nil {
+		return err
+	}
+
+	tweets, err := getTweets(user.Id)
+
+	if err != nil {
+		return err
+	}
+
+	err = writeJson(archive, "tweets.json", tweets)
+
+	if err != nil {
+		return err
+	}
+
+	following, err := getAllUsers(user.Id, db.DbConn.GetFollowing)
+
+	if err != nil {
+		return err
+	}
+
+	err = writeJson(archive, "following.json", following)
+
+	if err != nil {
+		return err
+	}
+
+	followers, err := getAllUsers(user.Id, db.DbConn.GetFollowers)
+
+	if err != nil {
+		return err
+	}
+
+	err = writeJson(archive, "followers.json", followers)
+
+	if err != nil {
+		return err
+	}
+
+	for _, tweet := range tweets {
+		for i, reference := range tweet.Media {
+			err = writeImage(archive, fmt.Sprintf("media/%s-%d", tweet.Id, i), "uploads/media", reference)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = writeImage(archive, "avatar", "uploads/avatars", profile.Avatar)
+
+	if err != nil {
+		return err
+	}
+
+	err = writeImage(archive, "banner", "uploads/banners", profile.Banner)
+
+	if err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+func getTweets(userId string) ([]exportTweet, error) {
+	results := []exportTweet{}
+
+	tweets, err := db.DbConn.GetUserTweets(userId)
+
+	if err != nil {
+		return results, err
+	}
+
+	for _, tweet := range tweets {
+		var revisions []*mr.TweetRevision
+
+		// Only the edited tweets have previous messages
+		if tweet.Edited {
+			revisions, err = db.DbConn.GetTweetRevisions(tweet.Id)
+
+			if err != nil {
+				return results, err
+			}
+		}
+
+		results = append(results, exportTweet{
+			Id:            tweet.Id,
+			Message:       tweet.Message,
+			Date:          tweet.Date,
+			InReplyTo:     tweet.InReplyTo,
+			RetweetOf:     tweet.RetweetOf,
+			QuotedTweetId: tweet.QuotedTweetId,
+			EditedAt:      tweet.EditedAt,
+			Revisions:     revisions,
+			Media:         tweet.Media,
+			Deleted:       !tweet.Active,
+		})
+	}
+
+	return results, nil
+}
+
+func getAllUsers(userId string, getPage func(string, int64, int64, string) ([]*mr.User, int64, error)) ([]*mr.User, error) {
+	results := []*mr.User{}
+
+	for page := int64(1); ; page++ {
+		users, total, err := getPage(userId, page, pageSize, "")
+
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, users...)
+
+		if len(users) < pageSize || int64(len(results)) >= total {
+			return results, nil
+		}
+	}
+}
+
+func writeJson(archive *zip.Writer, name string, value any) error {
+	w, err := archive.Create(name)
+
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(value)
+}
+
+func writeImage(archive *zip.Writer, name string, localPath string, filename string) error {
+	var reader io.ReadCloser
+
+	if len(filename) < 1 {
+		return nil
+	}
+
+	isRemote, _ := strconv.ParseBool(os.Getenv("FILES_REMOTE"))
+
+	if isRemote {
+		// A stalled download would block the generation of the export forever
+		client := http.Client{Timeout: helpers.GetDurationEnv("EXPORT_DOWNLOAD_TIMEOUT", time.Minute)}
+		resp, err := client.Get(filename)
+
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+
+			return fmt.Errorf("error downloading the %s: %s", name, resp.Status)
+		}
+
+		reader = resp.Body
+	} else {
+		f, err := helpers.GetFileLocal(fmt.Sprintf("%s/%s", localPath, filename))
+
+		// The missing files are skipped, the rest of the data is still exported
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		reader = f
+	}
+
+	defer reader.Close()
+
+	w, err := archive.Create(name + path.Ext(filename))
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, reader)
+
+	return err
+}
+
+func getFilePath(id string) string {
+	return fmt.Sprintf("%s/%s.zip", exportsPath, id)
+}
+
+func deleteExport(id string) {
+	err := os.Remove(getFilePath(id))
+
+	if err != nil && !os.IsNotExist(err) {
+		log.Printf("Error removing the export %s: %s", id, err.Error())
+	}
+
+	err = db.DbConn.DeleteExport(id)
+
+	if err != nil {
+		log.Printf("Error deleting the export %s: %s", id, err.Error())
+	}
+}
+
+// endregion
@@ -5,6 +5,8 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"routes/admin"
+	"routes/keys"
 	"routes/relations"
 	"routes/tweets"
 	"routes/users"
@@ -24,9 +26,33 @@ func SetHandlers() {
 	// Register Home page service
 	router.HandleFunc("/", home)
 
+	// Register Keys endpoints
+	keys.GetJwks(router)
+
 	// Register Users endpoints
 	users.Insert(router)
 	users.Login(router)
+	users.LoginTwoFactor(router)
+	users.RefreshToken(router)
+	users.Logout(router)
+	users.GetCsrfToken(router)
+	users.VerifyEmail(router)
+	users.ResendVerification(router)
+	users.ForgotPassword(router)
+	users.ResetPassword(router)
+	users.ChangePassword(router)
+	users.DeleteAccount(router)
+	users.ExportData(router)
+	users.GetExport(router)
+	users.DownloadExport(router)
+	users.EnrollTotp(router)
+	users.ConfirmTotp(router)
+	users.DisableTotp(router)
+	users.CreateAccessToken(router)
+	users.GetAccessTokens(router)
+	users.DeleteAccessToken(router)
+	users.GetSessions(router)
+	users.DeleteSession(router)
 	users.GetProfile(router)
 	users.Modify(router)
 	users.UploadAvatar(router)
@@ -36,8 +62,33 @@ func SetHandlers() {
 
 	// Register Tweets endpoints
 	tweets.Insert(router)
+	tweets.Modify(router)
+	tweets.GetHistory(router)
+	tweets.GetMedia(router)
+	tweets.GetScheduledTweets(router)
+	tweets.DeleteScheduledTweet(router)
+	tweets.InsertDraft(router)
+	tweets.GetDrafts(router)
+	tweets.ModifyDraft(router)
+	tweets.DeleteDraft(router)
+	tweets.PublishDraft(router)
+	tweets.GetDraftMedia(router)
 	tweets.GetTweets(router)
+	tweets.GetReplies(router)
+	tweets.GetConversation(router)
+	tweets.GetHashtagTweets(router)
+	tweets.GetMentionTweets(router)
+	tweets.SearchTweets(router)
+	tweets.GetTrends(router)
 	tweets.Delete(router)
+	tweets.Retweet(router)
+	tweets.DeleteRetweet(router)
+	tweets.Like(router)
+	tweets.Unlike(router)
+	tweets.GetLikedTweets(router)
+	tweets.Bookmark(router)
+	tweets.DeleteBookmark(router)
+	tweets.GetBookmarkedTweets(router)
 
 	// Register Relations endpoints
 	relations.Insert(router)
@@ -46,6 +97,10 @@ func SetHandlers() {
 	relations.GetUsers(router)
 	relations.GetFollowingTweets(router)
 
+	// Register Admin endpoints
+	admin.UnlockLogin(router)
+	admin.SetRole(router)
+
 	PORT := os.Getenv("PORT")
 	handler := cors.AllowAll().Handler(router)
 
@@ -0,0 +1,31 @@
+package helpers
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+/* GetClientIp Returns the IP address of the client, using the X-Forwarded-For header only when the TRUST_PROXY option is enabled */
+func GetClientIp(r *http.Request) string {
+	isTrusted, _ := strconv.ParseBool(os.Getenv("TRUST_PROXY"))
+
+	if isTrusted {
+		forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+
+		// The last address is the one added by the proxy, the previous ones can be forged by the client
+		if ip := strings.TrimSpace(forwarded[len(forwarded)-1]); len(ip) > 0 {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
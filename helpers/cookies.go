@@ -0,0 +1,68 @@
+package helpers
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	/* TokenCookieName is the name of the cookie with the access token */
+	TokenCookieName = "token"
+
+	/* RefreshCookieName is the name of the cookie with the refresh token */
+	RefreshCookieName = "refresh_token"
+
+	/* CsrfCookieName is the name of the cookie with the CSRF token, readable by the browser clients */
+	CsrfCookieName = "csrf_token"
+
+	/* CsrfHeaderName is the header where the clients send back the CSRF token */
+	CsrfHeaderName = "X-CSRF-Token"
+)
+
+/* NewCookie Returns a cookie with the attributes set in the COOKIE_SECURE, COOKIE_SAMESITE, COOKIE_DOMAIN and COOKIE_PATH options */
+func NewCookie(name string, value string, expires time.Time, isHttpOnly bool) *http.Cookie {
+	isSecure, err := strconv.ParseBool(os.Getenv("COOKIE_SECURE"))
+
+	if err != nil {
+		isSecure = true
+	}
+
+	path := os.Getenv("COOKIE_PATH")
+
+	if len(path) < 1 {
+		path = "/"
+	}
+
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   os.Getenv("COOKIE_DOMAIN"),
+		Expires:  expires,
+		Secure:   isSecure,
+		HttpOnly: isHttpOnly,
+		SameSite: getSameSite(),
+	}
+}
+
+/* NewExpiredCookie Returns a cookie that removes the cookie with the same name from the client */
+func NewExpiredCookie(name string) *http.Cookie {
+	cookie := NewCookie(name, "", time.Unix(0, 0), true)
+	cookie.MaxAge = -1
+
+	return cookie
+}
+
+func getSameSite() http.SameSite {
+	switch strings.ToLower(os.Getenv("COOKIE_SAMESITE")) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
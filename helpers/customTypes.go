@@ -11,3 +11,6 @@ type RequestPageKey struct{}
 
 /* RequestQueryIdKey Defines a type to use as a limit param key in a request context */
 type RequestLimitKey struct{}
+
+/* RequestPrincipalKey Defines a type to use as an authenticated principal key in a request context */
+type RequestPrincipalKey struct{}
@@ -0,0 +1,17 @@
+package helpers
+
+import (
+	"os"
+	"time"
+)
+
+/* GetDurationEnv Returns the duration set in an environment variable or the default value if it is not valid */
+func GetDurationEnv(name string, defaultValue time.Duration) time.Duration {
+	duration, err := time.ParseDuration(os.Getenv(name))
+
+	if err != nil || duration < 0 {
+		return defaultValue
+	}
+
+	return duration
+}
@@ -0,0 +1,81 @@
+package helpers
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+/* GetHashtags Returns the distinct hashtags of a message in lowercase and without the # */
+func GetHashtags(message string) []string {
+	var hashtags []string
+
+	isFound := make(map[string]bool)
+
+	for _, hashtag := range getPrefixedWords(message, '#', isHashtagRune) {
+		// The numbers, as in #1, are not hashtags
+		if strings.IndexFunc(hashtag, unicode.IsLetter) >= 0 && !isFound[hashtag] {
+			isFound[hashtag] = true
+			hashtags = append(hashtags, hashtag)
+		}
+	}
+
+	return hashtags
+}
+
+/* GetTrendsWindow Returns the time window used to compute the trending hashtags */
+func GetTrendsWindow() time.Duration {
+	return GetDurationEnv("TRENDS_WINDOW", 24*time.Hour)
+}
+
+/* GetTrendsBucket Returns the start of the time bucket where the hashtags of a tweet with the date are counted */
+func GetTrendsBucket(date time.Time) time.Time {
+	return date.UTC().Truncate(getTrendsBucketSize())
+}
+
+/* GetTrendsBucketExpiration Returns when the counts of a bucket are out of the trends window */
+func GetTrendsBucketExpiration(bucket time.Time) time.Time {
+	return bucket.Add(GetTrendsWindow() + getTrendsBucketSize())
+}
+
+func getTrendsBucketSize() time.Duration {
+	size := GetDurationEnv("TRENDS_BUCKET", time.Hour)
+
+	if size <= 0 {
+		return time.Hour
+	}
+
+	return size
+}
+
+/* getPrefixedWords Returns the words of a message starting with the prefix in lowercase and without it */
+func getPrefixedWords(message string, prefix rune, isWordRune func(rune) bool) []string {
+	var words []string
+
+	runes := []rune(message)
+
+	for i := 0; i < len(runes); i++ {
+		// A prefix inside a word, as in an url fragment or an email, does not start a new word
+		if runes[i] != prefix || (i > 0 && isWordRune(runes[i-1])) {
+			continue
+		}
+
+		end := i + 1
+
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+
+		if end > i+1 {
+			words = append(words, strings.ToLower(string(runes[i+1:end])))
+		}
+
+		i = end - 1
+	}
+
+	return words
+}
+
+func isHashtagRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
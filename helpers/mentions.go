@@ -0,0 +1,54 @@
+package helpers
+
+import (
+	"strings"
+	"unicode"
+)
+
+/* MaxMentions is the maximum number of users that can be mentioned in a tweet */
+const MaxMentions = 10
+
+/* GetMentions Returns the distinct handles mentioned in a message in lowercase and without the @ */
+func GetMentions(message string) []string {
+	var mentions []string
+
+	isFound := make(map[string]bool)
+
+	for _, mention := range getPrefixedWords(message, '@', isMentionRune) {
+		// The punctuation at the end of a sentence is not part of the handle
+		mention = strings.TrimRight(mention, ".-")
+
+		if len(mention) > 0 && !isFound[mention] && len(mentions) < MaxMentions {
+			isFound[mention] = true
+			mentions = append(mentions, mention)
+		}
+	}
+
+	return mentions
+}
+
+/* GetHandle returns the base handle of an user, the local part of the email in lowercase without the symbols that can not be mentioned */
+func GetHandle(email string) string {
+	localPart, _, _ := strings.Cut(strings.ToLower(email), "@")
+	handle := strings.Map(func(r rune) rune {
+		if isMentionRune(r) {
+			return r
+		}
+
+		return -1
+	}, localPart)
+
+	// The same trimming as in the mentions, so that the handle can always be mentioned
+	handle = strings.TrimRight(handle, ".-")
+
+	if len(handle) < 1 {
+		handle = "user"
+	}
+
+	return handle
+}
+
+/* isMentionRune allows the symbols of the email local parts, which are used as handles */
+func isMentionRune(r rune) bool {
+	return strings.ContainsRune("_.-+", r) || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
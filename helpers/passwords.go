@@ -0,0 +1,75 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+/* ValidatePassword Validates that a password satisfies the minimum length and the configured strength rules */
+func ValidatePassword(password string) error {
+	minLength, err := strconv.Atoi(os.Getenv("PASSWORD_MIN_LENGTH"))
+
+	if err != nil || minLength < 6 {
+		minLength = 6
+	}
+
+	if len(password) < minLength {
+		return fmt.Errorf("the password must have at least %d characters", minLength)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+
+	for _, c := range password {
+		switch {
+		case unicode.IsUpper(c):
+			hasUpper = true
+		case unicode.IsLower(c):
+			hasLower = true
+		case unicode.IsDigit(c):
+			hasDigit = true
+		case unicode.IsPunct(c) || unicode.IsSymbol(c):
+			hasSymbol = true
+		}
+	}
+
+	rules := []struct {
+		env     string
+		isValid bool
+		message string
+	}{
+		{"PASSWORD_REQUIRE_UPPER", hasUpper, "the password must have at least one uppercase letter"},
+		{"PASSWORD_REQUIRE_LOWER", hasLower, "the password must have at least one lowercase letter"},
+		{"PASSWORD_REQUIRE_DIGIT", hasDigit, "the password must have at least one digit"},
+		{"PASSWORD_REQUIRE_SYMBOL", hasSymbol, "the password must have at least one symbol"},
+	}
+
+	for _, rule := range rules {
+		isRequired, _ := strconv.ParseBool(os.Getenv(rule.env))
+
+		if isRequired && !rule.isValid {
+			return errors.New(rule.message)
+		}
+	}
+
+	return nil
+}
+
+/* GetBcryptCost Returns the bcrypt cost used to encrypt the passwords */
+func GetBcryptCost() int {
+	// Minimum - cost: 6
+	// Common user - cost: 6
+	// Admin user - cost: 8
+
+	cost, err := strconv.Atoi(os.Getenv("BCRYPT_COST"))
+
+	if err != nil || cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return 8
+	}
+
+	return cost
+}
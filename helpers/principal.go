@@ -0,0 +1,14 @@
+package helpers
+
+import (
+	"net/http"
+
+	mr "models/request"
+)
+
+/* GetPrincipal Returns the authenticated principal stored in the request context */
+func GetPrincipal(r *http.Request) mr.Principal {
+	principal, _ := r.Context().Value(RequestPrincipalKey{}).(mr.Principal)
+
+	return principal
+}
@@ -0,0 +1,28 @@
+package helpers
+
+import (
+	"strings"
+	"unicode"
+)
+
+/* MaxSearchTerms is the maximum number of words used from a search query */
+const MaxSearchTerms = 10
+
+/* GetSearchTerms Returns the distinct words of a search query in lowercase, the operators of the DBs are discarded */
+func GetSearchTerms(query string) []string {
+	var terms []string
+
+	isFound := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		if !isFound[word] && len(terms) < MaxSearchTerms {
+			isFound[word] = true
+			terms = append(terms, word)
+		}
+	}
+
+	return terms
+}
@@ -0,0 +1,27 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+/* GenerateToken Returns a random hex encoded token of the specified bytes size */
+func GenerateToken(size int) (string, error) {
+	bytes := make([]byte, size)
+
+	_, err := rand.Read(bytes)
+
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(bytes), nil
+}
+
+/* HashToken Returns the hex encoded SHA-256 hash of a token, used to store tokens at rest */
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(hash[:])
+}
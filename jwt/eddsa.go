@@ -0,0 +1,59 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"errors"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+/* signingMethodEdDSA implements the EdDSA (Ed25519) signing method, which is not included in the jwt library */
+type signingMethodEdDSA struct{}
+
+/* SigningMethodEdDSA is the EdDSA signing method */
+var SigningMethodEdDSA = &signingMethodEdDSA{}
+
+func init() {
+	jwt.RegisterSigningMethod(SigningMethodEdDSA.Alg(), func() jwt.SigningMethod {
+		return SigningMethodEdDSA
+	})
+}
+
+/* Alg returns the name of the algorithm */
+func (m *signingMethodEdDSA) Alg() string {
+	return "EdDSA"
+}
+
+/* Verify verifies the signature with an ed25519.PublicKey */
+func (m *signingMethodEdDSA) Verify(signingString string, signature string, key any) error {
+	publicKey, ok := key.(ed25519.PublicKey)
+
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+
+	sig, err := jwt.DecodeSegment(signature)
+
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(publicKey, []byte(signingString), sig) {
+		return errors.New("EdDSA verification failed")
+	}
+
+	return nil
+}
+
+/* Sign signs the string with an ed25519.PrivateKey */
+func (m *signingMethodEdDSA) Sign(signingString string, key any) (string, error) {
+	privateKey, ok := key.(ed25519.PrivateKey)
+
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+
+	sig := ed25519.Sign(privateKey, []byte(signingString))
+
+	return jwt.EncodeSegment(sig), nil
+}
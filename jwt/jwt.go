@@ -2,25 +2,35 @@ package jwt
 
 import (
 	"db"
+	"helpers"
 	mr "models/request"
-	"os"
 
 	"errors"
+	"log"
 	"strings"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
 )
 
-/* Email is used in all the endpoints */
-var Email string
+const (
+	accessTokenType    = "access"
+	refreshTokenType   = "refresh"
+	challengeTokenType = "challenge"
+)
 
-/* UserId is the User Id that is going to be used in all the endpoints */
-var UserId string
+/* sessionTouchInterval is the minimum time between the updates of the last time a session was seen */
+const sessionTouchInterval = time.Minute
 
 /* GenerateJWT generates the encryption with JWT */
-func GenerateJWT(user mr.User) (string, error) {
-	myKey := []byte(os.Getenv("JWT_SIGNING_KEY"))
+func GenerateJWT(user mr.User, sessionId string) (string, error) {
+	tokenId, err := helpers.GenerateToken(16)
+
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
 	payload := jwt.MapClaims{
 		"email":      user.Email,
 		"name":       user.Name,
@@ -30,11 +40,16 @@ func GenerateJWT(user mr.User) (string, error) {
 		"location":   user.Location,
 		"web_site":   user.WebSite,
 		"_id":        user.Id,
-		"exp":        time.Now().Add(time.Hour * 24).Unix(),
+		"jti":        tokenId,
+		"sid":        sessionId,
+		"role":       user.Role,
+		"type":       accessTokenType,
+		"iat":        now.Unix(),
+		"iat_ms":     now.UnixMilli(),
+		"exp":        now.Add(GetAccessTTL()).Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
-	tokenStr, err := token.SignedString(myKey)
+	tokenStr, err := signToken(payload)
 
 	if err != nil {
 		return tokenStr, err
@@ -43,31 +58,120 @@ func GenerateJWT(user mr.User) (string, error) {
 	return tokenStr, nil
 }
 
+/* GenerateRefreshJWT generates a long-lived JWT that can only be used to obtain a new access token */
+func GenerateRefreshJWT(user mr.User, sessionId string) (string, error) {
+	return generateTypedJWT(user, sessionId, refreshTokenType, GetRefreshTTL())
+}
+
+/* GenerateChallengeJWT generates a short-lived JWT that can only be exchanged for the tokens with a two-factor code */
+func GenerateChallengeJWT(user mr.User) (string, error) {
+	return generateTypedJWT(user, "", challengeTokenType, helpers.GetDurationEnv("TOTP_CHALLENGE_TTL", 5*time.Minute))
+}
+
 /* ProcessJWT process the JWT received in the request */
 func ProcessJWT(token string) (*mr.Claim, error) {
-	myKey := []byte(os.Getenv("JWT_SIGNING_KEY"))
-	claims := &mr.Claim{}
 	splitToken := strings.Split(token, "Bearer")
 
 	if len(splitToken) != 2 {
-		return claims, errors.New("token format invalid")
+		return &mr.Claim{}, errors.New("token format invalid")
 	}
 
 	token = strings.TrimSpace(splitToken[1])
 
-	tkn, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
-		return myKey, nil
-	})
+	return parseJWT(token, accessTokenType)
+}
+
+/* ProcessCookieJWT process the JWT received in the token cookie */
+func ProcessCookieJWT(token string) (*mr.Claim, error) {
+	return parseJWT(strings.TrimSpace(token), accessTokenType)
+}
+
+/* ProcessRefreshJWT process a refresh JWT received in the body of the request */
+func ProcessRefreshJWT(token string) (*mr.Claim, error) {
+	return parseJWT(strings.TrimSpace(token), refreshTokenType)
+}
+
+/* ProcessChallengeJWT process a two-factor challenge JWT received in the body of the request */
+func ProcessChallengeJWT(token string) (*mr.Claim, error) {
+	return parseJWT(strings.TrimSpace(token), challengeTokenType)
+}
+
+/* RevokeJWT adds the token to the revocation list until it expires, it returns false if the token had already been revoked */
+func RevokeJWT(claims *mr.Claim) (bool, error) {
+	if len(claims.TokenId) < 1 {
+		return false, errors.New("the token has no id")
+	}
+
+	revokedToken := mr.RevokedToken{
+		TokenId:   claims.TokenId,
+		UserId:    claims.Id,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}
+
+	return db.DbConn.RevokeToken(revokedToken)
+}
+
+/* GetAccessTTL returns the lifetime of the access tokens */
+func GetAccessTTL() time.Duration {
+	return helpers.GetDurationEnv("JWT_ACCESS_TTL", 15*time.Minute)
+}
+
+/* GetRefreshTTL returns the lifetime of the refresh tokens */
+func GetRefreshTTL() time.Duration {
+	return helpers.GetDurationEnv("JWT_REFRESH_TTL", 7*24*time.Hour)
+}
+
+func generateTypedJWT(user mr.User, sessionId string, tokenType string, ttl time.Duration) (string, error) {
+	tokenId, err := helpers.GenerateToken(16)
+
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	payload := jwt.MapClaims{
+		"email":  user.Email,
+		"_id":    user.Id,
+		"jti":    tokenId,
+		"sid":    sessionId,
+		"role":   user.Role,
+		"type":   tokenType,
+		"iat":    now.Unix(),
+		"iat_ms": now.UnixMilli(),
+		"exp":    now.Add(ttl).Unix(),
+	}
+
+	return signToken(payload)
+}
+
+func parseJWT(token string, tokenType string) (*mr.Claim, error) {
+	claims := &mr.Claim{}
+
+	tkn, err := jwt.ParseWithClaims(token, claims, getVerificationKey)
+
+	if err != nil {
+		return claims, err
+	}
 
 	if !tkn.Valid {
 		return claims, errors.New("invalid token")
 	}
 
+	if claims.Type != tokenType {
+		return claims, errors.New("invalid token type")
+	}
+
+	isRevoked, err := db.DbConn.IsTokenRevoked(claims.TokenId)
+
 	if err != nil {
 		return claims, err
 	}
 
-	isFound, _, err := db.DbConn.IsUser(claims.Email)
+	if isRevoked {
+		return claims, errors.New("the token has been revoked")
+	}
+
+	isFound, user, err := db.DbConn.IsUser(claims.Email)
 
 	if err != nil {
 		return claims, err
@@ -77,8 +181,53 @@ func ProcessJWT(token string) (*mr.Claim, error) {
 		return claims, errors.New("user not found")
 	}
 
-	Email = claims.Email
-	UserId = claims.Id
+	// The tokens issued in the same second as the revocation are told apart by the milliseconds
+	issuedAtMs := claims.IssuedAtMs
+
+	if issuedAtMs < 1 {
+		issuedAtMs = claims.IssuedAt * 1000
+	}
+
+	if issuedAtMs < user.TokensRevokedAt.UnixMilli() {
+		return claims, errors.New("the token has been revoked")
+	}
+
+	// The role can change during the lifetime of the token, so the current one is used
+	claims.Role = user.Role
+
+	// The tokens issued before the sessions were recorded do not have a session
+	if len(claims.SessionId) > 0 {
+		err = validateSession(claims)
+
+		if err != nil {
+			return claims, err
+		}
+	}
 
 	return claims, nil
 }
+
+func validateSession(claims *mr.Claim) error {
+	session, isFound, err := db.DbConn.GetSession(claims.SessionId)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	if !isFound || session.UserId != claims.Id || session.ExpiresAt.Before(now) {
+		return errors.New("the session has been revoked")
+	}
+
+	// The last seen time is not updated on every request to avoid a write per request
+	if now.Sub(session.LastSeenAt) > sessionTouchInterval {
+		err = db.DbConn.TouchSession(session.Id, now, now.Add(GetRefreshTTL()))
+
+		if err != nil {
+			log.Println("Error updating the session: " + err.Error())
+		}
+	}
+
+	return nil
+}
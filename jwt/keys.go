@@ -0,0 +1,331 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"helpers"
+	res "models/response"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+/* key is a signing or verification key identified by its kid */
+type key struct {
+	Id         string
+	Method     jwt.SigningMethod
+	PrivateKey any
+	PublicKey  any
+}
+
+/* keyStore holds the keys loaded from the keys directory */
+type keyStore struct {
+	mutex      sync.RWMutex
+	signingKey *key
+	keys       map[string]*key
+}
+
+var store keyStore
+
+/* LoadKeys loads the asymmetric keys from the JWT_KEYS_DIR directory and reloads them periodically */
+func LoadKeys() error {
+	if isSymmetric() {
+		if len(os.Getenv("JWT_SIGNING_KEY")) < 1 {
+			return errors.New("the JWT_SIGNING_KEY is required for the HS256 signing method")
+		}
+
+		return nil
+	}
+
+	err := reloadKeys()
+
+	if err != nil {
+		return err
+	}
+
+	interval := helpers.GetDurationEnv("JWT_KEYS_RELOAD", time.Minute)
+
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	go func() {
+		for range time.Tick(interval) {
+			err := reloadKeys()
+
+			if err != nil {
+				log.Println("Error reloading the JWT keys: " + err.Error())
+			}
+		}
+	}()
+
+	return nil
+}
+
+/* GetJWKS returns the public keys used to verify the tokens as a JSON Web Key Set */
+func GetJWKS() res.JwksResponse {
+	response := res.JwksResponse{
+		Keys: []res.Jwk{},
+	}
+
+	if isSymmetric() {
+		return response
+	}
+
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	for _, k := range store.keys {
+		jwk := res.Jwk{
+			Kid: k.Id,
+			Use: "sig",
+			Alg: k.Method.Alg(),
+		}
+
+		switch publicKey := k.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		}
+
+		response.Keys = append(response.Keys, jwk)
+	}
+
+	sort.Slice(response.Keys, func(i, j int) bool {
+		return response.Keys[i].Kid < response.Keys[j].Kid
+	})
+
+	return response
+}
+
+// region "Helpers"
+
+func signToken(payload jwt.MapClaims) (string, error) {
+	if isSymmetric() {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
+
+		return token.SignedString([]byte(os.Getenv("JWT_SIGNING_KEY")))
+	}
+
+	store.mutex.RLock()
+	signingKey := store.signingKey
+	store.mutex.RUnlock()
+
+	if signingKey == nil {
+		return "", errors.New("there is no signing key loaded")
+	}
+
+	token := jwt.NewWithClaims(signingKey.Method, payload)
+
+	token.Header["kid"] = signingKey.Id
+
+	return token.SignedString(signingKey.PrivateKey)
+}
+
+func getVerificationKey(t *jwt.Token) (any, error) {
+	if isSymmetric() {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %s", t.Method.Alg())
+		}
+
+		return []byte(os.Getenv("JWT_SIGNING_KEY")), nil
+	}
+
+	kid, _ := t.Header["kid"].(string)
+
+	store.mutex.RLock()
+	k, isFound := store.keys[kid]
+	store.mutex.RUnlock()
+
+	if !isFound {
+		return nil, fmt.Errorf("unknown signing key: %s", kid)
+	}
+
+	// The algorithm of the token must be the one of the key to avoid algorithm confusion attacks
+	if t.Method.Alg() != k.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %s", t.Method.Alg())
+	}
+
+	return k.PublicKey, nil
+}
+
+func reloadKeys() error {
+	method := getSigningMethod()
+
+	if method == nil {
+		return fmt.Errorf("invalid signing method: %s", os.Getenv("JWT_SIGNING_METHOD"))
+	}
+
+	dir := os.Getenv("JWT_KEYS_DIR")
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		return fmt.Errorf("error reading the keys directory: %s", err.Error())
+	}
+
+	keys := make(map[string]*key)
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if entry.IsDir() || !strings.HasSuffix(name, ".pem") {
+			continue
+		}
+
+		// <kid>.pem holds a private key and <kid>.pub.pem a public key only used to verify
+		kid := strings.TrimSuffix(strings.TrimSuffix(name, ".pem"), ".pub")
+		k, err := readKey(filepath.Join(dir, name), kid, method)
+
+		if err != nil {
+			log.Printf("Ignoring the key file %s: %s", name, err.Error())
+
+			continue
+		}
+
+		if existing, isFound := keys[kid]; isFound && existing.PrivateKey != nil {
+			continue
+		}
+
+		keys[kid] = k
+	}
+
+	signingKey, err := getSigningKey(keys)
+
+	if err != nil {
+		return err
+	}
+
+	store.mutex.Lock()
+	store.keys = keys
+	store.signingKey = signingKey
+	store.mutex.Unlock()
+
+	return nil
+}
+
+func readKey(path string, kid string, method jwt.SigningMethod) (*key, error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+
+	if block == nil {
+		return nil, errors.New("invalid PEM file")
+	}
+
+	var parsed any
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		err = fmt.Errorf("unsupported PEM block type: %s", block.Type)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	k := &key{
+		Id:     kid,
+		Method: method,
+	}
+
+	switch parsedKey := parsed.(type) {
+	case *rsa.PrivateKey:
+		k.PrivateKey = parsedKey
+		k.PublicKey = &parsedKey.PublicKey
+	case *rsa.PublicKey:
+		k.PublicKey = parsedKey
+	case ed25519.PrivateKey:
+		k.PrivateKey = parsedKey
+		k.PublicKey = parsedKey.Public()
+	case ed25519.PublicKey:
+		k.PublicKey = parsedKey
+	default:
+		return nil, errors.New("unsupported key type")
+	}
+
+	_, isRsa := k.PublicKey.(*rsa.PublicKey)
+
+	if isRsa != (method == jwt.SigningMethodRS256) {
+		return nil, fmt.Errorf("the key cannot be used with the %s signing method", method.Alg())
+	}
+
+	return k, nil
+}
+
+func getSigningKey(keys map[string]*key) (*key, error) {
+	kid := os.Getenv("JWT_SIGNING_KID")
+
+	if len(kid) > 0 {
+		k, isFound := keys[kid]
+
+		if !isFound || k.PrivateKey == nil {
+			return nil, fmt.Errorf("the private key %s was not found", kid)
+		}
+
+		return k, nil
+	}
+
+	// Without an explicit kid, the last private key in alphabetical order is used
+	var signingKey *key
+
+	for _, k := range keys {
+		if k.PrivateKey != nil && (signingKey == nil || k.Id > signingKey.Id) {
+			signingKey = k
+		}
+	}
+
+	if signingKey == nil {
+		return nil, errors.New("there is no private key in the keys directory")
+	}
+
+	return signingKey, nil
+}
+
+func getSigningMethod() jwt.SigningMethod {
+	switch os.Getenv("JWT_SIGNING_METHOD") {
+	case "", "HS256":
+		return jwt.SigningMethodHS256
+	case "RS256":
+		return jwt.SigningMethodRS256
+	case "EdDSA":
+		return SigningMethodEdDSA
+	default:
+		return nil
+	}
+}
+
+func isSymmetric() bool {
+	return getSigningMethod() == jwt.SigningMethodHS256
+}
+
+// endregion
@@ -0,0 +1,49 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+/* MailerFile writes the emails to a file, or to the log if there is no file, for development and tests */
+type MailerFile struct {
+	Path  string
+	mutex sync.Mutex
+}
+
+/* Send writes the email to the file or to the log */
+func (m *MailerFile) Send(to string, subject string, body string) error {
+	message := fmt.Sprintf("To: %s\nSubject: %s\nDate: %s\n\n%s\n\n", to, subject, time.Now().Format(time.RFC1123Z), body)
+
+	if len(m.Path) < 1 {
+		log.Print("Email sent:\n" + message)
+
+		return nil
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	f, err := os.OpenFile(m.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+
+	defer func() {
+		if f != nil {
+			f.Close()
+		}
+	}()
+
+	if err != nil {
+		return fmt.Errorf("error opening the mail file: %s", err.Error())
+	}
+
+	_, err = f.WriteString(message)
+
+	if err != nil {
+		return fmt.Errorf("error writing the email: %s", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package mailer
+
+import (
+	"log"
+	"os"
+)
+
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
+
+/* MailConn is the mailer used to send the emails */
+var MailConn Mailer
+
+/* SetMailer sets the mailer for the mailer type */
+func SetMailer(mailerType string) {
+	switch mailerType {
+	case "Smtp":
+		MailConn = new(MailerSmtp)
+	case "", "File":
+		MailConn = &MailerFile{
+			Path: os.Getenv("MAILER_FILE"),
+		}
+	default:
+		log.Fatal("No mailer selected")
+	}
+}
@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"fmt"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+type MailerSmtp struct{}
+
+/* Send sends an email through the SMTP server */
+func (m *MailerSmtp) Send(to string, subject string, body string) error {
+	host := os.Getenv("SMTP_HOST")
+	port := os.Getenv("SMTP_PORT")
+	user := os.Getenv("SMTP_USER")
+	pass := os.Getenv("SMTP_PASS")
+	from := os.Getenv("SMTP_FROM")
+
+	var auth smtp.Auth
+
+	if len(user) > 0 {
+		auth = smtp.PlainAuth("", user, pass, host)
+	}
+
+	headers := []string{
+		fmt.Sprintf("From: %s", from),
+		fmt.Sprintf("To: %s", to),
+		fmt.Sprintf("Subject: %s", subject),
+		fmt.Sprintf("Date: %s", time.Now().Format(time.RFC1123Z)),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"utf-8\"",
+	}
+	message := strings.Join(headers, "\r\n") + "\r\n\r\n" + body
+
+	err := smtp.SendMail(fmt.Sprintf("%s:%s", host, port), auth, from, []string{to}, []byte(message))
+
+	if err != nil {
+		return fmt.Errorf("error sending the email: %s", err.Error())
+	}
+
+	return nil
+}
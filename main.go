@@ -1,8 +1,15 @@
 package main
 
 import (
+	"accounts"
 	"db"
+	"exports"
 	"handlers"
+	"jwt"
+	"mailer"
+	mr "models/request"
+	"scheduler"
+	"throttle"
 
 	"log"
 	"os"
@@ -33,5 +40,46 @@ func main() {
 
 	log.Println("Connection successful to the DB")
 
+	mailer.SetMailer(os.Getenv("MAILER_TYPE"))
+	throttle.SetStore(os.Getenv("LOGIN_ATTEMPTS_STORE"))
+
+	err := jwt.LoadKeys()
+
+	if err != nil {
+		log.Fatal("Error loading the JWT keys: " + err.Error())
+	}
+
+	err = setAdmin(os.Getenv("ADMIN_EMAIL"))
+
+	if err != nil {
+		log.Fatal("Error setting the administrator: " + err.Error())
+	}
+
+	accounts.StartDeletionJob()
+	exports.StartCleanupJob()
+	scheduler.StartPublishJob()
+
 	handlers.SetHandlers()
 }
+
+/* setAdmin gives the admin role to the registered and verified user with the email, so the first administrator can be set */
+func setAdmin(email string) error {
+	if len(email) < 1 {
+		return nil
+	}
+
+	isFound, user, err := db.DbConn.IsUser(email)
+
+	if err != nil || !isFound || user.Role == mr.RoleAdmin {
+		return err
+	}
+
+	// Anyone could register the address first, so only its verified owner is promoted
+	if !user.Verified {
+		log.Println("The administrator " + email + " has not verified the email, the role is not given")
+
+		return nil
+	}
+
+	return db.DbConn.SetRole(user.Id, mr.RoleAdmin)
+}
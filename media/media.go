@@ -0,0 +1,179 @@
+package media
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"helpers"
+)
+
+/* MaxFiles is the maximum number of images attached to a tweet */
+const MaxFiles = 4
+
+const mediaPath = "uploads/media"
+
+/* extensions are the image types accepted, detected from the content of the files */
+var extensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+/* Validate checks the number, the size and the type of the images added to a tweet which already has some */
+func Validate(headers []*multipart.FileHeader, current int) error {
+	if current+len(headers) > MaxFiles {
+		return fmt.Errorf("a tweet can have up to %d images", MaxFiles)
+	}
+
+	maxSize, err := strconv.ParseInt(os.Getenv("MEDIA_MAX_SIZE"), 10, 64)
+
+	if err != nil || maxSize <= 0 {
+		maxSize = 5 << 20
+	}
+
+	for _, header := range headers {
+		if header.Size > maxSize {
+			return fmt.Errorf("the image %s exceeds the maximum size of %d bytes", header.Filename, maxSize)
+		}
+
+		_, err := getExtension(header)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/* Upload stores the images of an user's tweet, it returns their references in the same order */
+func Upload(userId string, headers []*multipart.FileHeader) ([]string, error) {
+	var references []string
+
+	isRemote, _ := strconv.ParseBool(os.Getenv("FILES_REMOTE"))
+
+	for _, header := range headers {
+		reference, err := upload(userId, header, isRemote)
+
+		// The images already stored are removed, so a failed tweet does not leave orphan files
+		if err != nil {
+			Delete(references)
+
+			return nil, err
+		}
+
+		references = append(references, reference)
+	}
+
+	return references, nil
+}
+
+/* Delete removes the images of a tweet, the errors are logged so the rest of the images are still removed */
+func Delete(references []string) {
+	isRemote, _ := strconv.ParseBool(os.Getenv("FILES_REMOTE"))
+
+	for _, reference := range references {
+		var err error
+
+		if isRemote {
+			err = helpers.DestroyRemote(getPublicId(reference))
+		} else {
+			err = os.Remove(GetLocalPath(reference))
+		}
+
+		if err != nil && !os.IsNotExist(err) {
+			log.Printf("Error removing the image %s: %s", reference, err.Error())
+		}
+	}
+}
+
+/* GetLocalPath returns the path of an image stored in the local server */
+func GetLocalPath(reference string) string {
+	return fmt.Sprintf("%s/%s", mediaPath, reference)
+}
+
+// region "Helpers"
+
+func upload(userId string, header *multipart.FileHeader, isRemote bool) (string, error) {
+	extension, err := getExtension(header)
+
+	if err != nil {
+		return "", err
+	}
+
+	token, err := helpers.GenerateToken(16)
+
+	if err != nil {
+		return "", err
+	}
+
+	file, err := header.Open()
+
+	if err != nil {
+		return "", err
+	}
+
+	defer file.Close()
+
+	// The remote images are referenced by their url and the local ones by their filename
+	if isRemote {
+		return helpers.UploadRemote(file, fmt.Sprintf("%s-media-%s", userId, token))
+	}
+
+	err = os.MkdirAll(mediaPath, 0755)
+
+	if err != nil {
+		return "", err
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", userId, token, extension)
+	err = helpers.UploadFileLocal(GetLocalPath(filename), file)
+
+	if err != nil {
+		return "", err
+	}
+
+	return filename, nil
+}
+
+/* getExtension detects the type of an image from its content, the extension of the filename is not trusted */
+func getExtension(header *multipart.FileHeader) (string, error) {
+	file, err := header.Open()
+
+	if err != nil {
+		return "", err
+	}
+
+	defer file.Close()
+
+	buffer := make([]byte, 512)
+	n, err := io.ReadFull(file, buffer)
+
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", err
+	}
+
+	extension, isFound := extensions[http.DetectContentType(buffer[:n])]
+
+	if !isFound {
+		return "", fmt.Errorf("the file %s is not a supported image", header.Filename)
+	}
+
+	return extension, nil
+}
+
+/* getPublicId gets the id of a remote image from its url */
+func getPublicId(reference string) string {
+	return strings.TrimSuffix(path.Base(reference), path.Ext(reference))
+}
+
+// endregion
@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"helpers"
+	"net/http"
+)
+
+/* RequireRoles Validates that the authenticated user has one of the roles, so it must be chained after the token validation */
+func RequireRoles(roles ...string) helpers.Middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			principal := helpers.GetPrincipal(r)
+
+			for _, role := range roles {
+				if principal.Role == role {
+					next.ServeHTTP(w, r)
+
+					return
+				}
+			}
+
+			http.Error(w, "Forbidden", http.StatusForbidden)
+		}
+	}
+}
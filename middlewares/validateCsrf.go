@@ -0,0 +1,34 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"helpers"
+	"net/http"
+)
+
+/* ValidateCSRF Validates the double-submit CSRF token of the state-changing requests authenticated with the token cookie */
+func ValidateCSRF(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// The Authorization header cannot be sent by other sites, so only the cookie needs the protection
+		_, tokenErr := r.Cookie(helpers.TokenCookieName)
+		_, refreshErr := r.Cookie(helpers.RefreshCookieName)
+		isCookieAuth := (tokenErr == nil || refreshErr == nil) && len(r.Header.Get("Authorization")) < 1
+
+		if !isCookieAuth || r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		csrfCookie, err := r.Cookie(helpers.CsrfCookieName)
+		csrfHeader := r.Header.Get(helpers.CsrfHeaderName)
+
+		if err != nil || len(csrfCookie.Value) < 1 || subtle.ConstantTimeCompare([]byte(csrfCookie.Value), []byte(csrfHeader)) != 1 {
+			http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
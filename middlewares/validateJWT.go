@@ -1,14 +1,27 @@
 package middlewares
 
 import (
+	"context"
+	"helpers"
 	"jwt"
+	mr "models/request"
 	"net/http"
 )
 
-/* ValidateJWT allows to validate the JWT from the request */
+/* ValidateJWT allows to validate the JWT from the Authorization header or, if there is no header, from the token cookie */
 func ValidateJWT(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		_, err := jwt.ProcessJWT(r.Header.Get("Authorization"))
+		var claims *mr.Claim
+		var err error
+
+		authorization := r.Header.Get("Authorization")
+		cookie, cookieErr := r.Cookie(helpers.TokenCookieName)
+
+		if len(authorization) < 1 && cookieErr == nil {
+			claims, err = jwt.ProcessCookieJWT(cookie.Value)
+		} else {
+			claims, err = jwt.ProcessJWT(authorization)
+		}
 
 		if err != nil {
 			http.Error(w, "Error on the Token: "+err.Error(), http.StatusBadRequest)
@@ -16,6 +29,17 @@ func ValidateJWT(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
+		principal := mr.Principal{
+			Id:      claims.Id,
+			Email:   claims.Email,
+			TokenId: claims.TokenId,
+			Role:    claims.Role,
+			Claims:  claims,
+		}
+
+		ctx := context.WithValue(r.Context(), helpers.RequestPrincipalKey{}, principal)
+		r = r.Clone(ctx)
+
 		next.ServeHTTP(w, r)
 	}
 }
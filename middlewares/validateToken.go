@@ -0,0 +1,90 @@
+package middlewares
+
+import (
+	"context"
+	"db"
+	"helpers"
+	mr "models/request"
+	"net/http"
+	"strings"
+	"time"
+)
+
+/* ValidateToken allows to validate the JWT or a personal access token with the scope from the request */
+func ValidateToken(scope string) helpers.Middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			token := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer"))
+
+			if !strings.HasPrefix(token, mr.AccessTokenPrefix) {
+				ValidateJWT(next).ServeHTTP(w, r)
+
+				return
+			}
+
+			accessToken, isFound, err := db.DbConn.GetAccessToken(helpers.HashToken(token))
+
+			if err != nil {
+				http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+				return
+			}
+
+			if !isFound || (!accessToken.ExpiresAt.IsZero() && accessToken.ExpiresAt.Before(time.Now())) {
+				http.Error(w, "Error on the Token: invalid access token", http.StatusBadRequest)
+
+				return
+			}
+
+			if !hasScope(accessToken.Scopes, scope) {
+				http.Error(w, "The access token does not have the scope "+scope, http.StatusForbidden)
+
+				return
+			}
+
+			profile, isFound, err := db.DbConn.GetProfile(accessToken.UserId)
+
+			if err != nil {
+				http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+				return
+			}
+
+			if !isFound {
+				http.Error(w, "Error on the Token: user not found", http.StatusBadRequest)
+
+				return
+			}
+
+			// The access tokens are kept during the grace period, so they are rejected until the deletion is cancelled
+			if !profile.DeleteAt.IsZero() {
+				http.Error(w, "Error on the Token: the account is scheduled for deletion", http.StatusUnauthorized)
+
+				return
+			}
+
+			principal := mr.Principal{
+				Id:      accessToken.UserId,
+				Email:   profile.Email,
+				TokenId: accessToken.Id,
+				Role:    profile.Role,
+				Scopes:  accessToken.Scopes,
+			}
+
+			ctx := context.WithValue(r.Context(), helpers.RequestPrincipalKey{}, principal)
+			r = r.Clone(ctx)
+
+			next.ServeHTTP(w, r)
+		}
+	}
+}
+
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,39 @@
+package middlewares
+
+import (
+	"db"
+	"helpers"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+/* ValidateVerifiedEmail Validates that the user has verified the email address when the REQUIRE_VERIFIED_EMAIL option is enabled */
+func ValidateVerifiedEmail(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		isRequired, _ := strconv.ParseBool(os.Getenv("REQUIRE_VERIFIED_EMAIL"))
+
+		if !isRequired {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		principal := helpers.GetPrincipal(r)
+		profile, isFound, err := db.DbConn.GetProfile(principal.Id)
+
+		if err != nil {
+			http.Error(w, "Something went wrong: "+err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		if !isFound || !profile.Verified {
+			http.Error(w, "The email address must be verified", http.StatusForbidden)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
@@ -5,21 +5,39 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"db"
+	"helpers"
 	mr "models/request"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type DbMock struct {
-	Users          map[string]*mr.User
-	Tweets         []*mr.Tweet
-	Relations      []*mr.Relation
-	IsError        bool
-	IsConnected    bool
-	IdUserCounter  int
-	IdTweetCounter int
+	Users                map[string]*mr.User
+	Tweets               []*mr.Tweet
+	Relations            []*mr.Relation
+	Likes                []*mr.Like
+	Bookmarks            []*mr.Bookmark
+	Revisions            []*mr.TweetRevision
+	ScheduledTweets      []*mr.ScheduledTweet
+	Drafts               []*mr.Draft
+	RevokedTokens        map[string]*mr.RevokedToken
+	ActionTokens         map[string]*mr.ActionToken
+	AccessTokens         []*mr.AccessToken
+	Sessions             []*mr.Session
+	Exports              []*mr.Export
+	LoginAttempts        map[string]*mr.LoginAttempt
+	IsError              bool
+	IsConnected          bool
+	IdUserCounter        int
+	IdTweetCounter       int
+	IdAccessTokenCounter int
+	IdSessionCounter     int
+	IdScheduledCounter   int
+	IdDraftCounter       int
+	IdExportCounter      int
 }
 
 // region "Connection"
@@ -30,6 +48,9 @@ func (db *DbMock) Connect() error {
 	}
 
 	db.Users = make(map[string]*mr.User)
+	db.RevokedTokens = make(map[string]*mr.RevokedToken)
+	db.ActionTokens = make(map[string]*mr.ActionToken)
+	db.LoginAttempts = make(map[string]*mr.LoginAttempt)
 
 	return nil
 }
@@ -71,6 +92,7 @@ func (db *DbMock) InsertUser(user mr.User) (string, error) {
 
 	user.Id = strconv.Itoa(db.IdUserCounter)
 	user.Password = string(bytes)
+	user.Handle = db.getAvailableHandle(helpers.GetHandle(user.Email))
 
 	db.Users[user.Id] = &user
 
@@ -109,6 +131,208 @@ func (db *DbMock) ModifyRegistry(id string, user mr.User) error {
 	return nil
 }
 
+func (db *DbMock) ModifyPassword(id string, password string) error {
+	if db.IsError {
+		return fmt.Errorf("Error!")
+	}
+
+	cost := 8
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+
+	if err != nil {
+		return fmt.Errorf("Error!")
+	}
+
+	db.Users[id].Password = string(bytes)
+
+	return nil
+}
+
+func (db *DbMock) VerifyUser(id string) error {
+	if db.IsError {
+		return fmt.Errorf("Error!")
+	}
+
+	db.Users[id].Verified = true
+
+	return nil
+}
+
+func (db *DbMock) RevokeUserTokens(id string) error {
+	if db.IsError {
+		return fmt.Errorf("Error!")
+	}
+
+	db.Users[id].TokensRevokedAt = time.Now()
+
+	return nil
+}
+
+func (db *DbMock) SetTotp(id string, secret string, isEnabled bool) error {
+	if db.IsError {
+		return fmt.Errorf("Error!")
+	}
+
+	db.Users[id].TotpSecret = secret
+	db.Users[id].TotpEnabled = isEnabled
+
+	return nil
+}
+
+func (db *DbMock) UseTotpCounter(id string, counter int64) (bool, error) {
+	if db.IsError {
+		return false, fmt.Errorf("Error!")
+	}
+
+	if db.Users[id].TotpLastCounter >= counter {
+		return false, nil
+	}
+
+	db.Users[id].TotpLastCounter = counter
+
+	return true, nil
+}
+
+func (db *DbMock) SetRole(id string, role string) error {
+	if db.IsError {
+		return fmt.Errorf("Error!")
+	}
+
+	db.Users[id].Role = role
+
+	return nil
+}
+
+func (db *DbMock) ScheduleUserDeletion(id string, deleteAt time.Time) error {
+	if db.IsError {
+		return fmt.Errorf("Error!")
+	}
+
+	db.Users[id].DeleteAt = deleteAt
+
+	return nil
+}
+
+func (db *DbMock) GetUsersToDelete(before time.Time) ([]*mr.User, error) {
+	var results []*mr.User
+
+	if db.IsError {
+		return results, fmt.Errorf("Error!")
+	}
+
+	for _, u := range db.Users {
+		if !u.DeleteAt.IsZero() && !u.DeleteAt.After(before) {
+			results = append(results, u)
+		}
+	}
+
+	return results, nil
+}
+
+func (db *DbMock) DeleteUser(id string) error {
+	var tweets []*mr.Tweet
+	var relations []*mr.Relation
+	var likes []*mr.Like
+	var revisions []*mr.TweetRevision
+	var accessTokens []*mr.AccessToken
+	var sessions []*mr.Session
+	var scheduledTweets []*mr.ScheduledTweet
+	var drafts []*mr.Draft
+	var bookmarks []*mr.Bookmark
+
+	if db.IsError {
+		return fmt.Errorf("Error!")
+	}
+
+	for _, t := range db.Tweets {
+		if t.UserId != id {
+			tweets = append(tweets, t)
+		} else if t.Active {
+			db.updateTweetCounters(*t, -1)
+		}
+	}
+
+	for _, l := range db.Likes {
+		if l.UserId == id {
+			db.updateLikeCount(l.TweetId, -1)
+
+			continue
+		}
+
+		if tweet, isFound, _ := db.GetTweet(l.TweetId); !isFound || tweet.UserId != id {
+			likes = append(likes, l)
+		}
+	}
+
+	for _, r := range db.Revisions {
+		if tweet, isFound, _ := db.GetTweet(r.TweetId); !isFound || tweet.UserId != id {
+			revisions = append(revisions, r)
+		}
+	}
+
+	for _, r := range db.Relations {
+		if r.UserId != id && r.UserRelationId != id {
+			relations = append(relations, r)
+		}
+	}
+
+	for _, token := range db.AccessTokens {
+		if token.UserId != id {
+			accessTokens = append(accessTokens, token)
+		}
+	}
+
+	for _, session := range db.Sessions {
+		if session.UserId != id {
+			sessions = append(sessions, session)
+		}
+	}
+
+	for _, scheduled := range db.ScheduledTweets {
+		if scheduled.UserId != id {
+			scheduledTweets = append(scheduledTweets, scheduled)
+		}
+	}
+
+	for _, draft := range db.Drafts {
+		if draft.UserId != id {
+			drafts = append(drafts, draft)
+		}
+	}
+
+	for _, bookmark := range db.Bookmarks {
+		if bookmark.UserId != id {
+			bookmarks = append(bookmarks, bookmark)
+		}
+	}
+
+	for tokenId, token := range db.RevokedTokens {
+		if token.UserId == id {
+			delete(db.RevokedTokens, tokenId)
+		}
+	}
+
+	for hash, token := range db.ActionTokens {
+		if token.UserId == id {
+			delete(db.ActionTokens, hash)
+		}
+	}
+
+	db.Tweets = tweets
+	db.Relations = relations
+	db.Likes = likes
+	db.Revisions = revisions
+	db.AccessTokens = accessTokens
+	db.Sessions = sessions
+	db.ScheduledTweets = scheduledTweets
+	db.Drafts = drafts
+	db.Bookmarks = bookmarks
+
+	delete(db.Users, id)
+
+	return nil
+}
+
 func (db *DbMock) TryLogin(email string, password string) (mr.User, bool) {
 	var requestModel mr.User
 
@@ -151,268 +375,1400 @@ func (db *DbMock) DeleteTweet(id string, userId string) error {
 		return fmt.Errorf("invalid operation - cannot delete a non-owner tweet")
 	}
 
-	tweetModel.Active = false
+	if tweetModel.Active {
+		db.updateTweetCounters(*tweetModel, -1)
+	}
+
+	tweetModel.Active = false
+
+	return nil
+}
+
+func (db *DbMock) GetTweets(id string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	tweets := []*mr.Tweet{}
+
+	if db.IsError {
+		return tweets, 0, fmt.Errorf("Error!")
+	}
+
+	offset := (page - 1) * limit
+	count := int64(0)
+	total := int64(0)
+
+	for _, tweet := range db.Tweets {
+		if tweet.UserId == id && tweet.Active {
+			total++
+		}
+	}
+
+	sort.Slice(db.Tweets, func(i, j int) bool {
+		return db.Tweets[i].Date.After(db.Tweets[j].Date)
+	})
+
+	for _, tweet := range db.Tweets[offset:] {
+		if tweet.UserId == id && tweet.Active && count < limit {
+			tweets = append(tweets, tweet)
+			count++
+		}
+	}
+
+	return tweets, total, nil
+}
+
+func (db *DbMock) GetUserTweets(id string) ([]*mr.Tweet, error) {
+	tweets := []*mr.Tweet{}
+
+	if db.IsError {
+		return tweets, fmt.Errorf("Error!")
+	}
+
+	for _, tweet := range db.Tweets {
+		if tweet.UserId == id {
+			tweets = append(tweets, tweet)
+		}
+	}
+
+	sort.Slice(tweets, func(i, j int) bool {
+		return tweets[i].Date.After(tweets[j].Date)
+	})
+
+	return tweets, nil
+}
+
+func (db *DbMock) InsertTweet(tweet mr.Tweet) (string, error) {
+	db.IdTweetCounter++
+
+	tweet.Id = strconv.Itoa(db.IdTweetCounter)
+	db.Tweets = append(db.Tweets, &tweet)
+
+	db.updateTweetCounters(tweet, 1)
+
+	return tweet.Id, nil
+}
+
+func (db *DbMock) InsertRetweet(tweet mr.Tweet) (string, bool, error) {
+	if db.IsError {
+		return "", false, fmt.Errorf("Error!")
+	}
+
+	for _, t := range db.Tweets {
+		if t.UserId == tweet.UserId && t.RetweetOf == tweet.RetweetOf && t.Active {
+			return "", false, nil
+		}
+	}
+
+	id, err := db.InsertTweet(tweet)
+
+	return id, err == nil, err
+}
+
+func (db *DbMock) DeleteRetweet(userId string, tweetId string) (bool, error) {
+	if db.IsError {
+		return false, fmt.Errorf("Error!")
+	}
+
+	for _, t := range db.Tweets {
+		if t.UserId == userId && t.RetweetOf == tweetId && t.Active {
+			err := db.DeleteTweet(t.Id, userId)
+
+			return err == nil, err
+		}
+	}
+
+	return false, nil
+}
+
+func (db *DbMock) GetTweet(id string) (mr.Tweet, bool, error) {
+	if db.IsError {
+		return mr.Tweet{}, false, fmt.Errorf("Error!")
+	}
+
+	for _, t := range db.Tweets {
+		if t.Id == id {
+			return *t, true, nil
+		}
+	}
+
+	return mr.Tweet{}, false, nil
+}
+
+func (db *DbMock) GetReplies(id string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	var replies []*mr.Tweet
+	var results []*mr.Tweet
+
+	if db.IsError {
+		return results, 0, fmt.Errorf("Error!")
+	}
+
+	for _, t := range db.Tweets {
+		if t.InReplyTo == id && t.Active {
+			replies = append(replies, t)
+		}
+	}
+
+	sort.Slice(replies, func(i, j int) bool {
+		return replies[i].Date.Before(replies[j].Date)
+	})
+
+	total := int64(len(replies))
+	offset := (page - 1) * limit
+
+	for i := offset; i < total && i < offset+limit; i++ {
+		results = append(results, replies[i])
+	}
+
+	return results, total, nil
+}
+
+func (db *DbMock) GetConversation(id string) ([]*mr.Tweet, error) {
+	var results []*mr.Tweet
+
+	if db.IsError {
+		return results, fmt.Errorf("Error!")
+	}
+
+	for _, t := range db.Tweets {
+		if t.Id == id || t.ConversationId == id {
+			results = append(results, t)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Date.Before(results[j].Date)
+	})
+
+	return results, nil
+}
+
+func (db *DbMock) ModifyTweet(tweet mr.Tweet) (bool, error) {
+	if db.IsError {
+		return false, fmt.Errorf("Error!")
+	}
+
+	for _, t := range db.Tweets {
+		if t.Id == tweet.Id && t.UserId == tweet.UserId && t.Active {
+			revision := &mr.TweetRevision{
+				Id:      strconv.Itoa(len(db.Revisions) + 1),
+				TweetId: t.Id,
+				Message: t.Message,
+				Date:    t.Date,
+			}
+
+			if t.EditedAt != nil {
+				revision.Date = *t.EditedAt
+			}
+
+			db.Revisions = append(db.Revisions, revision)
+			t.Message = tweet.Message
+			t.Edited = true
+			t.EditedAt = tweet.EditedAt
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (db *DbMock) GetTweetRevisions(id string) ([]*mr.TweetRevision, error) {
+	var results []*mr.TweetRevision
+
+	if db.IsError {
+		return results, fmt.Errorf("Error!")
+	}
+
+	for _, r := range db.Revisions {
+		if r.TweetId == id {
+			results = append(results, r)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Date.Before(results[j].Date)
+	})
+
+	return results, nil
+}
+
+func (db *DbMock) GetHashtagTweets(tag string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	var tweets []*mr.Tweet
+	var results []*mr.Tweet
+
+	if db.IsError {
+		return results, 0, fmt.Errorf("Error!")
+	}
+
+	for _, t := range db.Tweets {
+		if !t.Active {
+			continue
+		}
+
+		for _, hashtag := range helpers.GetHashtags(t.Message) {
+			if hashtag == tag {
+				tweets = append(tweets, t)
+			}
+		}
+	}
+
+	sort.Slice(tweets, func(i, j int) bool {
+		return tweets[i].Date.After(tweets[j].Date)
+	})
+
+	total := int64(len(tweets))
+	offset := (page - 1) * limit
+
+	for i := offset; i < total && i < offset+limit; i++ {
+		results = append(results, tweets[i])
+	}
+
+	return results, total, nil
+}
+
+func (db *DbMock) GetMentionTweets(userId string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	var tweets []*mr.Tweet
+	var results []*mr.Tweet
+
+	if db.IsError {
+		return results, 0, fmt.Errorf("Error!")
+	}
+
+	for _, t := range db.Tweets {
+		if t.Active && db.isMentioned(t, userId) {
+			tweets = append(tweets, t)
+		}
+	}
+
+	sort.Slice(tweets, func(i, j int) bool {
+		return tweets[i].Date.After(tweets[j].Date)
+	})
+
+	total := int64(len(tweets))
+	offset := (page - 1) * limit
+
+	for i := offset; i < total && i < offset+limit; i++ {
+		results = append(results, tweets[i])
+	}
+
+	return results, total, nil
+}
+
+func (db *DbMock) SearchTweets(terms []string, isNewestFirst bool, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	var tweets []*mr.Tweet
+	var results []*mr.Tweet
+
+	if db.IsError {
+		return results, 0, fmt.Errorf("Error!")
+	}
+
+	scores := make(map[*mr.Tweet]int)
+
+	for _, t := range db.Tweets {
+		if !t.Active {
+			continue
+		}
+
+		words := helpers.GetSearchTerms(t.Message)
+
+		for _, term := range terms {
+			for _, word := range words {
+				if word == term {
+					scores[t]++
+
+					break
+				}
+			}
+		}
+
+		if scores[t] > 0 {
+			tweets = append(tweets, t)
+		}
+	}
+
+	sort.Slice(tweets, func(i, j int) bool {
+		if !isNewestFirst && scores[tweets[i]] != scores[tweets[j]] {
+			return scores[tweets[i]] > scores[tweets[j]]
+		}
+
+		return tweets[i].Date.After(tweets[j].Date)
+	})
+
+	total := int64(len(tweets))
+	offset := (page - 1) * limit
+
+	for i := offset; i < total && i < offset+limit; i++ {
+		results = append(results, tweets[i])
+	}
+
+	return results, total, nil
+}
+
+func (db *DbMock) GetTrends(since time.Time, limit int64) ([]*mr.Trend, error) {
+	var results []*mr.Trend
+
+	if db.IsError {
+		return results, fmt.Errorf("Error!")
+	}
+
+	counts := make(map[string]int64)
+
+	for _, t := range db.Tweets {
+		if !t.Active || t.Date.Before(since) {
+			continue
+		}
+
+		for _, hashtag := range helpers.GetHashtags(t.Message) {
+			counts[hashtag]++
+		}
+	}
+
+	for tag, count := range counts {
+		results = append(results, &mr.Trend{Tag: tag, Count: count})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Count == results[j].Count {
+			return results[i].Tag < results[j].Tag
+		}
+
+		return results[i].Count > results[j].Count
+	})
+
+	if int64(len(results)) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// endregion
+
+// region "Likes"
+
+func (db *DbMock) InsertLike(like mr.Like) (bool, error) {
+	if db.IsError {
+		return false, fmt.Errorf("Error!")
+	}
+
+	for _, l := range db.Likes {
+		if l.UserId == like.UserId && l.TweetId == like.TweetId {
+			return false, nil
+		}
+	}
+
+	db.Likes = append(db.Likes, &like)
+	db.updateLikeCount(like.TweetId, 1)
+
+	return true, nil
+}
+
+func (db *DbMock) DeleteLike(userId string, tweetId string) (bool, error) {
+	if db.IsError {
+		return false, fmt.Errorf("Error!")
+	}
+
+	for i, l := range db.Likes {
+		if l.UserId == userId && l.TweetId == tweetId {
+			db.Likes = append(db.Likes[:i], db.Likes[i+1:]...)
+			db.updateLikeCount(tweetId, -1)
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (db *DbMock) GetLikedTweetIds(userId string, tweetIds []string) ([]string, error) {
+	var results []string
+
+	if db.IsError {
+		return results, fmt.Errorf("Error!")
+	}
+
+	for _, l := range db.Likes {
+		if l.UserId != userId {
+			continue
+		}
+
+		for _, tweetId := range tweetIds {
+			if l.TweetId == tweetId {
+				results = append(results, tweetId)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func (db *DbMock) GetLikedTweets(userId string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	var likes []*mr.Like
+	var results []*mr.Tweet
+
+	if db.IsError {
+		return results, 0, fmt.Errorf("Error!")
+	}
+
+	for _, l := range db.Likes {
+		if tweet, isFound, _ := db.GetTweet(l.TweetId); l.UserId == userId && isFound && tweet.Active {
+			likes = append(likes, l)
+		}
+	}
+
+	sort.Slice(likes, func(i, j int) bool {
+		return likes[i].Date.After(likes[j].Date)
+	})
+
+	total := int64(len(likes))
+	offset := (page - 1) * limit
+
+	for i := offset; i < total && i < offset+limit; i++ {
+		tweet, _, _ := db.GetTweet(likes[i].TweetId)
+		results = append(results, &tweet)
+	}
+
+	return results, total, nil
+}
+
+// endregion
+
+// region "Bookmarks"
+
+func (db *DbMock) InsertBookmark(bookmark mr.Bookmark) (bool, error) {
+	if db.IsError {
+		return false, fmt.Errorf("Error!")
+	}
+
+	for _, b := range db.Bookmarks {
+		if b.UserId == bookmark.UserId && b.TweetId == bookmark.TweetId {
+			return false, nil
+		}
+	}
+
+	db.Bookmarks = append(db.Bookmarks, &bookmark)
+
+	return true, nil
+}
+
+func (db *DbMock) DeleteBookmark(userId string, tweetId string) (bool, error) {
+	if db.IsError {
+		return false, fmt.Errorf("Error!")
+	}
+
+	for i, b := range db.Bookmarks {
+		if b.UserId == userId && b.TweetId == tweetId {
+			db.Bookmarks = append(db.Bookmarks[:i], db.Bookmarks[i+1:]...)
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (db *DbMock) GetBookmarkedTweets(userId string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
+	var bookmarks []*mr.Bookmark
+	var results []*mr.Tweet
+
+	if db.IsError {
+		return results, 0, fmt.Errorf("Error!")
+	}
+
+	for _, b := range db.Bookmarks {
+		if b.UserId == userId {
+			bookmarks = append(bookmarks, b)
+		}
+	}
+
+	sort.Slice(bookmarks, func(i, j int) bool {
+		return bookmarks[i].Date.After(bookmarks[j].Date)
+	})
+
+	total := int64(len(bookmarks))
+	offset := (page - 1) * limit
+
+	for i := offset; i < total && i < offset+limit; i++ {
+		tweet, isFound, _ := db.GetTweet(bookmarks[i].TweetId)
+
+		if !isFound {
+			tweet = mr.Tweet{Id: bookmarks[i].TweetId}
+		}
+
+		results = append(results, &tweet)
+	}
+
+	return results, total, nil
+}
+
+// endregion
+
+// region "Scheduled tweets"
+
+func (db *DbMock) InsertScheduledTweet(tweet mr.ScheduledTweet) (string, error) {
+	if db.IsError {
+		return "", fmt.Errorf("Error!")
+	}
+
+	db.IdScheduledCounter++
+
+	tweet.Id = strconv.Itoa(db.IdScheduledCounter)
+	db.ScheduledTweets = append(db.ScheduledTweets, &tweet)
+
+	return tweet.Id, nil
+}
+
+func (db *DbMock) GetScheduledTweets(userId string, page int64, limit int64) ([]*mr.ScheduledTweet, int64, error) {
+	var tweets []*mr.ScheduledTweet
+	var results []*mr.ScheduledTweet
+
+	if db.IsError {
+		return results, 0, fmt.Errorf("Error!")
+	}
+
+	for _, scheduled := range db.ScheduledTweets {
+		if scheduled.UserId == userId {
+			tweets = append(tweets, scheduled)
+		}
+	}
+
+	sort.Slice(tweets, func(i, j int) bool {
+		return tweets[i].PublishAt.Before(tweets[j].PublishAt)
+	})
+
+	total := int64(len(tweets))
+
+	if limit < 1 {
+		return tweets, total, nil
+	}
+
+	offset := (page - 1) * limit
+
+	for i := offset; i < total && i < offset+limit; i++ {
+		results = append(results, tweets[i])
+	}
+
+	return results, total, nil
+}
+
+func (db *DbMock) GetDueScheduledTweets(before time.Time, limit int64) ([]*mr.ScheduledTweet, error) {
+	var results []*mr.ScheduledTweet
+
+	if db.IsError {
+		return results, fmt.Errorf("Error!")
+	}
+
+	for _, scheduled := range db.ScheduledTweets {
+		if !scheduled.PublishAt.After(before) {
+			results = append(results, scheduled)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].PublishAt.Before(results[j].PublishAt)
+	})
+
+	if limit > 0 && int64(len(results)) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+func (db *DbMock) DeleteScheduledTweet(id string, userId string) (mr.ScheduledTweet, bool, error) {
+	if db.IsError {
+		return mr.ScheduledTweet{}, false, fmt.Errorf("Error!")
+	}
+
+	for i, scheduled := range db.ScheduledTweets {
+		if scheduled.Id == id && scheduled.UserId == userId {
+			db.ScheduledTweets = append(db.ScheduledTweets[:i], db.ScheduledTweets[i+1:]...)
+
+			return *scheduled, true, nil
+		}
+	}
+
+	return mr.ScheduledTweet{}, false, nil
+}
+
+func (db *DbMock) PublishScheduledTweet(id string, tweet mr.Tweet) (string, bool, error) {
+	if db.IsError {
+		return "", false, fmt.Errorf("Error!")
+	}
+
+	for i, scheduled := range db.ScheduledTweets {
+		if scheduled.Id == id {
+			db.ScheduledTweets = append(db.ScheduledTweets[:i], db.ScheduledTweets[i+1:]...)
+			tweetId, err := db.InsertTweet(tweet)
+
+			return tweetId, err == nil, err
+		}
+	}
+
+	return "", false, nil
+}
+
+// endregion
+
+// region "Drafts"
+
+func (db *DbMock) InsertDraft(draft mr.Draft) (string, error) {
+	if db.IsError {
+		return "", fmt.Errorf("Error!")
+	}
+
+	db.IdDraftCounter++
+
+	draft.Id = strconv.Itoa(db.IdDraftCounter)
+	db.Drafts = append(db.Drafts, &draft)
+
+	return draft.Id, nil
+}
+
+func (db *DbMock) GetDrafts(userId string, page int64, limit int64) ([]*mr.Draft, int64, error) {
+	var drafts []*mr.Draft
+	var results []*mr.Draft
+
+	if db.IsError {
+		return results, 0, fmt.Errorf("Error!")
+	}
+
+	for _, draft := range db.Drafts {
+		if draft.UserId == userId {
+			drafts = append(drafts, draft)
+		}
+	}
+
+	sort.Slice(drafts, func(i, j int) bool {
+		return drafts[i].Date.After(drafts[j].Date)
+	})
+
+	total := int64(len(drafts))
+
+	if limit < 1 {
+		return drafts, total, nil
+	}
+
+	offset := (page - 1) * limit
+
+	for i := offset; i < total && i < offset+limit; i++ {
+		results = append(results, drafts[i])
+	}
+
+	return results, total, nil
+}
+
+func (db *DbMock) GetDraft(id string, userId string) (mr.Draft, bool, error) {
+	if db.IsError {
+		return mr.Draft{}, false, fmt.Errorf("Error!")
+	}
+
+	for _, draft := range db.Drafts {
+		if draft.Id == id && draft.UserId == userId {
+			return *draft, true, nil
+		}
+	}
+
+	return mr.Draft{}, false, nil
+}
+
+func (db *DbMock) ModifyDraft(draft mr.Draft) (bool, error) {
+	if db.IsError {
+		return false, fmt.Errorf("Error!")
+	}
+
+	for i, d := range db.Drafts {
+		if d.Id == draft.Id && d.UserId == draft.UserId {
+			db.Drafts[i] = &draft
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (db *DbMock) DeleteDraft(id string, userId string) (mr.Draft, bool, error) {
+	if db.IsError {
+		return mr.Draft{}, false, fmt.Errorf("Error!")
+	}
+
+	for i, draft := range db.Drafts {
+		if draft.Id == id && draft.UserId == userId {
+			db.Drafts = append(db.Drafts[:i], db.Drafts[i+1:]...)
+
+			return *draft, true, nil
+		}
+	}
+
+	return mr.Draft{}, false, nil
+}
+
+func (db *DbMock) PublishDraft(id string, userId string, tweet mr.Tweet) (string, bool, error) {
+	_, isFound, err := db.DeleteDraft(id, userId)
+
+	if err != nil || !isFound {
+		return "", false, err
+	}
+
+	tweetId, err := db.InsertTweet(tweet)
+
+	return tweetId, err == nil, err
+}
+
+// endregion
+
+// region "Relations"
+
+func (db *DbMock) IsRelation(relation mr.Relation) (bool, mr.Relation, error) {
+	var relationModel mr.Relation
+	var isFound bool
+
+	if db.IsError {
+		return false, relationModel, fmt.Errorf("Error!")
+	}
+
+	for _, r := range db.Relations {
+		if relation.UserId == r.UserId && relation.UserRelationId == r.UserRelationId {
+			isFound = true
+			relationModel = *r
+		}
+	}
+
+	return isFound, relationModel, nil
+}
+
+func (db *DbMock) InsertRelation(relation mr.Relation) error {
+	_, isFound, err := db.GetProfile(relation.UserRelationId)
+
+	if err != nil {
+		return err
+	}
+
+	if !isFound {
+		return fmt.Errorf("no registry found in the DB")
+	}
+
+	isFound, relationDb, err := db.IsRelation(relation)
+
+	if err != nil {
+		return err
+	}
+
+	if !isFound {
+		db.Relations = append(db.Relations, &mr.Relation{
+			UserId:         relationDb.UserId,
+			UserRelationId: relationDb.UserRelationId})
+
+		return nil
+	}
+
+	if relationDb.Active {
+		return fmt.Errorf("the relation with the user id = %s already exists", relation.UserRelationId)
+	}
+
+	db.updateRelation(relationDb, true)
+
+	return nil
+}
+
+func (db *DbMock) DeleteRelation(relation mr.Relation) error {
+	if db.IsError {
+		return fmt.Errorf("Error!")
+	}
+
+	db.updateRelation(relation, false)
+
+	return nil
+}
+
+func (db *DbMock) GetUsers(id string, page int64, limit int64, search string, searchType string) ([]*mr.User, int64, error) {
+	var results []*mr.User
+	var users []*mr.User
+	var total int64
+
+	if db.IsError {
+		return results, total, fmt.Errorf("Error!")
+	}
+
+	for _, u := range db.Users {
+		if strings.Contains(strings.ToLower(u.Name), strings.ToLower(search)) {
+			users = append(users, u)
+		}
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].BirthDate.After(users[j].BirthDate)
+	})
+
+	offset := (page - 1) * limit
+	usersCount := int64(0)
+	usersFiltered := []*mr.User{}
+
+	for _, u := range users[offset:] {
+		if usersCount < limit {
+			usersFiltered = append(usersFiltered, u)
+			usersCount++
+		}
+	}
+
+	var include, isRelation bool
+
+	for _, user := range usersFiltered {
+		isRelation = false
+		include = false
+
+		relationRequest := mr.Relation{
+			UserId:         id,
+			UserRelationId: user.Id,
+			Active:         true,
+		}
+
+		isFound, relationDb, _ := db.IsRelation(relationRequest)
+
+		if isFound {
+			isRelation = relationDb.Active
+		}
+
+		if relationRequest.UserRelationId == id {
+			include = false
+		} else if (searchType == "new" && !isRelation) || (searchType == "follow" && isRelation) {
+			include = true
+		}
+
+		if include {
+			userRequest := *user
+
+			userRequest.Email = ""
+			userRequest.Password = ""
+			userRequest.Avatar = ""
+			userRequest.Banner = ""
+			userRequest.Biography = ""
+			userRequest.Location = ""
+			userRequest.WebSite = ""
+
+			results = append(results, &userRequest)
+		}
+	}
+
+	// This total only reflects the total returned registries
+	total = int64(len(results))
+
+	return results, total, nil
+}
+
+func (db *DbMock) GetFollowing(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
+	return db.getUsers(id, page, limit, search, true)
+}
+
+func (db *DbMock) GetFollowers(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
+	var results []*mr.User
+	var users []*mr.User
+
+	if db.IsError {
+		return results, 0, fmt.Errorf("Error!")
+	}
+
+	for _, r := range db.Relations {
+		u := db.Users[r.UserId]
+
+		if r.UserRelationId == id && r.Active && u != nil &&
+			strings.Contains(strings.ToLower(u.Name), strings.ToLower(search)) {
+			users = append(users, u)
+		}
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].BirthDate.After(users[j].BirthDate)
+	})
+
+	total := int64(len(users))
+	offset := (page - 1) * limit
+
+	for i := offset; i < total && i < offset+limit; i++ {
+		results = append(results, users[i])
+	}
+
+	return results, total, nil
+}
+
+func (db *DbMock) GetNotFollowing(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
+	return db.getUsers(id, page, limit, search, false)
+}
+
+func (db *DbMock) GetFollowingTweets(id string, page int64, limit int64, isOnlyTweets bool) (any, int64, error) {
+	var results any
+	var userRelationIds []string
+	var tweets []*mr.Tweet
+	var total int64
+
+	if db.IsError {
+		return results, total, fmt.Errorf("Error!")
+	}
+
+	for _, r := range db.Relations {
+		if r.UserId == id {
+			userRelationIds = append(userRelationIds, r.UserRelationId)
+		}
+	}
+
+	for _, id := range userRelationIds {
+		for _, tweet := range db.Tweets {
+			if tweet.UserId == id {
+				tweets = append(tweets, tweet)
+			}
+		}
+	}
+
+	total = int64(len(tweets))
+
+	sort.Slice(tweets, func(i, j int) bool {
+		return tweets[i].Date.After(tweets[j].Date)
+	})
+
+	offset := (page - 1) * limit
+	tweetsCount := int64(0)
+
+	if isOnlyTweets {
+		var reqResults []*mr.Tweet
+
+		for _, t := range tweets[offset:] {
+			if tweetsCount < limit {
+				reqResults = append(reqResults, t)
+
+				tweetsCount++
+			}
+		}
+
+		results = reqResults
+	} else {
+		var reqResults []*mr.UserTweet
+
+		for _, t := range tweets[offset:] {
+			if tweetsCount < limit {
+				ut := &mr.UserTweet{}
+
+				ut.UserId = id
+				ut.UserRelationId = t.UserId
+				ut.Tweet.Id = t.Id
+				ut.Tweet.Message = t.Message
+				ut.Tweet.Date = t.Date
+				ut.Tweet.InReplyTo = t.InReplyTo
+				ut.Tweet.ConversationId = t.ConversationId
+				ut.Tweet.ReplyCount = t.ReplyCount
+				ut.Tweet.RetweetOf = t.RetweetOf
+				ut.Tweet.QuotedTweetId = t.QuotedTweetId
+				ut.Tweet.RetweetCount = t.RetweetCount
+				ut.Tweet.QuoteCount = t.QuoteCount
+				ut.Tweet.LikeCount = t.LikeCount
+				ut.Tweet.Active = t.Active
+
+				reqResults = append(reqResults, ut)
+
+				tweetsCount++
+			}
+		}
+
+		results = reqResults
+	}
+
+	return results, total, nil
+}
+
+// endregion
+
+// region "Tokens"
+
+func (db *DbMock) RevokeToken(token mr.RevokedToken) (bool, error) {
+	if db.IsError {
+		return false, fmt.Errorf("Error!")
+	}
+
+	if _, isFound := db.RevokedTokens[token.TokenId]; isFound {
+		return false, nil
+	}
+
+	db.RevokedTokens[token.TokenId] = &token
+
+	return true, nil
+}
+
+func (db *DbMock) IsTokenRevoked(tokenId string) (bool, error) {
+	if db.IsError {
+		return false, fmt.Errorf("Error!")
+	}
+
+	_, isRevoked := db.RevokedTokens[tokenId]
+
+	return isRevoked, nil
+}
+
+func (db *DbMock) InsertActionToken(token mr.ActionToken) error {
+	if db.IsError {
+		return fmt.Errorf("Error!")
+	}
+
+	db.ActionTokens[token.Hash] = &token
+
+	return nil
+}
+
+func (db *DbMock) ConsumeActionToken(hash string, action string) (mr.ActionToken, bool, error) {
+	var tokenRequest mr.ActionToken
+
+	if db.IsError {
+		return tokenRequest, false, fmt.Errorf("Error!")
+	}
+
+	token := db.ActionTokens[hash]
+
+	if token == nil || token.Action != action {
+		return tokenRequest, false, nil
+	}
+
+	delete(db.ActionTokens, hash)
+
+	return *token, token.ExpiresAt.IsZero() || token.ExpiresAt.After(time.Now()), nil
+}
+
+func (db *DbMock) DeleteActionTokens(userId string, action string) error {
+	if db.IsError {
+		return fmt.Errorf("Error!")
+	}
+
+	for hash, token := range db.ActionTokens {
+		if token.UserId == userId && token.Action == action {
+			delete(db.ActionTokens, hash)
+		}
+	}
+
+	return nil
+}
+
+// endregion
+
+// region "Access tokens"
+
+func (db *DbMock) InsertAccessToken(token mr.AccessToken) (string, error) {
+	if db.IsError {
+		return "", fmt.Errorf("Error!")
+	}
+
+	db.IdAccessTokenCounter++
+
+	token.Id = strconv.Itoa(db.IdAccessTokenCounter)
+	db.AccessTokens = append(db.AccessTokens, &token)
+
+	return token.Id, nil
+}
+
+func (db *DbMock) GetAccessTokens(userId string) ([]*mr.AccessToken, error) {
+	var results []*mr.AccessToken
+
+	if db.IsError {
+		return results, fmt.Errorf("Error!")
+	}
+
+	for _, token := range db.AccessTokens {
+		if token.UserId == userId {
+			results = append(results, token)
+		}
+	}
+
+	return results, nil
+}
+
+func (db *DbMock) GetAccessToken(hash string) (mr.AccessToken, bool, error) {
+	if db.IsError {
+		return mr.AccessToken{}, false, fmt.Errorf("Error!")
+	}
+
+	for _, token := range db.AccessTokens {
+		if token.Hash == hash {
+			return *token, true, nil
+		}
+	}
+
+	return mr.AccessToken{}, false, nil
+}
+
+func (db *DbMock) DeleteAccessToken(id string, userId string) (bool, error) {
+	if db.IsError {
+		return false, fmt.Errorf("Error!")
+	}
+
+	for i, token := range db.AccessTokens {
+		if token.Id == id && token.UserId == userId {
+			db.AccessTokens = append(db.AccessTokens[:i], db.AccessTokens[i+1:]...)
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (db *DbMock) DeleteAccessTokens(userId string) error {
+	var accessTokens []*mr.AccessToken
+
+	if db.IsError {
+		return fmt.Errorf("Error!")
+	}
+
+	for _, token := range db.AccessTokens {
+		if token.UserId != userId {
+			accessTokens = append(accessTokens, token)
+		}
+	}
+
+	db.AccessTokens = accessTokens
 
 	return nil
 }
 
-func (db *DbMock) GetTweets(id string, page int64, limit int64) ([]*mr.Tweet, int64, error) {
-	tweets := []*mr.Tweet{}
+// endregion
+
+// region "Sessions"
 
+func (db *DbMock) InsertSession(session mr.Session) (string, error) {
 	if db.IsError {
-		return tweets, 0, fmt.Errorf("Error!")
+		return "", fmt.Errorf("Error!")
 	}
 
-	offset := (page - 1) * limit
-	count := int64(0)
-	total := int64(0)
+	db.IdSessionCounter++
 
-	for _, tweet := range db.Tweets {
-		if tweet.UserId == id && tweet.Active {
-			total++
-		}
-	}
+	session.Id = strconv.Itoa(db.IdSessionCounter)
+	db.Sessions = append(db.Sessions, &session)
 
-	sort.Slice(db.Tweets, func(i, j int) bool {
-		return db.Tweets[i].Date.After(db.Tweets[j].Date)
-	})
+	return session.Id, nil
+}
 
-	for _, tweet := range db.Tweets[offset:] {
-		if tweet.UserId == id && tweet.Active && count < limit {
-			tweets = append(tweets, tweet)
-			count++
+func (db *DbMock) GetSession(id string) (mr.Session, bool, error) {
+	if db.IsError {
+		return mr.Session{}, false, fmt.Errorf("Error!")
+	}
+
+	for _, session := range db.Sessions {
+		if session.Id == id {
+			return *session, true, nil
 		}
 	}
 
-	return tweets, total, nil
+	return mr.Session{}, false, nil
 }
 
-func (db *DbMock) InsertTweet(tweet mr.Tweet) (string, error) {
-	db.IdTweetCounter++
+func (db *DbMock) GetSessions(userId string) ([]*mr.Session, error) {
+	var results []*mr.Session
 
-	tweet.Id = strconv.Itoa(db.IdTweetCounter)
-	db.Tweets = append(db.Tweets, &tweet)
+	if db.IsError {
+		return results, fmt.Errorf("Error!")
+	}
 
-	return tweet.Id, nil
+	for _, session := range db.Sessions {
+		if session.UserId == userId && session.ExpiresAt.After(time.Now()) {
+			results = append(results, session)
+		}
+	}
+
+	return results, nil
 }
 
-// endregion
+func (db *DbMock) TouchSession(id string, lastSeenAt time.Time, expiresAt time.Time) error {
+	if db.IsError {
+		return fmt.Errorf("Error!")
+	}
 
-// region "Relations"
+	for _, session := range db.Sessions {
+		if session.Id == id {
+			session.LastSeenAt = lastSeenAt
+			session.ExpiresAt = expiresAt
+		}
+	}
 
-func (db *DbMock) IsRelation(relation mr.Relation) (bool, mr.Relation, error) {
-	var relationModel mr.Relation
-	var isFound bool
+	return nil
+}
 
+func (db *DbMock) DeleteSession(id string, userId string) (bool, error) {
 	if db.IsError {
-		return false, relationModel, fmt.Errorf("Error!")
+		return false, fmt.Errorf("Error!")
 	}
 
-	for _, r := range db.Relations {
-		if relation.UserId == r.UserId && relation.UserRelationId == r.UserRelationId {
-			isFound = true
-			relationModel = *r
+	for i, session := range db.Sessions {
+		if session.Id == id && session.UserId == userId {
+			db.Sessions = append(db.Sessions[:i], db.Sessions[i+1:]...)
+
+			return true, nil
 		}
 	}
 
-	return isFound, relationModel, nil
+	return false, nil
 }
 
-func (db *DbMock) InsertRelation(relation mr.Relation) error {
-	_, isFound, err := db.GetProfile(relation.UserRelationId)
+func (db *DbMock) DeleteSessions(userId string, exceptId string) error {
+	var sessions []*mr.Session
 
-	if err != nil {
-		return err
+	if db.IsError {
+		return fmt.Errorf("Error!")
 	}
 
-	if !isFound {
-		return fmt.Errorf("no registry found in the DB")
+	for _, session := range db.Sessions {
+		if session.UserId != userId || session.Id == exceptId {
+			sessions = append(sessions, session)
+		}
 	}
 
-	isFound, relationDb, err := db.IsRelation(relation)
+	db.Sessions = sessions
 
-	if err != nil {
-		return err
-	}
+	return nil
+}
 
-	if !isFound {
-		db.Relations = append(db.Relations, &mr.Relation{
-			UserId:         relationDb.UserId,
-			UserRelationId: relationDb.UserRelationId})
+// endregion
 
-		return nil
-	}
+// region "Exports"
 
-	if relationDb.Active {
-		return fmt.Errorf("the relation with the user id = %s already exists", relation.UserRelationId)
+func (db *DbMock) InsertExport(export mr.Export) (string, error) {
+	if db.IsError {
+		return "", fmt.Errorf("Error!")
 	}
 
-	db.updateRelation(relationDb, true)
+	db.IdExportCounter++
 
-	return nil
+	export.Id = strconv.Itoa(db.IdExportCounter)
+	db.Exports = append(db.Exports, &export)
+
+	return export.Id, nil
 }
 
-func (db *DbMock) DeleteRelation(relation mr.Relation) error {
+func (db *DbMock) GetExport(id string) (mr.Export, bool, error) {
 	if db.IsError {
-		return fmt.Errorf("Error!")
+		return mr.Export{}, false, fmt.Errorf("Error!")
 	}
 
-	db.updateRelation(relation, false)
+	for _, export := range db.Exports {
+		if export.Id == id {
+			return *export, true, nil
+		}
+	}
 
-	return nil
+	return mr.Export{}, false, nil
 }
 
-func (db *DbMock) GetUsers(id string, page int64, limit int64, search string, searchType string) ([]*mr.User, int64, error) {
-	var results []*mr.User
-	var users []*mr.User
-	var total int64
+func (db *DbMock) GetExports(userId string) ([]*mr.Export, error) {
+	var results []*mr.Export
 
 	if db.IsError {
-		return results, total, fmt.Errorf("Error!")
+		return results, fmt.Errorf("Error!")
 	}
 
-	for _, u := range db.Users {
-		if strings.Contains(strings.ToLower(u.Name), strings.ToLower(search)) {
-			users = append(users, u)
+	for _, export := range db.Exports {
+		if export.UserId == userId && export.ExpiresAt.After(time.Now()) {
+			results = append(results, export)
 		}
 	}
 
-	sort.Slice(users, func(i, j int) bool {
-		return users[i].BirthDate.After(users[j].BirthDate)
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].CreatedAt.After(results[j].CreatedAt)
 	})
 
-	offset := (page - 1) * limit
-	usersCount := int64(0)
-	usersFiltered := []*mr.User{}
+	return results, nil
+}
 
-	for _, u := range users[offset:] {
-		if usersCount < limit {
-			usersFiltered = append(usersFiltered, u)
-			usersCount++
-		}
+func (db *DbMock) GetExpiredExports(before time.Time) ([]*mr.Export, error) {
+	var results []*mr.Export
+
+	if db.IsError {
+		return results, fmt.Errorf("Error!")
 	}
 
-	var include, isRelation bool
+	for _, export := range db.Exports {
+		if !export.ExpiresAt.After(before) {
+			results = append(results, export)
+		}
+	}
 
-	for _, user := range usersFiltered {
-		isRelation = false
-		include = false
+	return results, nil
+}
 
-		relationRequest := mr.Relation{
-			UserId:         id,
-			UserRelationId: user.Id,
-			Active:         true,
-		}
+func (db *DbMock) ModifyExport(export mr.Export) (bool, error) {
+	if db.IsError {
+		return false, fmt.Errorf("Error!")
+	}
 
-		isFound, relationDb, _ := db.IsRelation(relationRequest)
+	for _, e := range db.Exports {
+		if e.Id == export.Id {
+			e.Status = export.Status
+			e.Error = export.Error
+			e.TokenHash = export.TokenHash
+			e.TokenExpiresAt = export.TokenExpiresAt
 
-		if isFound {
-			isRelation = relationDb.Active
+			return true, nil
 		}
+	}
 
-		if relationRequest.UserRelationId == id {
-			include = false
-		} else if (searchType == "new" && !isRelation) || (searchType == "follow" && isRelation) {
-			include = true
-		}
+	return false, nil
+}
 
-		if include {
-			userRequest := *user
+func (db *DbMock) DeleteExport(id string) error {
+	if db.IsError {
+		return fmt.Errorf("Error!")
+	}
 
-			userRequest.Email = ""
-			userRequest.Password = ""
-			userRequest.Avatar = ""
-			userRequest.Banner = ""
-			userRequest.Biography = ""
-			userRequest.Location = ""
-			userRequest.WebSite = ""
+	for i, export := range db.Exports {
+		if export.Id == id {
+			db.Exports = append(db.Exports[:i], db.Exports[i+1:]...)
 
-			results = append(results, &userRequest)
+			break
 		}
 	}
 
-	// This total only reflects the total returned registries
-	total = int64(len(results))
-
-	return results, total, nil
+	return nil
 }
 
-func (db *DbMock) GetFollowing(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
-	return db.getUsers(id, page, limit, search, true)
-}
+// endregion
 
-func (db *DbMock) GetNotFollowing(id string, page int64, limit int64, search string) ([]*mr.User, int64, error) {
-	return db.getUsers(id, page, limit, search, false)
-}
+// region "Login attempts"
 
-func (db *DbMock) GetFollowingTweets(id string, page int64, limit int64, isOnlyTweets bool) (any, int64, error) {
-	var results any
-	var userRelationIds []string
-	var tweets []*mr.Tweet
-	var total int64
+func (db *DbMock) GetLoginAttempt(key string) (mr.LoginAttempt, bool, error) {
+	var attemptRequest mr.LoginAttempt
 
 	if db.IsError {
-		return results, total, fmt.Errorf("Error!")
+		return attemptRequest, false, fmt.Errorf("Error!")
 	}
 
-	for _, r := range db.Relations {
-		if r.UserId == id {
-			userRelationIds = append(userRelationIds, r.UserRelationId)
-		}
-	}
+	attempt := db.LoginAttempts[key]
 
-	for _, id := range userRelationIds {
-		for _, tweet := range db.Tweets {
-			if tweet.UserId == id {
-				tweets = append(tweets, tweet)
-			}
-		}
+	if attempt == nil || !attempt.ExpiresAt.After(time.Now()) {
+		return attemptRequest, false, nil
 	}
 
-	total = int64(len(tweets))
-
-	sort.Slice(tweets, func(i, j int) bool {
-		return tweets[i].Date.After(tweets[j].Date)
-	})
-
-	offset := (page - 1) * limit
-	tweetsCount := int64(0)
-
-	if isOnlyTweets {
-		var reqResults []*mr.Tweet
-
-		for _, t := range tweets[offset:] {
-			if tweetsCount < limit {
-				reqResults = append(reqResults, t)
-
-				tweetsCount++
-			}
-		}
+	return *attempt, true, nil
+}
 
-		results = reqResults
-	} else {
-		var reqResults []*mr.UserTweet
+func (db *DbMock) IncrementLoginAttempt(key string, expiresAt time.Time) (mr.LoginAttempt, error) {
+	if db.IsError {
+		return mr.LoginAttempt{}, fmt.Errorf("Error!")
+	}
 
-		for _, t := range tweets[offset:] {
-			if tweetsCount < limit {
-				ut := &mr.UserTweet{}
+	attempt := db.LoginAttempts[key]
 
-				ut.UserId = id
-				ut.UserRelationId = t.UserId
-				ut.Tweet.Id = t.Id
-				ut.Tweet.Message = t.Message
-				ut.Tweet.Date = t.Date
+	if attempt == nil || !attempt.ExpiresAt.After(time.Now()) {
+		attempt = &mr.LoginAttempt{Key: key}
+		db.LoginAttempts[key] = attempt
+	}
 
-				reqResults = append(reqResults, ut)
+	attempt.Failures++
+	attempt.LastFailureAt = time.Now()
+	attempt.ExpiresAt = expiresAt
 
-				tweetsCount++
-			}
-		}
+	return *attempt, nil
+}
 
-		results = reqResults
+func (db *DbMock) DeleteLoginAttempt(key string) error {
+	if db.IsError {
+		return fmt.Errorf("Error!")
 	}
 
-	return results, total, nil
+	delete(db.LoginAttempts, key)
+
+	return nil
 }
 
 // endregion
@@ -470,6 +1826,64 @@ func (db *DbMock) getUsers(id string, page int64, limit int64, search string, is
 	return results, total, nil
 }
 
+func (db *DbMock) updateTweetCounters(tweet mr.Tweet, delta int64) {
+	for _, t := range db.Tweets {
+		if len(tweet.InReplyTo) > 0 && t.Id == tweet.InReplyTo {
+			t.ReplyCount += delta
+		}
+
+		if len(tweet.RetweetOf) > 0 && t.Id == tweet.RetweetOf {
+			t.RetweetCount += delta
+		}
+
+		if len(tweet.QuotedTweetId) > 0 && t.Id == tweet.QuotedTweetId {
+			t.QuoteCount += delta
+		}
+	}
+}
+
+func (db *DbMock) getAvailableHandle(base string) string {
+	handle := base
+
+	for i := 2; ; i++ {
+		isFound := false
+
+		for _, user := range db.Users {
+			isFound = isFound || user.Handle == handle
+		}
+
+		if !isFound {
+			return handle
+		}
+
+		handle = fmt.Sprintf("%s%d", base, i)
+	}
+}
+
+func (db *DbMock) isMentioned(tweet *mr.Tweet, userId string) bool {
+	user, isFound := db.Users[userId]
+
+	if !isFound || tweet.UserId == userId {
+		return false
+	}
+
+	for _, mention := range helpers.GetMentions(tweet.Message) {
+		if user.Handle == mention {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (db *DbMock) updateLikeCount(tweetId string, delta int64) {
+	for _, t := range db.Tweets {
+		if t.Id == tweetId {
+			t.LikeCount += delta
+		}
+	}
+}
+
 // endregion
 
 func Init() {
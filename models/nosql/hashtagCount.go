@@ -0,0 +1,16 @@
+package nosql
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/* HashtagCount model for the mongo DB, the number of tweets using a hashtag in a time bucket */
+type HashtagCount struct {
+	Id        primitive.ObjectID `bson:"_id,omitempty"`
+	Tag       string             `bson:"tag"`
+	Bucket    time.Time          `bson:"bucket"`
+	Count     int64              `bson:"count"`
+	ExpiresAt time.Time          `bson:"expiresAt"`
+}
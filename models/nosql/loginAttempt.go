@@ -0,0 +1,16 @@
+package nosql
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/* LoginAttempt model for the mongo DB */
+type LoginAttempt struct {
+	Id            primitive.ObjectID `bson:"_id,omitempty"`
+	Key           string             `bson:"key"`
+	Failures      int                `bson:"failures"`
+	LastFailureAt time.Time          `bson:"lastFailureAt"`
+	ExpiresAt     time.Time          `bson:"expiresAt"`
+}
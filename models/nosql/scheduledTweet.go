@@ -0,0 +1,20 @@
+package nosql
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/* ScheduledTweet model for the mongo DB, a tweet waiting to be published */
+type ScheduledTweet struct {
+	Id             primitive.ObjectID `bson:"_id,omitempty"`
+	UserId         primitive.ObjectID `bson:"userId"`
+	Message        string             `bson:"message"`
+	InReplyTo      primitive.ObjectID `bson:"inReplyTo,omitempty"`
+	ConversationId primitive.ObjectID `bson:"conversationId,omitempty"`
+	QuotedTweetId  primitive.ObjectID `bson:"quotedTweetId,omitempty"`
+	Media          []string           `bson:"media,omitempty"`
+	PublishAt      time.Time          `bson:"publishAt"`
+	Date           time.Time          `bson:"date"`
+}
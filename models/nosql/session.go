@@ -0,0 +1,18 @@
+package nosql
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/* Session model for the mongo DB */
+type Session struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty"`
+	UserId     primitive.ObjectID `bson:"userId"`
+	UserAgent  string             `bson:"userAgent"`
+	Ip         string             `bson:"ip"`
+	CreatedAt  time.Time          `bson:"createdAt"`
+	LastSeenAt time.Time          `bson:"lastSeenAt"`
+	ExpiresAt  time.Time          `bson:"expiresAt"`
+}
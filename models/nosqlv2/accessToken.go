@@ -0,0 +1,18 @@
+package nosqlv2
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/* AccessToken model for the mongo DB */
+type AccessToken struct {
+	Id        primitive.ObjectID `bson:"_id,omitempty"`
+	UserId    primitive.ObjectID `bson:"userId"`
+	Name      string             `bson:"name"`
+	Hash      string             `bson:"hash"`
+	Scopes    []string           `bson:"scopes"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	ExpiresAt time.Time          `bson:"expiresAt,omitempty"`
+}
@@ -0,0 +1,16 @@
+package nosqlv2
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/* ActionToken model for the mongo DB */
+type ActionToken struct {
+	Id        primitive.ObjectID `bson:"_id,omitempty"`
+	Hash      string             `bson:"hash"`
+	UserId    primitive.ObjectID `bson:"userId"`
+	Action    string             `bson:"action"`
+	ExpiresAt time.Time          `bson:"expiresAt,omitempty"`
+}
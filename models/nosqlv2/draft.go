@@ -0,0 +1,18 @@
+package nosqlv2
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/* Draft model for the mongo DB, a tweet saved without publishing */
+type Draft struct {
+	Id            primitive.ObjectID `bson:"_id,omitempty"`
+	UserId        primitive.ObjectID `bson:"userId"`
+	Message       string             `bson:"message"`
+	InReplyTo     primitive.ObjectID `bson:"inReplyTo,omitempty"`
+	QuotedTweetId primitive.ObjectID `bson:"quotedTweetId,omitempty"`
+	Media         []string           `bson:"media,omitempty"`
+	Date          time.Time          `bson:"date"`
+}
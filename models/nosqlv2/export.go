@@ -0,0 +1,19 @@
+package nosqlv2
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/* Export model for the mongo DB */
+type Export struct {
+	Id             primitive.ObjectID `bson:"_id,omitempty"`
+	UserId         primitive.ObjectID `bson:"userId"`
+	Status         string             `bson:"status"`
+	Error          string             `bson:"error,omitempty"`
+	TokenHash      string             `bson:"tokenHash,omitempty"`
+	TokenExpiresAt time.Time          `bson:"tokenExpiresAt,omitempty"`
+	CreatedAt      time.Time          `bson:"createdAt"`
+	ExpiresAt      time.Time          `bson:"expiresAt"`
+}
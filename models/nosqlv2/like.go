@@ -0,0 +1,15 @@
+package nosqlv2
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/* Like model for the mongo DB */
+type Like struct {
+	Id      primitive.ObjectID `bson:"_id,omitempty"`
+	UserId  primitive.ObjectID `bson:"userId"`
+	TweetId primitive.ObjectID `bson:"tweetId"`
+	Date    time.Time          `bson:"date"`
+}
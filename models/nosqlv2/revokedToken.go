@@ -0,0 +1,15 @@
+package nosqlv2
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/* RevokedToken model for the mongo DB */
+type RevokedToken struct {
+	Id        primitive.ObjectID `bson:"_id,omitempty"`
+	TokenId   string             `bson:"tokenId"`
+	UserId    primitive.ObjectID `bson:"userId"`
+	ExpiresAt time.Time          `bson:"expiresAt"`
+}
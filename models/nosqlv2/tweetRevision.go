@@ -0,0 +1,15 @@
+package nosqlv2
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/* TweetRevision model for the mongo DB, a previous message of an edited tweet */
+type TweetRevision struct {
+	Id      primitive.ObjectID `bson:"_id,omitempty"`
+	TweetId primitive.ObjectID `bson:"tweetId"`
+	Message string             `bson:"message"`
+	Date    time.Time          `bson:"date"`
+}
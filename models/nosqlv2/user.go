@@ -8,17 +8,25 @@ import (
 
 /* User model for the mongo DB */
 type User struct {
-	Id        primitive.ObjectID   `bson:"_id,omitempty"`
-	Name      string               `bson:"name"`
-	LastName  string               `bson:"lastName"`
-	BirthDate time.Time            `bson:"birthDate"`
-	Email     string               `bson:"email"`
-	Password  string               `bson:"password"`
-	Avatar    string               `bson:"avatar"`
-	Banner    string               `bson:"banner"`
-	Biography string               `bson:"biography"`
-	Location  string               `bson:"location"`
-	WebSite   string               `bson:"webSite"`
-	Tweets    []Tweet              `bson:"tweets"`
-	Following []primitive.ObjectID `bson:"following"`
+	Id              primitive.ObjectID   `bson:"_id,omitempty"`
+	Name            string               `bson:"name"`
+	LastName        string               `bson:"lastName"`
+	BirthDate       time.Time            `bson:"birthDate"`
+	Email           string               `bson:"email"`
+	Handle          string               `bson:"handle,omitempty"`
+	Password        string               `bson:"password"`
+	Avatar          string               `bson:"avatar"`
+	Banner          string               `bson:"banner"`
+	Biography       string               `bson:"biography"`
+	Location        string               `bson:"location"`
+	WebSite         string               `bson:"webSite"`
+	Verified        bool                 `bson:"verified"`
+	TokensRevokedAt time.Time            `bson:"tokensRevokedAt"`
+	TotpSecret      string               `bson:"totpSecret"`
+	TotpEnabled     bool                 `bson:"totpEnabled"`
+	TotpLastCounter int64                `bson:"totpLastCounter"`
+	Role            string               `bson:"role"`
+	DeleteAt        time.Time            `bson:"deleteAt,omitempty"`
+	Tweets          []Tweet              `bson:"tweets"`
+	Following       []primitive.ObjectID `bson:"following"`
 }
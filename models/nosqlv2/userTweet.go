@@ -11,8 +11,19 @@ type UserTweet struct {
 	UserId          primitive.ObjectID `bson:"userId"`
 	UserFollowingId primitive.ObjectID `bson:"userFollowingId"`
 	Tweet           struct {
-		Id      primitive.ObjectID `bson:"_id"`
-		Message string             `bson:"message"`
-		Date    time.Time          `bson:"date"`
+		Id             primitive.ObjectID `bson:"_id"`
+		Message        string             `bson:"message"`
+		Date           time.Time          `bson:"date"`
+		Active         bool               `bson:"active"`
+		InReplyTo      primitive.ObjectID `bson:"inReplyTo,omitempty"`
+		ConversationId primitive.ObjectID `bson:"conversationId,omitempty"`
+		ReplyCount     int64              `bson:"replyCount"`
+		RetweetOf      primitive.ObjectID `bson:"retweetOf,omitempty"`
+		QuotedTweetId  primitive.ObjectID `bson:"quotedTweetId,omitempty"`
+		RetweetCount   int64              `bson:"retweetCount"`
+		QuoteCount     int64              `bson:"quoteCount"`
+		LikeCount      int64              `bson:"likeCount"`
+		EditedAt       *time.Time         `bson:"editedAt,omitempty"`
+		Media          []string           `bson:"media,omitempty"`
 	}
 }
@@ -0,0 +1,16 @@
+package relational
+
+import (
+	"time"
+)
+
+/* AccessToken model for the postgreSQL DB, the scopes are separated by spaces */
+type AccessToken struct {
+	Id        uint64    `gorm:"primarykey"`
+	UserId    uint64    `gorm:"not null;index"`
+	Name      string    `gorm:"not null"`
+	Hash      string    `gorm:"not null;uniqueIndex"`
+	Scopes    string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+}
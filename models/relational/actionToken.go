@@ -0,0 +1,14 @@
+package relational
+
+import (
+	"time"
+)
+
+/* ActionToken model for the postgreSQL DB */
+type ActionToken struct {
+	Id        uint64    `gorm:"primarykey"`
+	Hash      string    `gorm:"not null;uniqueIndex"`
+	UserId    uint64    `gorm:"not null;index"`
+	Action    string    `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+}
@@ -0,0 +1,13 @@
+package relational
+
+import (
+	"time"
+)
+
+/* Bookmark model for the postgreSQL DB */
+type Bookmark struct {
+	Id      uint64    `gorm:"primarykey"`
+	UserId  uint64    `gorm:"not null;uniqueIndex:idx_bookmarks_user_tweet;index:idx_bookmarks_user_date"`
+	TweetId uint64    `gorm:"not null;uniqueIndex:idx_bookmarks_user_tweet;index"`
+	Date    time.Time `gorm:"not null;index:idx_bookmarks_user_date"`
+}
@@ -0,0 +1,16 @@
+package relational
+
+import (
+	"time"
+)
+
+/* Draft model for the postgreSQL DB, a tweet saved without publishing */
+type Draft struct {
+	Id            uint64 `gorm:"primarykey"`
+	UserId        uint64 `gorm:"not null;index:idx_drafts_user_date"`
+	Message       string `gorm:"not null"`
+	InReplyTo     *uint64
+	QuotedTweetId *uint64
+	Media         []string  `gorm:"serializer:json"`
+	Date          time.Time `gorm:"not null;index:idx_drafts_user_date"`
+}
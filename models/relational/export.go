@@ -0,0 +1,17 @@
+package relational
+
+import (
+	"time"
+)
+
+/* Export model for the postgreSQL DB */
+type Export struct {
+	Id             uint64    `gorm:"primarykey"`
+	UserId         uint64    `gorm:"not null;index"`
+	Status         string    `gorm:"not null"`
+	Error          string    `gorm:"not null"`
+	TokenHash      string    `gorm:"not null"`
+	TokenExpiresAt time.Time `gorm:"not null"`
+	CreatedAt      time.Time `gorm:"not null"`
+	ExpiresAt      time.Time `gorm:"not null;index"`
+}
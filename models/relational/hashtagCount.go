@@ -0,0 +1,13 @@
+package relational
+
+import (
+	"time"
+)
+
+/* HashtagCount model for the postgreSQL DB, the number of tweets using a hashtag in a time bucket */
+type HashtagCount struct {
+	Tag       string    `gorm:"primarykey"`
+	Bucket    time.Time `gorm:"primarykey;index"`
+	Count     int64     `gorm:"not null;default:0"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
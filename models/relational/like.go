@@ -0,0 +1,13 @@
+package relational
+
+import (
+	"time"
+)
+
+/* Like model for the postgreSQL DB */
+type Like struct {
+	Id      uint64    `gorm:"primarykey"`
+	UserId  uint64    `gorm:"not null;uniqueIndex:idx_likes_user_tweet;index:idx_likes_user_date"`
+	TweetId uint64    `gorm:"not null;uniqueIndex:idx_likes_user_tweet;index"`
+	Date    time.Time `gorm:"not null;index:idx_likes_user_date"`
+}
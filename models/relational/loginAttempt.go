@@ -0,0 +1,14 @@
+package relational
+
+import (
+	"time"
+)
+
+/* LoginAttempt model for the postgreSQL DB */
+type LoginAttempt struct {
+	Id            uint64    `gorm:"primarykey"`
+	Key           string    `gorm:"not null;uniqueIndex"`
+	Failures      int       `gorm:"not null"`
+	LastFailureAt time.Time `gorm:"not null"`
+	ExpiresAt     time.Time `gorm:"not null;index"`
+}
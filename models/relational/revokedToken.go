@@ -0,0 +1,13 @@
+package relational
+
+import (
+	"time"
+)
+
+/* RevokedToken model for the postgreSQL DB */
+type RevokedToken struct {
+	Id        uint64    `gorm:"primarykey"`
+	TokenId   string    `gorm:"not null;uniqueIndex"`
+	UserId    uint64    `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
@@ -0,0 +1,18 @@
+package relational
+
+import (
+	"time"
+)
+
+/* ScheduledTweet model for the postgreSQL DB, a tweet waiting to be published */
+type ScheduledTweet struct {
+	Id             uint64 `gorm:"primarykey"`
+	UserId         uint64 `gorm:"not null;index:idx_scheduled_tweets_user_publish"`
+	Message        string `gorm:"not null"`
+	InReplyTo      *uint64
+	ConversationId *uint64
+	QuotedTweetId  *uint64
+	Media          []string  `gorm:"serializer:json"`
+	PublishAt      time.Time `gorm:"not null;index;index:idx_scheduled_tweets_user_publish"`
+	Date           time.Time `gorm:"not null"`
+}
@@ -0,0 +1,16 @@
+package relational
+
+import (
+	"time"
+)
+
+/* Session model for the postgreSQL DB */
+type Session struct {
+	Id         uint64    `gorm:"primarykey"`
+	UserId     uint64    `gorm:"not null;index"`
+	UserAgent  string    `gorm:"not null"`
+	Ip         string    `gorm:"not null"`
+	CreatedAt  time.Time `gorm:"not null"`
+	LastSeenAt time.Time `gorm:"not null"`
+	ExpiresAt  time.Time `gorm:"not null;index"`
+}
@@ -6,9 +6,19 @@ import (
 
 /* User model for the postgreSQL DB */
 type Tweet struct {
-	Id      uint64    `gorm:"primarykey"`
-	Message string    `gorm:"not null"`
-	Date    time.Time `gorm:"not null"`
-	Active  bool      `gorm:"not null;default:true"`
-	UserId  uint64    `gorm:"not null"`
+	Id             uint64    `gorm:"primarykey"`
+	Message        string    `gorm:"not null"`
+	Date           time.Time `gorm:"not null"`
+	Active         bool      `gorm:"not null;default:true"`
+	UserId         uint64    `gorm:"not null;uniqueIndex:idx_tweets_retweet,where:active"`
+	InReplyTo      *uint64   `gorm:"index"`
+	ConversationId *uint64   `gorm:"index"`
+	ReplyCount     int64     `gorm:"not null;default:0"`
+	RetweetOf      *uint64   `gorm:"index;uniqueIndex:idx_tweets_retweet,where:active"`
+	QuotedTweetId  *uint64   `gorm:"index"`
+	RetweetCount   int64     `gorm:"not null;default:0"`
+	QuoteCount     int64     `gorm:"not null;default:0"`
+	LikeCount      int64     `gorm:"not null;default:0"`
+	EditedAt       *time.Time
+	Media          []string `gorm:"serializer:json"`
 }
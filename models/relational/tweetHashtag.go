@@ -0,0 +1,12 @@
+package relational
+
+import (
+	"time"
+)
+
+/* TweetHashtag model for the postgreSQL DB, a hashtag used in a tweet */
+type TweetHashtag struct {
+	TweetId uint64    `gorm:"primarykey"`
+	Tag     string    `gorm:"primarykey;index:idx_tweet_hashtags_tag_date"`
+	Date    time.Time `gorm:"not null;index:idx_tweet_hashtags_tag_date"`
+}
@@ -0,0 +1,12 @@
+package relational
+
+import (
+	"time"
+)
+
+/* TweetMention model for the postgreSQL DB, an user mentioned in a tweet */
+type TweetMention struct {
+	TweetId uint64    `gorm:"primarykey"`
+	UserId  uint64    `gorm:"primarykey;index:idx_tweet_mentions_user_date"`
+	Date    time.Time `gorm:"not null;index:idx_tweet_mentions_user_date"`
+}
@@ -0,0 +1,13 @@
+package relational
+
+import (
+	"time"
+)
+
+/* TweetRevision model for the postgreSQL DB, a previous message of an edited tweet */
+type TweetRevision struct {
+	Id      uint64    `gorm:"primarykey"`
+	TweetId uint64    `gorm:"not null;index:idx_tweet_revisions_tweet_date"`
+	Message string    `gorm:"not null"`
+	Date    time.Time `gorm:"not null;index:idx_tweet_revisions_tweet_date"`
+}
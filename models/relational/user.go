@@ -6,17 +6,25 @@ import (
 
 /* User model for the postgreSQL DB */
 type User struct {
-	Id        uint64    `gorm:"primarykey"`
-	Name      string    `gorm:"not null"`
-	LastName  string    `gorm:"not null"`
-	BirthDate time.Time `gorm:"not null"`
-	Email     string    `gorm:"not null;uniqueIndex"`
-	Password  string    `gorm:"not null"`
-	Avatar    string
-	Banner    string
-	Biography string
-	Location  string
-	WebSite   string
-	Tweets    []Tweet
-	Following []User `gorm:"many2many:relations;"`
+	Id              uint64    `gorm:"primarykey"`
+	Name            string    `gorm:"not null"`
+	LastName        string    `gorm:"not null"`
+	BirthDate       time.Time `gorm:"not null"`
+	Email           string    `gorm:"not null;uniqueIndex"`
+	Handle          *string   `gorm:"uniqueIndex"`
+	Password        string    `gorm:"not null"`
+	Avatar          string
+	Banner          string
+	Biography       string
+	Location        string
+	WebSite         string
+	Verified        bool      `gorm:"not null;default:false"`
+	TokensRevokedAt time.Time `gorm:"not null;default:'epoch'"`
+	TotpSecret      string
+	TotpEnabled     bool       `gorm:"not null;default:false"`
+	TotpLastCounter int64      `gorm:"not null;default:0"`
+	Role            string     `gorm:"not null;default:'user'"`
+	DeleteAt        *time.Time `gorm:"index"`
+	Tweets          []Tweet
+	Following       []User `gorm:"many2many:relations;"`
 }
@@ -0,0 +1,55 @@
+package request
+
+import "time"
+
+/* AccessTokenPrefix is the prefix of the personal access tokens, used to tell them apart from the JWT */
+const AccessTokenPrefix = "twp_"
+
+const (
+	/* ScopeTweetsRead allows to read the tweets */
+	ScopeTweetsRead = "tweets:read"
+
+	/* ScopeTweetsWrite allows to post and delete tweets */
+	ScopeTweetsWrite = "tweets:write"
+
+	/* ScopeRelationsRead allows to read the relations and the users */
+	ScopeRelationsRead = "relations:read"
+
+	/* ScopeRelationsWrite allows to follow and unfollow users */
+	ScopeRelationsWrite = "relations:write"
+
+	/* ScopeProfileRead allows to read the profile */
+	ScopeProfileRead = "profile:read"
+
+	/* ScopeProfileWrite allows to modify the profile */
+	ScopeProfileWrite = "profile:write"
+)
+
+/* Scopes are the scopes that can be granted to a personal access token */
+var Scopes = []string{
+	ScopeTweetsRead,
+	ScopeTweetsWrite,
+	ScopeRelationsRead,
+	ScopeRelationsWrite,
+	ScopeProfileRead,
+	ScopeProfileWrite,
+}
+
+/* AccessToken is the request model for a personal access token. A zero ExpiresAt means that it does not expire */
+type AccessToken struct {
+	Id        string    `json:"id"`
+	UserId    string    `json:"userId,omitempty"`
+	Name      string    `json:"name"`
+	Hash      string    `json:"-"`
+	Token     string    `json:"token,omitempty"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+}
+
+/* AccessTokenCreate is the request model for the create personal access token endpoint */
+type AccessTokenCreate struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expiresInDays"`
+}
@@ -0,0 +1,6 @@
+package request
+
+/* AccountDelete is the request model for the delete account endpoint */
+type AccountDelete struct {
+	Password string `json:"password"`
+}
@@ -0,0 +1,22 @@
+package request
+
+import "time"
+
+const (
+	/* ActionResetPassword is the action of the tokens used to reset a password */
+	ActionResetPassword = "reset"
+
+	/* ActionVerifyEmail is the action of the tokens used to verify an email address */
+	ActionVerifyEmail = "verify"
+
+	/* ActionRecoveryCode is the action of the two-factor authentication recovery codes */
+	ActionRecoveryCode = "recovery"
+)
+
+/* ActionToken is the request model for a single-use token that allows an user to do an action. A zero ExpiresAt means that it does not expire */
+type ActionToken struct {
+	Hash      string    `json:"-"`
+	UserId    string    `json:"userId,omitempty"`
+	Action    string    `json:"action,omitempty"`
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+}
@@ -0,0 +1,10 @@
+package request
+
+import "time"
+
+/* Bookmark is the request model for a tweet privately saved by an user */
+type Bookmark struct {
+	UserId  string    `json:"userId,omitempty"`
+	TweetId string    `json:"tweetId,omitempty"`
+	Date    time.Time `json:"date"`
+}
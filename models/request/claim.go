@@ -6,7 +6,12 @@ import (
 
 /* Claim is the model to process the JWT */
 type Claim struct {
-	Email string `json:"email"`
-	Id    string `json:"_id,omitempty"`
+	Email      string `json:"email"`
+	Id         string `json:"_id,omitempty"`
+	TokenId    string `json:"jti,omitempty"`
+	SessionId  string `json:"sid,omitempty"`
+	Role       string `json:"role,omitempty"`
+	Type       string `json:"type,omitempty"`
+	IssuedAtMs int64  `json:"iat_ms,omitempty"`
 	jwt.StandardClaims
 }
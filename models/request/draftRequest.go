@@ -0,0 +1,14 @@
+package request
+
+import "time"
+
+/* Draft request model, a tweet saved without publishing */
+type Draft struct {
+	Id            string    `json:"id,omitempty"`
+	UserId        string    `json:"userId,omitempty"`
+	Message       string    `json:"message"`
+	InReplyTo     string    `json:"inReplyTo,omitempty"`
+	QuotedTweetId string    `json:"quotedTweetId,omitempty"`
+	Media         []string  `json:"media,omitempty"`
+	Date          time.Time `json:"date"`
+}
@@ -0,0 +1,15 @@
+package request
+
+import "time"
+
+/* Export is the request model for an archive with the personal data of an user */
+type Export struct {
+	Id             string    `json:"id"`
+	UserId         string    `json:"userId,omitempty"`
+	Status         string    `json:"status"`
+	Error          string    `json:"error,omitempty"`
+	TokenHash      string    `json:"-"`
+	TokenExpiresAt time.Time `json:"-"`
+	CreatedAt      time.Time `json:"createdAt"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+}
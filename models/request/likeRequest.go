@@ -0,0 +1,10 @@
+package request
+
+import "time"
+
+/* Like is the request model for an user's like of a tweet */
+type Like struct {
+	UserId  string    `json:"userId,omitempty"`
+	TweetId string    `json:"tweetId,omitempty"`
+	Date    time.Time `json:"date"`
+}
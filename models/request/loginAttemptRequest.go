@@ -0,0 +1,11 @@
+package request
+
+import "time"
+
+/* LoginAttempt is the request model for the failed attempts counted for an account or an IP address */
+type LoginAttempt struct {
+	Key           string    `json:"key"`
+	Failures      int       `json:"failures"`
+	LastFailureAt time.Time `json:"lastFailureAt"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+}
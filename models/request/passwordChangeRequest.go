@@ -0,0 +1,7 @@
+package request
+
+/* PasswordChange is the request model for the change password endpoint */
+type PasswordChange struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
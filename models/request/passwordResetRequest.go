@@ -0,0 +1,7 @@
+package request
+
+/* PasswordReset is the request model for the reset password endpoint */
+type PasswordReset struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
@@ -0,0 +1,11 @@
+package request
+
+/* Principal is the authenticated caller of a request. The Scopes are only set for the personal access tokens, the JWT have all of them */
+type Principal struct {
+	Id      string
+	Email   string
+	TokenId string
+	Role    string
+	Claims  *Claim
+	Scopes  []string
+}
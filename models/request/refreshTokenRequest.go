@@ -0,0 +1,6 @@
+package request
+
+/* RefreshToken is the request model for the refresh token and logout endpoints */
+type RefreshToken struct {
+	RefreshToken string `json:"refreshToken,omitempty"`
+}
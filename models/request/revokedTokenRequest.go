@@ -0,0 +1,10 @@
+package request
+
+import "time"
+
+/* RevokedToken is the request model for a token that cannot be used anymore */
+type RevokedToken struct {
+	TokenId   string    `json:"tokenId"`
+	UserId    string    `json:"userId,omitempty"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
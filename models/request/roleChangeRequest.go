@@ -0,0 +1,6 @@
+package request
+
+/* RoleChange is the request model for the change role endpoint */
+type RoleChange struct {
+	Role string `json:"role"`
+}
@@ -0,0 +1,16 @@
+package request
+
+import "time"
+
+/* ScheduledTweet request model, a tweet waiting to be published */
+type ScheduledTweet struct {
+	Id             string    `json:"id,omitempty"`
+	UserId         string    `json:"userId,omitempty"`
+	Message        string    `json:"message,omitempty"`
+	InReplyTo      string    `json:"inReplyTo,omitempty"`
+	ConversationId string    `json:"conversationId,omitempty"`
+	QuotedTweetId  string    `json:"quotedTweetId,omitempty"`
+	Media          []string  `json:"media,omitempty"`
+	PublishAt      time.Time `json:"publishAt"`
+	Date           time.Time `json:"date"`
+}
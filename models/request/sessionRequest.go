@@ -0,0 +1,15 @@
+package request
+
+import "time"
+
+/* Session is the request model for the session opened by a login, shared by the tokens issued for it */
+type Session struct {
+	Id         string    `json:"id"`
+	UserId     string    `json:"userId,omitempty"`
+	UserAgent  string    `json:"userAgent"`
+	Ip         string    `json:"ip"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Current    bool      `json:"current"`
+}
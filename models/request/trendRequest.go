@@ -0,0 +1,7 @@
+package request
+
+/* Trend request model, a hashtag with the number of tweets using it */
+type Trend struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
@@ -4,9 +4,25 @@ import "time"
 
 /* Tweet request model */
 type Tweet struct {
-	Id      string    `json:"id,omitempty"`
-	UserId  string    `json:"userId,omitempty"`
-	Message string    `json:"message,omitempty"`
-	Date    time.Time `json:"date,omitempty"`
-	Active  bool      `json:"-"`
+	Id             string     `json:"id,omitempty"`
+	UserId         string     `json:"userId,omitempty"`
+	Message        string     `json:"message,omitempty"`
+	Date           time.Time  `json:"date,omitempty"`
+	InReplyTo      string     `json:"inReplyTo,omitempty"`
+	ConversationId string     `json:"conversationId,omitempty"`
+	ReplyCount     int64      `json:"replyCount"`
+	RetweetOf      string     `json:"retweetOf,omitempty"`
+	QuotedTweetId  string     `json:"quotedTweetId,omitempty"`
+	RetweetCount   int64      `json:"retweetCount"`
+	QuoteCount     int64      `json:"quoteCount"`
+	LikeCount      int64      `json:"likeCount"`
+	LikedByMe      bool       `json:"likedByMe"`
+	Edited         bool       `json:"edited"`
+	EditedAt       *time.Time `json:"editedAt,omitempty"`
+	Media          []string   `json:"media,omitempty"`
+	PublishAt      *time.Time `json:"publishAt,omitempty"`
+	Original       *Tweet     `json:"original,omitempty"`
+	Author         *User      `json:"author,omitempty"`
+	Unavailable    bool       `json:"unavailable,omitempty"`
+	Active         bool       `json:"-"`
 }
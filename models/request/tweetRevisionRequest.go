@@ -0,0 +1,11 @@
+package request
+
+import "time"
+
+/* TweetRevision request model, a previous message of an edited tweet */
+type TweetRevision struct {
+	Id      string    `json:"id,omitempty"`
+	TweetId string    `json:"tweetId,omitempty"`
+	Message string    `json:"message"`
+	Date    time.Time `json:"date"`
+}
@@ -0,0 +1,13 @@
+package request
+
+/* TotpCode is the request model for the two-factor authentication endpoints that require a code */
+type TotpCode struct {
+	Code string `json:"code"`
+}
+
+/* TwoFactorLogin is the request model for the second step of the login */
+type TwoFactorLogin struct {
+	ChallengeToken string `json:"challengeToken"`
+	Code           string `json:"code,omitempty"`
+	RecoveryCode   string `json:"recoveryCode,omitempty"`
+}
@@ -2,17 +2,39 @@ package request
 
 import "time"
 
+const (
+	/* RoleUser is the role of the registered users */
+	RoleUser = "user"
+
+	/* RoleModerator is the role of the users that moderate the content */
+	RoleModerator = "moderator"
+
+	/* RoleAdmin is the role of the users that operate the service */
+	RoleAdmin = "admin"
+)
+
+/* Roles are the roles that can be assigned to an user */
+var Roles = []string{RoleUser, RoleModerator, RoleAdmin}
+
 /* User request */
 type User struct {
-	Id        string    `json:"id"`
-	Name      string    `json:"name,omitempty"`
-	LastName  string    `json:"lastName,omitempty"`
-	BirthDate time.Time `json:"birthDate,omitempty"`
-	Email     string    `json:"email,omitempty"`
-	Password  string    `json:"password,omitempty"`
-	Avatar    string    `json:"avatar,omitempty"`
-	Banner    string    `json:"banner,omitempty"`
-	Biography string    `json:"biography,omitempty"`
-	Location  string    `json:"location,omitempty"`
-	WebSite   string    `json:"webSite,omitempty"`
+	Id              string    `json:"id"`
+	Name            string    `json:"name,omitempty"`
+	LastName        string    `json:"lastName,omitempty"`
+	BirthDate       time.Time `json:"birthDate,omitempty"`
+	Email           string    `json:"email,omitempty"`
+	Handle          string    `json:"handle,omitempty"`
+	Password        string    `json:"password,omitempty"`
+	Avatar          string    `json:"avatar,omitempty"`
+	Banner          string    `json:"banner,omitempty"`
+	Biography       string    `json:"biography,omitempty"`
+	Location        string    `json:"location,omitempty"`
+	WebSite         string    `json:"webSite,omitempty"`
+	Verified        bool      `json:"verified,omitempty"`
+	TokensRevokedAt time.Time `json:"-"`
+	TotpSecret      string    `json:"-"`
+	TotpEnabled     bool      `json:"totpEnabled,omitempty"`
+	TotpLastCounter int64     `json:"-"`
+	Role            string    `json:"role,omitempty"`
+	DeleteAt        time.Time `json:"-"`
 }
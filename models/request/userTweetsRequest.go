@@ -8,8 +8,22 @@ type UserTweet struct {
 	UserId         string `json:"userId,omitempty"`
 	UserRelationId string `json:"userRelationId,omitempty"`
 	Tweet          struct {
-		Id      string    `json:"id,omitempty"`
-		Message string    `json:"message,omitempty"`
-		Date    time.Time `json:"date,omitempty"`
+		Id             string     `json:"id,omitempty"`
+		Message        string     `json:"message,omitempty"`
+		Date           time.Time  `json:"date,omitempty"`
+		InReplyTo      string     `json:"inReplyTo,omitempty"`
+		ConversationId string     `json:"conversationId,omitempty"`
+		ReplyCount     int64      `json:"replyCount"`
+		RetweetOf      string     `json:"retweetOf,omitempty"`
+		QuotedTweetId  string     `json:"quotedTweetId,omitempty"`
+		RetweetCount   int64      `json:"retweetCount"`
+		QuoteCount     int64      `json:"quoteCount"`
+		LikeCount      int64      `json:"likeCount"`
+		LikedByMe      bool       `json:"likedByMe"`
+		Edited         bool       `json:"edited"`
+		EditedAt       *time.Time `json:"editedAt,omitempty"`
+		Media          []string   `json:"media,omitempty"`
+		Original       *Tweet     `json:"original,omitempty"`
+		Active         bool       `json:"-"`
 	}
 }
@@ -0,0 +1,9 @@
+package response
+
+import mr "models/request"
+
+/* TweetThread is a tweet with its replies in a conversation */
+type TweetThread struct {
+	*mr.Tweet
+	Replies []*TweetThread `json:"replies"`
+}
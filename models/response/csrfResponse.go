@@ -0,0 +1,6 @@
+package response
+
+/* CsrfResponse is the response model for the CSRF token endpoint */
+type CsrfResponse struct {
+	CsrfToken string `json:"csrfToken"`
+}
@@ -0,0 +1,9 @@
+package response
+
+import mr "models/request"
+
+/* DraftsResponse is the response model for the GetDrafts endpoint */
+type DraftsResponse struct {
+	Drafts []*mr.Draft `json:"drafts"`
+	Total  int64       `json:"total"`
+}
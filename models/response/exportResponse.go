@@ -0,0 +1,14 @@
+package response
+
+import "time"
+
+/* ExportResponse is the response model for the personal data export endpoints */
+type ExportResponse struct {
+	Id                string    `json:"id"`
+	Status            string    `json:"status"`
+	Error             string    `json:"error,omitempty"`
+	CreatedAt         time.Time `json:"createdAt"`
+	ExpiresAt         time.Time `json:"expiresAt"`
+	DownloadUrl       string    `json:"downloadUrl,omitempty"`
+	DownloadExpiresAt time.Time `json:"downloadExpiresAt,omitempty"`
+}
@@ -0,0 +1,18 @@
+package response
+
+/* Jwk is a public JSON Web Key */
+type Jwk struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+/* JwksResponse is the response model for the JWKS endpoint */
+type JwksResponse struct {
+	Keys []Jwk `json:"keys"`
+}
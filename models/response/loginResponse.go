@@ -2,5 +2,7 @@ package response
 
 /* LoginResponse is the response model for the login endpoint */
 type LoginResponse struct {
-	Token string `json:"token,omitempty"`
+	Token          string `json:"token,omitempty"`
+	RefreshToken   string `json:"refreshToken,omitempty"`
+	ChallengeToken string `json:"challengeToken,omitempty"`
 }
@@ -0,0 +1,9 @@
+package response
+
+import mr "models/request"
+
+/* ScheduledTweetsResponse is the response model for the GetScheduledTweets endpoint */
+type ScheduledTweetsResponse struct {
+	Tweets []*mr.ScheduledTweet `json:"tweets"`
+	Total  int64                `json:"total"`
+}
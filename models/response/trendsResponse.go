@@ -0,0 +1,8 @@
+package response
+
+import mr "models/request"
+
+/* TrendsResponse is the response model for the GetTrends endpoint */
+type TrendsResponse struct {
+	Trends []*mr.Trend `json:"trends"`
+}
@@ -0,0 +1,9 @@
+package response
+
+import mr "models/request"
+
+/* TweetHistoryResponse is the response model for the GetHistory endpoint */
+type TweetHistoryResponse struct {
+	Tweet     *mr.Tweet           `json:"tweet"`
+	Revisions []*mr.TweetRevision `json:"revisions"`
+}
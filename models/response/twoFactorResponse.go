@@ -0,0 +1,12 @@
+package response
+
+/* TotpEnrollResponse is the response model for the two-factor authentication enrolment endpoint */
+type TotpEnrollResponse struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+}
+
+/* RecoveryCodesResponse is the response model for the two-factor authentication confirmation endpoint */
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
@@ -0,0 +1,29 @@
+package admin
+
+import (
+	"controllers/admin"
+	"helpers"
+	"middlewares"
+	mr "models/request"
+
+	"github.com/gorilla/mux"
+)
+
+/* UnlockLogin allows an administrator to unlock an account or an IP address */
+func UnlockLogin(router *mux.Router) {
+	router.HandleFunc("/admin/login/lockout", helpers.MultipleMiddleware(admin.UnlockLogin,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateJWT,
+		middlewares.RequireRoles(mr.RoleAdmin))).Methods("DELETE")
+}
+
+/* SetRole allows an administrator to set the role of an user */
+func SetRole(router *mux.Router) {
+	router.HandleFunc("/admin/user/role", helpers.MultipleMiddleware(admin.SetRole,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateJWT,
+		middlewares.RequireRoles(mr.RoleAdmin),
+		middlewares.ValidateQueryId)).Methods("PUT")
+}
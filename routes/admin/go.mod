@@ -0,0 +1,3 @@
+module routes/admin
+
+go 1.19
@@ -0,0 +1,3 @@
+module routes/keys
+
+go 1.19
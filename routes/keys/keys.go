@@ -0,0 +1,12 @@
+package keys
+
+import (
+	"controllers/keys"
+
+	"github.com/gorilla/mux"
+)
+
+/* GetJwks publishes the public keys used to verify the tokens */
+func GetJwks(router *mux.Router) {
+	router.HandleFunc("/.well-known/jwks.json", keys.GetJwks).Methods("GET")
+}
@@ -4,6 +4,7 @@ import (
 	"controllers/relations"
 	"helpers"
 	"middlewares"
+	mr "models/request"
 
 	"github.com/gorilla/mux"
 )
@@ -12,7 +13,9 @@ import (
 func Insert(router *mux.Router) {
 	router.HandleFunc("/relation", helpers.MultipleMiddleware(relations.Create,
 		middlewares.CheckDB,
-		middlewares.ValidateJWT,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateToken(mr.ScopeRelationsWrite),
+		middlewares.ValidateVerifiedEmail,
 		middlewares.ValidateQueryId)).Methods("POST")
 }
 
@@ -20,7 +23,8 @@ func Insert(router *mux.Router) {
 func Delete(router *mux.Router) {
 	router.HandleFunc("/relation", helpers.MultipleMiddleware(relations.Delete,
 		middlewares.CheckDB,
-		middlewares.ValidateJWT,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateToken(mr.ScopeRelationsWrite),
 		middlewares.ValidateQueryId)).Methods("DELETE")
 }
 
@@ -28,7 +32,7 @@ func Delete(router *mux.Router) {
 func IsRelation(router *mux.Router) {
 	router.HandleFunc("/relation", helpers.MultipleMiddleware(relations.IsRelation,
 		middlewares.CheckDB,
-		middlewares.ValidateJWT,
+		middlewares.ValidateToken(mr.ScopeRelationsRead),
 		middlewares.ValidateQueryId)).Methods("GET")
 }
 
@@ -36,7 +40,7 @@ func IsRelation(router *mux.Router) {
 func GetUsers(router *mux.Router) {
 	router.HandleFunc("/relation/users", helpers.MultipleMiddleware(relations.GetUsers,
 		middlewares.CheckDB,
-		middlewares.ValidateJWT,
+		middlewares.ValidateToken(mr.ScopeRelationsRead),
 		middlewares.ValidatePageLimit)).Methods("GET")
 }
 
@@ -44,6 +48,6 @@ func GetUsers(router *mux.Router) {
 func GetFollowingTweets(router *mux.Router) {
 	router.HandleFunc("/relation/tweets", helpers.MultipleMiddleware(relations.GetFollowingTweets,
 		middlewares.CheckDB,
-		middlewares.ValidateJWT,
+		middlewares.ValidateToken(mr.ScopeTweetsRead),
 		middlewares.ValidatePageLimit)).Methods("GET")
 }
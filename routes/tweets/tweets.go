@@ -4,6 +4,7 @@ import (
 	"controllers/tweets"
 	"helpers"
 	"middlewares"
+	mr "models/request"
 
 	"github.com/gorilla/mux"
 )
@@ -12,22 +13,239 @@ import (
 func Insert(router *mux.Router) {
 	router.HandleFunc("/tweet", helpers.MultipleMiddleware(tweets.Insert,
 		middlewares.CheckDB,
-		middlewares.ValidateJWT)).Methods("POST")
+		middlewares.ValidateCSRF,
+		middlewares.ValidateToken(mr.ScopeTweetsWrite),
+		middlewares.ValidateVerifiedEmail)).Methods("POST")
+}
+
+/* Modify allows to edit a tweet */
+func Modify(router *mux.Router) {
+	router.HandleFunc("/tweet", helpers.MultipleMiddleware(tweets.Modify,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateToken(mr.ScopeTweetsWrite),
+		middlewares.ValidateQueryId)).Methods("PUT")
+}
+
+/* GetHistory gets the revisions of an edited tweet */
+func GetHistory(router *mux.Router) {
+	router.HandleFunc("/tweet/history", helpers.MultipleMiddleware(tweets.GetHistory,
+		middlewares.CheckDB,
+		middlewares.ValidateToken(mr.ScopeTweetsRead),
+		middlewares.ValidateQueryId)).Methods("GET")
+}
+
+/* GetScheduledTweets gets the tweets scheduled by the user */
+func GetScheduledTweets(router *mux.Router) {
+	router.HandleFunc("/tweet/scheduled", helpers.MultipleMiddleware(tweets.GetScheduledTweets,
+		middlewares.CheckDB,
+		middlewares.ValidateToken(mr.ScopeTweetsRead),
+		middlewares.ValidatePageLimit)).Methods("GET")
+}
+
+/* DeleteScheduledTweet allows to cancel a scheduled tweet */
+func DeleteScheduledTweet(router *mux.Router) {
+	router.HandleFunc("/tweet/scheduled", helpers.MultipleMiddleware(tweets.DeleteScheduledTweet,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateToken(mr.ScopeTweetsWrite),
+		middlewares.ValidateQueryId)).Methods("DELETE")
+}
+
+/* InsertDraft allows to save a tweet without publishing it */
+func InsertDraft(router *mux.Router) {
+	router.HandleFunc("/tweet/drafts", helpers.MultipleMiddleware(tweets.InsertDraft,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateToken(mr.ScopeTweetsWrite))).Methods("POST")
+}
+
+/* GetDrafts gets the drafts of the user */
+func GetDrafts(router *mux.Router) {
+	router.HandleFunc("/tweet/drafts", helpers.MultipleMiddleware(tweets.GetDrafts,
+		middlewares.CheckDB,
+		middlewares.ValidateToken(mr.ScopeTweetsRead),
+		middlewares.ValidatePageLimit)).Methods("GET")
+}
+
+/* ModifyDraft allows to change a draft */
+func ModifyDraft(router *mux.Router) {
+	router.HandleFunc("/tweet/drafts", helpers.MultipleMiddleware(tweets.ModifyDraft,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateToken(mr.ScopeTweetsWrite),
+		middlewares.ValidateQueryId)).Methods("PUT")
+}
+
+/* DeleteDraft allows to delete a draft */
+func DeleteDraft(router *mux.Router) {
+	router.HandleFunc("/tweet/drafts", helpers.MultipleMiddleware(tweets.DeleteDraft,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateToken(mr.ScopeTweetsWrite),
+		middlewares.ValidateQueryId)).Methods("DELETE")
+}
+
+/* PublishDraft allows to publish a draft as a tweet */
+func PublishDraft(router *mux.Router) {
+	router.HandleFunc("/tweet/drafts/publish", helpers.MultipleMiddleware(tweets.PublishDraft,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateToken(mr.ScopeTweetsWrite),
+		middlewares.ValidateVerifiedEmail,
+		middlewares.ValidateQueryId)).Methods("POST")
+}
+
+/* GetDraftMedia gets an image of a draft */
+func GetDraftMedia(router *mux.Router) {
+	router.HandleFunc("/tweet/drafts/media", helpers.MultipleMiddleware(tweets.GetDraftMedia,
+		middlewares.CheckDB,
+		middlewares.ValidateToken(mr.ScopeTweetsRead),
+		middlewares.ValidateQueryId)).Methods("GET")
+}
+
+/* GetMedia gets an image of a tweet */
+func GetMedia(router *mux.Router) {
+	router.HandleFunc("/tweet/media", helpers.MultipleMiddleware(tweets.GetMedia,
+		middlewares.CheckDB,
+		middlewares.ValidateToken(mr.ScopeTweetsRead),
+		middlewares.ValidateQueryId)).Methods("GET")
+}
+
+/* Retweet allows to retweet a tweet */
+func Retweet(router *mux.Router) {
+	router.HandleFunc("/tweet/retweet", helpers.MultipleMiddleware(tweets.Retweet,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateToken(mr.ScopeTweetsWrite),
+		middlewares.ValidateVerifiedEmail,
+		middlewares.ValidateQueryId)).Methods("POST")
+}
+
+/* DeleteRetweet undoes a retweet */
+func DeleteRetweet(router *mux.Router) {
+	router.HandleFunc("/tweet/retweet", helpers.MultipleMiddleware(tweets.DeleteRetweet,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateToken(mr.ScopeTweetsWrite),
+		middlewares.ValidateQueryId)).Methods("DELETE")
+}
+
+/* Like allows to like a tweet */
+func Like(router *mux.Router) {
+	router.HandleFunc("/tweet/like", helpers.MultipleMiddleware(tweets.Like,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateToken(mr.ScopeTweetsWrite),
+		middlewares.ValidateQueryId)).Methods("POST")
+}
+
+/* Unlike undoes a like */
+func Unlike(router *mux.Router) {
+	router.HandleFunc("/tweet/like", helpers.MultipleMiddleware(tweets.Unlike,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateToken(mr.ScopeTweetsWrite),
+		middlewares.ValidateQueryId)).Methods("DELETE")
+}
+
+/* GetLikedTweets gets the tweets liked by an user */
+func GetLikedTweets(router *mux.Router) {
+	router.HandleFunc("/user/likes", helpers.MultipleMiddleware(tweets.GetLikedTweets,
+		middlewares.CheckDB,
+		middlewares.ValidateToken(mr.ScopeTweetsRead),
+		middlewares.ValidateQueryId,
+		middlewares.ValidatePageLimit)).Methods("GET")
+}
+
+/* Bookmark allows to bookmark a tweet */
+func Bookmark(router *mux.Router) {
+	router.HandleFunc("/tweet/bookmark", helpers.MultipleMiddleware(tweets.Bookmark,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateToken(mr.ScopeTweetsWrite),
+		middlewares.ValidateQueryId)).Methods("POST")
+}
+
+/* DeleteBookmark removes a tweet from the bookmarks */
+func DeleteBookmark(router *mux.Router) {
+	router.HandleFunc("/tweet/bookmark", helpers.MultipleMiddleware(tweets.DeleteBookmark,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateToken(mr.ScopeTweetsWrite),
+		middlewares.ValidateQueryId)).Methods("DELETE")
+}
+
+/* GetBookmarkedTweets gets the tweets bookmarked by the user */
+func GetBookmarkedTweets(router *mux.Router) {
+	router.HandleFunc("/tweet/bookmarks", helpers.MultipleMiddleware(tweets.GetBookmarkedTweets,
+		middlewares.CheckDB,
+		middlewares.ValidateToken(mr.ScopeTweetsRead),
+		middlewares.ValidatePageLimit)).Methods("GET")
 }
 
 /* GetTweets gets an user's tweets */
 func GetTweets(router *mux.Router) {
 	router.HandleFunc("/tweet", helpers.MultipleMiddleware(tweets.GetTweets,
 		middlewares.CheckDB,
-		middlewares.ValidateJWT,
+		middlewares.ValidateToken(mr.ScopeTweetsRead),
 		middlewares.ValidateQueryId,
 		middlewares.ValidatePageLimit)).Methods("GET")
 }
 
+/* GetHashtagTweets gets the tweets with a hashtag */
+func GetHashtagTweets(router *mux.Router) {
+	router.HandleFunc("/tweet/hashtag", helpers.MultipleMiddleware(tweets.GetHashtagTweets,
+		middlewares.CheckDB,
+		middlewares.ValidateToken(mr.ScopeTweetsRead),
+		middlewares.ValidatePageLimit)).Methods("GET")
+}
+
+/* SearchTweets searches the tweets by their message */
+func SearchTweets(router *mux.Router) {
+	router.HandleFunc("/tweet/search", helpers.MultipleMiddleware(tweets.SearchTweets,
+		middlewares.CheckDB,
+		middlewares.ValidateToken(mr.ScopeTweetsRead),
+		middlewares.ValidatePageLimit)).Methods("GET")
+}
+
+/* GetMentionTweets gets the tweets that mention the user */
+func GetMentionTweets(router *mux.Router) {
+	router.HandleFunc("/tweet/mentions", helpers.MultipleMiddleware(tweets.GetMentionTweets,
+		middlewares.CheckDB,
+		middlewares.ValidateToken(mr.ScopeTweetsRead),
+		middlewares.ValidatePageLimit)).Methods("GET")
+}
+
+/* GetTrends gets the trending hashtags */
+func GetTrends(router *mux.Router) {
+	router.HandleFunc("/trends", helpers.MultipleMiddleware(tweets.GetTrends,
+		middlewares.CheckDB,
+		middlewares.ValidateToken(mr.ScopeTweetsRead))).Methods("GET")
+}
+
+/* GetReplies gets the direct replies to a tweet */
+func GetReplies(router *mux.Router) {
+	router.HandleFunc("/tweet/replies", helpers.MultipleMiddleware(tweets.GetReplies,
+		middlewares.CheckDB,
+		middlewares.ValidateToken(mr.ScopeTweetsRead),
+		middlewares.ValidateQueryId,
+		middlewares.ValidatePageLimit)).Methods("GET")
+}
+
+/* GetConversation gets the thread of a tweet */
+func GetConversation(router *mux.Router) {
+	router.HandleFunc("/tweet/conversation", helpers.MultipleMiddleware(tweets.GetConversation,
+		middlewares.CheckDB,
+		middlewares.ValidateToken(mr.ScopeTweetsRead),
+		middlewares.ValidateQueryId)).Methods("GET")
+}
+
 /* Delete deletes an user's tweet */
 func Delete(router *mux.Router) {
 	router.HandleFunc("/tweet", helpers.MultipleMiddleware(tweets.Delete,
 		middlewares.CheckDB,
-		middlewares.ValidateJWT,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateToken(mr.ScopeTweetsWrite),
 		middlewares.ValidateQueryId)).Methods("DELETE")
 }
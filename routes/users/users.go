@@ -4,52 +4,196 @@ import (
 	"controllers/users"
 	"helpers"
 	"middlewares"
+	mr "models/request"
 
 	"github.com/gorilla/mux"
 )
 
 /* Insert allows to create an user */
 func Insert(router *mux.Router) {
-	router.HandleFunc("/user", helpers.MultipleMiddleware(users.Insert, middlewares.CheckDB, middlewares.ValidateEmail)).Methods("POST")
+	router.HandleFunc("/user", helpers.MultipleMiddleware(users.Insert, middlewares.CheckDB, middlewares.ValidateCSRF, middlewares.ValidateEmail)).Methods("POST")
 }
 
 /* Login permits an user to login in the service */
 func Login(router *mux.Router) {
-	router.HandleFunc("/user/login", helpers.MultipleMiddleware(users.Login, middlewares.CheckDB, middlewares.ValidateEmail)).Methods("POST")
+	router.HandleFunc("/user/login", helpers.MultipleMiddleware(users.Login, middlewares.CheckDB, middlewares.ValidateCSRF, middlewares.ValidateEmail)).Methods("POST")
+}
+
+/* LoginTwoFactor completes the login with a two-factor code */
+func LoginTwoFactor(router *mux.Router) {
+	router.HandleFunc("/user/login/2fa", helpers.MultipleMiddleware(users.LoginTwoFactor, middlewares.CheckDB, middlewares.ValidateCSRF)).Methods("POST")
+}
+
+/* RefreshToken exchanges a refresh token for a new pair of tokens */
+func RefreshToken(router *mux.Router) {
+	router.HandleFunc("/user/token/refresh", helpers.MultipleMiddleware(users.RefreshToken, middlewares.CheckDB, middlewares.ValidateCSRF)).Methods("POST")
+}
+
+/* Logout revokes the user's tokens */
+func Logout(router *mux.Router) {
+	router.HandleFunc("/user/logout", helpers.MultipleMiddleware(users.Logout, middlewares.CheckDB, middlewares.ValidateCSRF, middlewares.ValidateJWT)).Methods("POST")
+}
+
+/* VerifyEmail verifies the user's email address */
+func VerifyEmail(router *mux.Router) {
+	router.HandleFunc("/user/verify", helpers.MultipleMiddleware(users.VerifyEmail, middlewares.CheckDB)).Methods("GET")
+}
+
+/* ResendVerification sends a new verification email */
+func ResendVerification(router *mux.Router) {
+	router.HandleFunc("/user/verify/resend", helpers.MultipleMiddleware(users.ResendVerification,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateJWT)).Methods("POST")
+}
+
+/* ForgotPassword sends an email to reset the password */
+func ForgotPassword(router *mux.Router) {
+	router.HandleFunc("/user/password/forgot", helpers.MultipleMiddleware(users.ForgotPassword, middlewares.CheckDB, middlewares.ValidateCSRF, middlewares.ValidateEmail)).Methods("POST")
+}
+
+/* ResetPassword sets a new password using a reset token */
+func ResetPassword(router *mux.Router) {
+	router.HandleFunc("/user/password/reset", helpers.MultipleMiddleware(users.ResetPassword, middlewares.CheckDB, middlewares.ValidateCSRF)).Methods("POST")
+}
+
+/* ChangePassword changes the user's password */
+func ChangePassword(router *mux.Router) {
+	router.HandleFunc("/user/password", helpers.MultipleMiddleware(users.ChangePassword,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateJWT)).Methods("PUT")
+}
+
+/* DeleteAccount deletes the user's account */
+func DeleteAccount(router *mux.Router) {
+	router.HandleFunc("/user", helpers.MultipleMiddleware(users.DeleteAccount,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateJWT)).Methods("DELETE")
+}
+
+/* ExportData starts the export of the user's personal data */
+func ExportData(router *mux.Router) {
+	router.HandleFunc("/user/export", helpers.MultipleMiddleware(users.ExportData,
+		middlewares.CheckDB,
+		middlewares.ValidateJWT)).Methods("GET")
+}
+
+/* GetExport gets the status of an export */
+func GetExport(router *mux.Router) {
+	router.HandleFunc("/user/export/{id}", helpers.MultipleMiddleware(users.GetExport,
+		middlewares.CheckDB,
+		middlewares.ValidateJWT)).Methods("GET")
+}
+
+/* DownloadExport downloads the archive of an export */
+func DownloadExport(router *mux.Router) {
+	router.HandleFunc("/user/export/{id}/download", users.DownloadExport).Methods("GET")
+}
+
+/* EnrollTotp starts the enrolment of the two-factor authentication */
+func EnrollTotp(router *mux.Router) {
+	router.HandleFunc("/user/2fa/enroll", helpers.MultipleMiddleware(users.EnrollTotp,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateJWT)).Methods("POST")
+}
+
+/* ConfirmTotp enables the two-factor authentication */
+func ConfirmTotp(router *mux.Router) {
+	router.HandleFunc("/user/2fa/confirm", helpers.MultipleMiddleware(users.ConfirmTotp,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateJWT)).Methods("POST")
+}
+
+/* DisableTotp disables the two-factor authentication */
+func DisableTotp(router *mux.Router) {
+	router.HandleFunc("/user/2fa", helpers.MultipleMiddleware(users.DisableTotp,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateJWT)).Methods("DELETE")
+}
+
+/* CreateAccessToken creates a personal access token */
+func CreateAccessToken(router *mux.Router) {
+	router.HandleFunc("/user/tokens", helpers.MultipleMiddleware(users.CreateAccessToken,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateJWT)).Methods("POST")
+}
+
+/* GetAccessTokens gets the user's personal access tokens */
+func GetAccessTokens(router *mux.Router) {
+	router.HandleFunc("/user/tokens", helpers.MultipleMiddleware(users.GetAccessTokens,
+		middlewares.CheckDB,
+		middlewares.ValidateJWT)).Methods("GET")
+}
+
+/* DeleteAccessToken revokes a personal access token */
+func DeleteAccessToken(router *mux.Router) {
+	router.HandleFunc("/user/tokens", helpers.MultipleMiddleware(users.DeleteAccessToken,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateJWT,
+		middlewares.ValidateQueryId)).Methods("DELETE")
+}
+
+/* GetSessions gets the user's active sessions */
+func GetSessions(router *mux.Router) {
+	router.HandleFunc("/user/sessions", helpers.MultipleMiddleware(users.GetSessions,
+		middlewares.CheckDB,
+		middlewares.ValidateJWT)).Methods("GET")
+}
+
+/* DeleteSession revokes a session */
+func DeleteSession(router *mux.Router) {
+	router.HandleFunc("/user/sessions/{id}", helpers.MultipleMiddleware(users.DeleteSession,
+		middlewares.CheckDB,
+		middlewares.ValidateCSRF,
+		middlewares.ValidateJWT)).Methods("DELETE")
+}
+
+/* GetCsrfToken issues a CSRF token for the browser clients */
+func GetCsrfToken(router *mux.Router) {
+	router.HandleFunc("/user/csrf", users.GetCsrfToken).Methods("GET")
 }
 
 /* GetProfile gets an user profile */
 func GetProfile(router *mux.Router) {
 	router.HandleFunc("/user/profile", helpers.MultipleMiddleware(users.GetProfile,
 		middlewares.CheckDB,
-		middlewares.ValidateJWT,
+		middlewares.ValidateToken(mr.ScopeProfileRead),
 		middlewares.ValidateQueryId)).Methods("GET")
 }
 
 /* Modify allows to modify a registry */
 func Modify(router *mux.Router) {
-	router.HandleFunc("/user", helpers.MultipleMiddleware(users.Modify, middlewares.CheckDB, middlewares.ValidateJWT)).Methods("PUT")
+	router.HandleFunc("/user", helpers.MultipleMiddleware(users.Modify, middlewares.CheckDB, middlewares.ValidateCSRF, middlewares.ValidateToken(mr.ScopeProfileWrite))).Methods("PUT")
 }
 
 /* Upload uploads an user's avatar */
 func UploadAvatar(router *mux.Router) {
 	router.HandleFunc("/user/avatar", helpers.MultipleMiddleware(users.UploadAvatar,
 		middlewares.CheckDB,
-		middlewares.ValidateJWT)).Methods("POST")
+		middlewares.ValidateCSRF,
+		middlewares.ValidateToken(mr.ScopeProfileWrite))).Methods("POST")
 }
 
 /* Upload uploads an user's avatar */
 func UploadBanner(router *mux.Router) {
 	router.HandleFunc("/user/banner", helpers.MultipleMiddleware(users.UploadBanner,
 		middlewares.CheckDB,
-		middlewares.ValidateJWT)).Methods("POST")
+		middlewares.ValidateCSRF,
+		middlewares.ValidateToken(mr.ScopeProfileWrite))).Methods("POST")
 }
 
 /* GetAvatar gets the user's avatar */
 func GetAvatar(router *mux.Router) {
 	router.HandleFunc("/user/avatar", helpers.MultipleMiddleware(users.GetAvatar,
 		middlewares.CheckDB,
-		middlewares.ValidateJWT,
+		middlewares.ValidateToken(mr.ScopeProfileRead),
 		middlewares.ValidateQueryId)).Methods("GET")
 }
 
@@ -57,6 +201,6 @@ func GetAvatar(router *mux.Router) {
 func GetBanner(router *mux.Router) {
 	router.HandleFunc("/user/banner", helpers.MultipleMiddleware(users.GetBanner,
 		middlewares.CheckDB,
-		middlewares.ValidateJWT,
+		middlewares.ValidateToken(mr.ScopeProfileRead),
 		middlewares.ValidateQueryId)).Methods("GET")
 }
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+
+	"db"
+	"helpers"
+	mr "models/request"
+)
+
+const batchSize = 100
+
+/* PublishDueTweets publishes the scheduled tweets whose publication date has been reached */
+func PublishDueTweets() error {
+	tweets, err := db.DbConn.GetDueScheduledTweets(time.Now(), batchSize)
+
+	if err != nil {
+		return err
+	}
+
+	for _, scheduled := range tweets {
+		tweet := mr.Tweet{
+			UserId:         scheduled.UserId,
+			Message:        scheduled.Message,
+			Date:           time.Now(),
+			Active:         true,
+			InReplyTo:      scheduled.InReplyTo,
+			ConversationId: scheduled.ConversationId,
+			QuotedTweetId:  scheduled.QuotedTweetId,
+			Media:          scheduled.Media,
+		}
+
+		// Another instance could have published or the user cancelled the tweet, then it is not published again
+		_, _, err = db.DbConn.PublishScheduledTweet(scheduled.Id, tweet)
+
+		// A failed tweet is retried in the next run, without stopping the rest
+		if err != nil {
+			log.Printf("Error publishing the scheduled tweet %s: %s", scheduled.Id, err.Error())
+		}
+	}
+
+	return nil
+}
+
+/* StartPublishJob publishes periodically the scheduled tweets that are due */
+func StartPublishJob() {
+	interval := helpers.GetDurationEnv("SCHEDULER_INTERVAL", 30*time.Second)
+
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	go func() {
+		for range time.Tick(interval) {
+			err := PublishDueTweets()
+
+			if err != nil {
+				log.Println("Error publishing the scheduled tweets: " + err.Error())
+			}
+		}
+	}()
+}
@@ -0,0 +1,26 @@
+package throttle
+
+import (
+	"time"
+
+	"db"
+	mr "models/request"
+)
+
+/* AttemptStoreDb counts the failed attempts in the DB, so they are shared by all the instances */
+type AttemptStoreDb struct{}
+
+/* GetLoginAttempt gets the failed login attempts counted for a key */
+func (s *AttemptStoreDb) GetLoginAttempt(key string) (mr.LoginAttempt, bool, error) {
+	return db.DbConn.GetLoginAttempt(key)
+}
+
+/* IncrementLoginAttempt adds a failed login attempt to a key and returns the updated counter */
+func (s *AttemptStoreDb) IncrementLoginAttempt(key string, expiresAt time.Time) (mr.LoginAttempt, error) {
+	return db.DbConn.IncrementLoginAttempt(key, expiresAt)
+}
+
+/* DeleteLoginAttempt resets the failed login attempts of a key */
+func (s *AttemptStoreDb) DeleteLoginAttempt(key string) error {
+	return db.DbConn.DeleteLoginAttempt(key)
+}
@@ -0,0 +1,86 @@
+package throttle
+
+import (
+	"sync"
+	"time"
+
+	mr "models/request"
+)
+
+/* AttemptStoreMemory counts the failed attempts in memory, so it is only valid for a single instance */
+type AttemptStoreMemory struct {
+	mutex    sync.Mutex
+	attempts map[string]*mr.LoginAttempt
+}
+
+/* NewAttemptStoreMemory creates a memory store that removes the expired counters periodically */
+func NewAttemptStoreMemory() *AttemptStoreMemory {
+	store := &AttemptStoreMemory{
+		attempts: make(map[string]*mr.LoginAttempt),
+	}
+
+	go func() {
+		for range time.Tick(time.Minute) {
+			store.deleteExpired()
+		}
+	}()
+
+	return store
+}
+
+/* GetLoginAttempt gets the failed login attempts counted for a key */
+func (s *AttemptStoreMemory) GetLoginAttempt(key string) (mr.LoginAttempt, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	attempt, isFound := s.attempts[key]
+
+	if !isFound || !attempt.ExpiresAt.After(time.Now()) {
+		return mr.LoginAttempt{}, false, nil
+	}
+
+	return *attempt, true, nil
+}
+
+/* IncrementLoginAttempt adds a failed login attempt to a key and returns the updated counter */
+func (s *AttemptStoreMemory) IncrementLoginAttempt(key string, expiresAt time.Time) (mr.LoginAttempt, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	attempt, isFound := s.attempts[key]
+
+	if !isFound || !attempt.ExpiresAt.After(now) {
+		attempt = &mr.LoginAttempt{Key: key}
+		s.attempts[key] = attempt
+	}
+
+	attempt.Failures++
+	attempt.LastFailureAt = now
+	attempt.ExpiresAt = expiresAt
+
+	return *attempt, nil
+}
+
+/* DeleteLoginAttempt resets the failed login attempts of a key */
+func (s *AttemptStoreMemory) DeleteLoginAttempt(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.attempts, key)
+
+	return nil
+}
+
+func (s *AttemptStoreMemory) deleteExpired() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+
+	for key, attempt := range s.attempts {
+		if !attempt.ExpiresAt.After(now) {
+			delete(s.attempts, key)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package throttle
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"helpers"
+	mr "models/request"
+)
+
+type AttemptStore interface {
+	GetLoginAttempt(key string) (mr.LoginAttempt, bool, error)
+	IncrementLoginAttempt(key string, expiresAt time.Time) (mr.LoginAttempt, error)
+	DeleteLoginAttempt(key string) error
+}
+
+/* Store is the store where the failed attempts are counted */
+var Store AttemptStore
+
+/* SetStore sets the store for the store type */
+func SetStore(storeType string) {
+	switch storeType {
+	case "", "Memory":
+		Store = NewAttemptStoreMemory()
+	case "Db":
+		Store = new(AttemptStoreDb)
+	default:
+		log.Fatal("No attempt store selected")
+	}
+}
+
+/* AccountKey returns the key used to count the failed attempts of an account */
+func AccountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+/* IpKey returns the key used to count the failed attempts of an IP address */
+func IpKey(ip string) string {
+	return "ip:" + ip
+}
+
+/* GetLockout returns how long the keys are still locked, zero if none of them is locked */
+func GetLockout(keys ...string) (time.Duration, error) {
+	var lockout time.Duration
+
+	now := time.Now()
+
+	for _, key := range keys {
+		attempt, isFound, err := Store.GetLoginAttempt(key)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if !isFound {
+			continue
+		}
+
+		remaining := attempt.LastFailureAt.Add(getLockoutDuration(key, attempt.Failures)).Sub(now)
+
+		if remaining > lockout {
+			lockout = remaining
+		}
+	}
+
+	return lockout, nil
+}
+
+/* RegisterFailure adds a failed attempt to each key */
+func RegisterFailure(keys ...string) error {
+	// The counters must live at least as long as the longest lockout
+	window := helpers.GetDurationEnv("LOGIN_ATTEMPTS_WINDOW", 24*time.Hour)
+	maxLockout := helpers.GetDurationEnv("LOGIN_LOCKOUT_MAX", time.Hour)
+
+	if maxLockout > window {
+		window = maxLockout
+	}
+
+	expiresAt := time.Now().Add(window)
+
+	for _, key := range keys {
+		_, err := Store.IncrementLoginAttempt(key, expiresAt)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/* Reset removes the failed attempts of each key */
+func Reset(keys ...string) error {
+	for _, key := range keys {
+		err := Store.DeleteLoginAttempt(key)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// region "Helpers"
+
+func getLockoutDuration(key string, failures int) time.Duration {
+	maxAttempts := getMaxAttempts(key)
+
+	if failures < maxAttempts {
+		return 0
+	}
+
+	lockout := helpers.GetDurationEnv("LOGIN_LOCKOUT", time.Minute)
+	maxLockout := helpers.GetDurationEnv("LOGIN_LOCKOUT_MAX", time.Hour)
+
+	// The lockout is doubled with each failed attempt over the threshold
+	for i := maxAttempts; i < failures && lockout < maxLockout; i++ {
+		lockout *= 2
+	}
+
+	if lockout > maxLockout {
+		lockout = maxLockout
+	}
+
+	return lockout
+}
+
+func getMaxAttempts(key string) int {
+	// Many users can share an IP address, so it has its own threshold
+	envName := "LOGIN_MAX_ATTEMPTS"
+	defaultValue := 5
+
+	if strings.HasPrefix(key, "ip:") {
+		envName = "LOGIN_MAX_IP_ATTEMPTS"
+		defaultValue = 20
+	}
+
+	maxAttempts, err := strconv.Atoi(os.Getenv(envName))
+
+	if err != nil || maxAttempts < 1 {
+		return defaultValue
+	}
+
+	return maxAttempts
+}
+
+// endregion
@@ -0,0 +1,3 @@
+module totp
+
+go 1.19
@@ -0,0 +1,95 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	digits = 6
+	period = 30
+
+	/* skew is the number of periods before and after the current one in which a code is accepted */
+	skew = 1
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+/* GenerateSecret generates a random base32 encoded secret */
+func GenerateSecret() (string, error) {
+	secret := make([]byte, 20)
+
+	_, err := rand.Read(secret)
+
+	if err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(secret), nil
+}
+
+/* GetURI returns the otpauth:// URI used by the authenticator apps to register the secret */
+func GetURI(secret string, issuer string, account string) string {
+	params := url.Values{}
+
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(digits))
+	params.Set("period", fmt.Sprint(period))
+
+	label := url.PathEscape(fmt.Sprintf("%s:%s", issuer, account))
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, params.Encode())
+}
+
+/* Validate checks a code against the secret at the specified time, the codes of the counters up to the last accepted one are rejected, it returns the counter of the code */
+func Validate(code string, secret string, t time.Time, lastCounter int64) (int64, bool) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimSpace(secret)))
+
+	if err != nil || len(code) != digits {
+		return 0, false
+	}
+
+	counter := t.Unix() / period
+
+	for i := int64(-skew); i <= skew; i++ {
+		// A code can only be used once (RFC 6238 section 5.2)
+		if counter+i <= lastCounter {
+			continue
+		}
+
+		expected := getCode(key, uint64(counter+i))
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return counter + i, true
+		}
+	}
+
+	return 0, false
+}
+
+/* getCode generates the code of a counter as specified in the RFC 4226 */
+func getCode(key []byte, counter uint64) string {
+	message := make([]byte, 8)
+
+	binary.BigEndian.PutUint64(message, counter)
+
+	mac := hmac.New(sha1.New, key)
+
+	mac.Write(message)
+
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", digits, value%1000000)
+}
@@ -0,0 +1,81 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+/* rfcSecret is the SHA1 seed of the RFC 6238 test vectors */
+var rfcSecret = encoding.EncodeToString([]byte("12345678901234567890"))
+
+/* TestValidateRfcVectors checks the last six digits of the RFC 6238 appendix B SHA1 codes */
+func TestValidateRfcVectors(t *testing.T) {
+	vectors := []struct {
+		seconds int64
+		code    string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, vector := range vectors {
+		counter, isValid := Validate(vector.code, rfcSecret, time.Unix(vector.seconds, 0), 0)
+
+		if !isValid {
+			t.Errorf("the code %s was rejected at %d", vector.code, vector.seconds)
+		}
+
+		if counter != vector.seconds/period {
+			t.Errorf("the counter of the code %s is %d, expected %d", vector.code, counter, vector.seconds/period)
+		}
+	}
+}
+
+/* TestValidateSkew checks that the codes of the adjacent periods are accepted and the rest are rejected */
+func TestValidateSkew(t *testing.T) {
+	at := time.Unix(1111111111, 0)
+
+	_, isValid := Validate("050471", rfcSecret, at.Add(period*time.Second), 0)
+
+	if !isValid {
+		t.Error("the code of the previous period was rejected")
+	}
+
+	_, isValid = Validate("050471", rfcSecret, at.Add(2*period*time.Second), 0)
+
+	if isValid {
+		t.Error("a code older than the skew was accepted")
+	}
+
+	_, isValid = Validate("000000", rfcSecret, at, 0)
+
+	if isValid {
+		t.Error("a wrong code was accepted")
+	}
+}
+
+/* TestValidateReplay checks that a code can not be used again once its counter has been accepted */
+func TestValidateReplay(t *testing.T) {
+	at := time.Unix(1111111111, 0)
+	counter, isValid := Validate("050471", rfcSecret, at, 0)
+
+	if !isValid {
+		t.Fatal("the code was rejected")
+	}
+
+	_, isValid = Validate("050471", rfcSecret, at, counter)
+
+	if isValid {
+		t.Error("the code was accepted again")
+	}
+
+	_, isValid = Validate("081804", rfcSecret, at, counter)
+
+	if isValid {
+		t.Error("a code of an earlier counter was accepted")
+	}
+}